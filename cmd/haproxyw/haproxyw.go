@@ -2,15 +2,14 @@ package main
 
 import (
 	"context"
-	_ "github.com/haproxytech/client-native/v2/runtime"
-	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/impl/dcs"
 	"github/mlyahmed.io/nominee/impl/haproxy"
 	"github/mlyahmed.io/nominee/pkg/logger"
 	"github/mlyahmed.io/nominee/pkg/runner"
 )
 
 func main() {
-	observer := etcd.NewObserver(etcd.NewConfigLoader())
+	observer := dcs.NewObserver(dcs.NewConfigLoader())
 	proxy := haproxy.NewHAProxy(haproxy.NewConfigLoader())
 	or := runner.NewObserverRunner()
 	if err := or.Run(context.Background(), observer, proxy); err != nil {
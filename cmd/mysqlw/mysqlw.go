@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/dcs"
+	"github/mlyahmed.io/nominee/impl/mysql"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/runner"
+)
+
+func main() {
+	dcsConfig := dcs.NewConfigLoader()
+	node := mysql.NewMySQL(mysql.NewConfigLoader())
+	elector, err := dcs.NewElector(dcsConfig, node)
+	if err != nil {
+		logger.G(context.TODO()).Fatalf("MySQLW: Failed to run: %v", err)
+	}
+	dcs.WarnUnsupported(dcsConfig, node)
+	rn := runner.NewElectorRunner()
+	if err := rn.Run(context.Background(), elector, node); err != nil {
+		logger.G(context.TODO()).Fatalf("MySQLW: Failed to run: %v", err)
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/dcs"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/impl/postgres"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const connectTimeout = 5 * time.Second
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, inspector *etcd.Inspector, out printer, args []string) error
+}
+
+type printer interface {
+	members(members []etcd.Member) error
+	member(member etcd.Member) error
+	change(change etcd.Change) error
+}
+
+var commands = map[string]command{
+	"list":        {"list the members of the cluster", list},
+	"leader":      {"print the leader of the cluster", leader},
+	"watch":       {"stream the membership and leadership changes", watch},
+	"switchover":  {"hand the leadership over to another member: switchover -to <node> [-wait <duration>]", switchover},
+	"pause":       {"stop promoting, demoting and stonithing the cluster, or only a member: pause [-node <node>]", pause},
+	"resume":      {"resume the cluster, or only a member: resume [-node <node>]", resume},
+	"edit-config": {"edit the Postgres parameters of the cluster in $EDITOR, or replace them: edit-config [-f <file>|-]", editConfig},
+}
+
+func main() {
+	flags := flag.NewFlagSet("nomineectl", flag.ExitOnError)
+	domain := flags.String("domain", "", "the domain, overrides NOMINEE_DOMAIN_NAME")
+	cluster := flags.String("cluster", "", "the cluster, overrides NOMINEE_CLUSTER_NAME")
+	endpoints := flags.String("endpoints", "", "the comma separated etcd endpoints, overrides NOMINEE_ETCD_ENDPOINTS")
+	output := flags.String("o", "table", "the output format: table or json")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: nomineectl [flags] <command> [command flags]\n\nCommands:\n")
+		for _, name := range []string{"list", "leader", "watch", "switchover", "pause", "resume", "edit-config"} {
+			fmt.Fprintf(flags.Output(), "  %-12s %s\n", name, commands[name].usage)
+		}
+		fmt.Fprintf(flags.Output(), "\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var out printer
+	switch *output {
+	case "table":
+		out = &tablePrinter{out: os.Stdout}
+	case "json":
+		out = &jsonPrinter{encoder: json.NewEncoder(os.Stdout)}
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	setEnv("NOMINEE_DOMAIN_NAME", *domain)
+	setEnv("NOMINEE_CLUSTER_NAME", *cluster)
+	setEnv("NOMINEE_ETCD_ENDPOINTS", *endpoints)
+	logrus.SetLevel(logrus.WarnLevel)
+
+	if err := run(cmd, out, flags.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "nomineectl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(cmd command, out printer, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	// The nodes of the other backends never read the keys written here, a pause would silently do nothing.
+	dcsConfig := dcs.NewConfigLoader()
+	dcsConfig.Load(ctx)
+	if backend := dcsConfig.GetSpec().Backend; backend != dcs.Etcd {
+		return fmt.Errorf("%s does not support %s, %s nor %s, only %s does", backend, dcs.Pause, dcs.Switchover, dcs.DynamicConfig, dcs.Etcd)
+	}
+
+	inspector := etcd.NewInspector(etcd.NewConfigLoader())
+	defer inspector.Cleanup()
+	connected := make(chan error, 1)
+	go func() { connected <- inspector.Connect(ctx) }()
+	select {
+	case err := <-connected:
+		if err != nil {
+			return err
+		}
+	case <-time.After(connectTimeout):
+		return fmt.Errorf("failed to connect to etcd within %v", connectTimeout)
+	}
+	return cmd.run(ctx, inspector, out, args)
+}
+
+func list(ctx context.Context, inspector *etcd.Inspector, out printer, _ []string) error {
+	members, err := inspector.Members(ctx)
+	if err != nil {
+		return err
+	}
+	return out.members(members)
+}
+
+func leader(ctx context.Context, inspector *etcd.Inspector, out printer, _ []string) error {
+	member, err := inspector.Leader(ctx)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return fmt.Errorf("no leader")
+	}
+	return out.member(*member)
+}
+
+func watch(ctx context.Context, inspector *etcd.Inspector, out printer, _ []string) error {
+	changes, err := inspector.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	for change := range changes {
+		if err := out.change(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func switchover(ctx context.Context, inspector *etcd.Inspector, out printer, args []string) error {
+	flags := flag.NewFlagSet("switchover", flag.ExitOnError)
+	to := flags.String("to", "", "the member to hand the leadership over to")
+	wait := flags.Duration("wait", time.Minute, "how long to wait for the candidate to lead, 0 to not wait")
+	_ = flags.Parse(args)
+	if *to == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	changes, err := inspector.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := inspector.Switchover(ctx, *to); err != nil {
+		return err
+	}
+	if *wait == 0 {
+		return nil
+	}
+
+	timeout := time.After(*wait)
+	for {
+		select {
+		case change, ok := <-changes:
+			if !ok {
+				return ctx.Err()
+			}
+			if change.Type == etcd.LeaderChanged && change.Member.Name == *to {
+				return out.member(change.Member)
+			}
+		case <-timeout:
+			return fmt.Errorf("%s does not lead after %v", *to, *wait)
+		}
+	}
+}
+
+func pause(ctx context.Context, inspector *etcd.Inspector, _ printer, args []string) error {
+	flags := flag.NewFlagSet("pause", flag.ExitOnError)
+	name := flags.String("node", "", "the member to pause, the whole cluster if not set")
+	_ = flags.Parse(args)
+	return inspector.Pause(ctx, *name)
+}
+
+func resume(ctx context.Context, inspector *etcd.Inspector, _ printer, args []string) error {
+	flags := flag.NewFlagSet("resume", flag.ExitOnError)
+	name := flags.String("node", "", "the member to resume, the whole cluster if not set")
+	_ = flags.Parse(args)
+	return inspector.Resume(ctx, *name)
+}
+
+func editConfig(ctx context.Context, inspector *etcd.Inspector, _ printer, args []string) error {
+	flags := flag.NewFlagSet("edit-config", flag.ExitOnError)
+	file := flags.String("f", "", "the file of the configuration, - for the standard input, $EDITOR if not set")
+	_ = flags.Parse(args)
+
+	current, revision, err := inspector.Config(ctx)
+	if err != nil {
+		return err
+	}
+	if current.Parameters == nil {
+		current.Parameters = node.Parameters{}
+	}
+	original, _ := json.MarshalIndent(current, "", "  ")
+
+	var edited []byte
+	switch *file {
+	case "":
+		edited, err = edit(append(original, '\n'))
+	case "-":
+		edited, err = ioutil.ReadAll(os.Stdin)
+	default:
+		edited, err = ioutil.ReadFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	config := etcd.DynamicConfig{}
+	decoder := json.NewDecoder(bytes.NewReader(edited))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return fmt.Errorf("invalid configuration: %v", err)
+	}
+	if err := postgres.CheckParameters(config.Parameters); err != nil {
+		return err
+	}
+	if updated, _ := json.MarshalIndent(config, "", "  "); bytes.Equal(updated, original) {
+		fmt.Println("no change")
+		return nil
+	}
+	return inspector.EditConfig(ctx, config, revision)
+}
+
+// edit opens the content in $EDITOR, vi by default, and returns it once saved.
+func edit(content []byte) ([]byte, error) {
+	file, err := ioutil.TempFile("", "nominee-config-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command("sh", "-c", editor+` "$0"`, file.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(file.Name())
+}
+
+type tablePrinter struct {
+	out io.Writer
+}
+
+func (p *tablePrinter) members(members []etcd.Member) error {
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tROLE\tADDRESS\tPORT\tELECTION KEY\tLEASE TTL\tPAUSED\tPENDING RESTART\tTAGS")
+	for _, m := range members {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%v\t%v\t%s\n", m.Name, m.Role, m.Address, m.Port, m.ElectionKey, ttl(m.LeaseTTL), m.Paused, m.PendingRestart, tags(m.Tags))
+	}
+	return w.Flush()
+}
+
+func (p *tablePrinter) member(member etcd.Member) error {
+	return p.members([]etcd.Member{member})
+}
+
+func (p *tablePrinter) change(change etcd.Change) error {
+	_, err := fmt.Fprintf(p.out, "%-7s %s (%s:%d) %s\n", change.Type, change.Member.Name, change.Member.Address, change.Member.Port, change.Member.ElectionKey)
+	return err
+}
+
+type jsonPrinter struct {
+	encoder *json.Encoder
+}
+
+func (p *jsonPrinter) members(members []etcd.Member) error {
+	return p.encoder.Encode(members)
+}
+
+func (p *jsonPrinter) member(member etcd.Member) error {
+	return p.encoder.Encode(member)
+}
+
+func (p *jsonPrinter) change(change etcd.Change) error {
+	return p.encoder.Encode(change)
+}
+
+func ttl(seconds int64) string {
+	if seconds < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+func tags(t node.Tags) string {
+	list := make([]string, 0)
+	if t.Priority > 0 {
+		list = append(list, fmt.Sprintf("priority=%d", t.Priority))
+	}
+	if t.NoFailover {
+		list = append(list, "nofailover")
+	}
+	if t.NoLoadBalance {
+		list = append(list, "noloadbalance")
+	}
+	if t.CloneFrom {
+		list = append(list, "clonefrom")
+	}
+	if len(list) == 0 {
+		return "-"
+	}
+	return strings.Join(list, ",")
+}
+
+func setEnv(key, value string) {
+	if value != "" {
+		_ = os.Setenv(key, value)
+	}
+}
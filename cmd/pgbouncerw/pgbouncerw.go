@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/dcs"
+	"github/mlyahmed.io/nominee/impl/pgbouncer"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/runner"
+)
+
+func main() {
+	observer := dcs.NewObserver(dcs.NewConfigLoader())
+	proxy := pgbouncer.NewPgBouncer(pgbouncer.NewConfigLoader())
+	or := runner.NewObserverRunner()
+	if err := or.Run(context.Background(), observer, proxy); err != nil {
+		logger.G(context.TODO()).Fatalf("PgBouncerW: Failed to run: %v", err)
+	}
+}
@@ -2,15 +2,20 @@ package main
 
 import (
 	"context"
-	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/impl/dcs"
 	"github/mlyahmed.io/nominee/impl/postgres"
 	"github/mlyahmed.io/nominee/pkg/logger"
 	"github/mlyahmed.io/nominee/pkg/runner"
 )
 
 func main() {
-	elector := etcd.NewElector(etcd.NewConfigLoader())
+	dcsConfig := dcs.NewConfigLoader()
 	node := postgres.NewPostgres(postgres.NewConfigLoader())
+	elector, err := dcs.NewElector(dcsConfig, node)
+	if err != nil {
+		logger.G(context.TODO()).Fatalf("PostgresW: Failed to run: %v", err)
+	}
+	dcs.WarnUnsupported(dcsConfig, node)
 	rn := runner.NewElectorRunner()
 	if err := rn.Run(context.Background(), elector, node); err != nil {
 		logger.G(context.TODO()).Fatalf("PostgresW: Failed to run: %v", err)
@@ -0,0 +1,58 @@
+package consul
+
+import (
+	"fmt"
+	"github.com/hashicorp/consul/api"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/metrics"
+	"github/mlyahmed.io/nominee/pkg/node"
+)
+
+// Consul ...
+type Consul struct {
+	*ConfigSpec
+	Connector  Connector
+	failBackFn func() error
+}
+
+var (
+	log *logrus.Entry
+)
+
+// NewConsul ...
+func NewConsul(cl ConfigLoader) *Consul {
+	return &Consul{
+		Connector:  NewDefaultConnector(),
+		ConfigSpec: cl.GetSpec(),
+		failBackFn: func() error { return nil },
+	}
+}
+
+// Cleanup ...
+func (consul *Consul) Cleanup() {
+	consul.Connector.Cleanup()
+}
+
+func (consul *Consul) listenToTheConnectorSession() {
+	go func() {
+		for { //TODO: retries limit
+			<-consul.Connector.Stop()
+			log.Infof("session closed. Try to reconnect...")
+			metrics.SessionReconnected("consul")
+			_ = consul.failBackFn()
+		}
+	}()
+}
+
+func (consul *Consul) toNodeSpec(pair *api.KVPair) node.Spec {
+	var value node.Spec
+	if pair != nil {
+		value, _ = node.Unmarshal(pair.Value)
+		value.ElectionKey = pair.Key
+	}
+	return value
+}
+
+func (consul *Consul) electionKey() string {
+	return fmt.Sprintf("nominee/domain/%s/cluster/%s", consul.Domain, consul.Cluster)
+}
@@ -0,0 +1,45 @@
+package consul
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/config"
+)
+
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	Address    string
+	Token      string
+	Datacenter string
+	SessionTTL string
+	Loaded     bool
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{BasicConfig: config.NewBasicConfig()}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	config.SetDefault("NOMINEE_CONSUL_SESSION_TTL", "10s")
+
+	conf.Address = config.GetStringOrPanic("NOMINEE_CONSUL_ADDRESS")
+	conf.Token = config.GetString("NOMINEE_CONSUL_TOKEN")
+	conf.Datacenter = config.GetString("NOMINEE_CONSUL_DATACENTER")
+	conf.SessionTTL = config.GetString("NOMINEE_CONSUL_SESSION_TTL")
+	conf.Loaded = true
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	if !conf.Loaded {
+		panic("config not loaded.")
+	}
+	return conf
+}
@@ -0,0 +1,56 @@
+package consul_test
+
+type configurationExample struct {
+	description string
+	cluster     string
+	domain      string
+	address     string
+	token       string
+	datacenter  string
+	sessionTTL  string
+}
+
+var validExamples = []configurationExample{
+	{
+		description: "minimum configuration",
+		cluster:     "nominee",
+		domain:      "postgres",
+		address:     "127.0.0.1:8500",
+	},
+	{
+		description: "full configuration",
+		cluster:     "cluster-002",
+		domain:      "foo",
+		address:     "https://consul.service.priv:8501",
+		token:       "2a7d9f1e-8e4c-4c8e-9b1a-3f5d0c6e7b21",
+		datacenter:  "dc1",
+		sessionTTL:  "15s",
+	},
+	{
+		description: "full configuration",
+		cluster:     "cluster-003",
+		domain:      "domain-007",
+		address:     "node1.consul.priv:8500",
+		token:       "configXXX",
+		datacenter:  "eu-west",
+		sessionTTL:  "30s",
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "the cluster name is missing",
+		domain:      "postgres",
+		address:     "127.0.0.1:8500",
+	},
+	{
+		description: "the domain name is missing",
+		cluster:     "nominee",
+		address:     "127.0.0.1:8500",
+	},
+	{
+		description: "the address is missing",
+		cluster:     "nominee",
+		domain:      "postgres",
+	},
+}
@@ -0,0 +1,82 @@
+package consul_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/consul"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"testing"
+)
+
+func TestConsulConfig_it_must_load_all_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := consul.NewConfigLoader()
+			loader.Load(context.TODO())
+			consulConfig := loader.GetSpec()
+			if consulConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, consulConfig.Cluster)
+			}
+
+			if consulConfig.Domain != example.domain {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, consulConfig.Domain)
+			}
+
+			if consulConfig.Address != example.address {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Address, expected <%s> but actual is <%s>", testutils.Failed, example.address, consulConfig.Address)
+			}
+
+			if consulConfig.Token != example.token {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Token, expected <%s> but actual is <%s>", testutils.Failed, example.token, consulConfig.Token)
+			}
+
+			if consulConfig.Datacenter != example.datacenter {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Datacenter, expected <%s> but actual is <%s>", testutils.Failed, example.datacenter, consulConfig.Datacenter)
+			}
+
+			expectedTTL := example.sessionTTL
+			if expectedTTL == "" {
+				expectedTTL = "10s"
+			}
+			if consulConfig.SessionTTL != expectedTTL {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.SessionTTL, expected <%s> but actual is <%s>", testutils.Failed, expectedTTL, consulConfig.SessionTTL)
+			}
+		})
+	}
+}
+
+func TestConsulConfig_it_must_panic_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			consulConfig := consul.NewConfigLoader()
+			consulConfig.Load(context.TODO())
+		})
+	}
+}
+
+func declareConfigurationExample(example configurationExample) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_CONSUL_ADDRESS", example.address)
+	_ = os.Setenv("NOMINEE_CONSUL_TOKEN", example.token)
+	_ = os.Setenv("NOMINEE_CONSUL_DATACENTER", example.datacenter)
+	_ = os.Setenv("NOMINEE_CONSUL_SESSION_TTL", example.sessionTTL)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_CONSUL_ADDRESS")
+	_ = os.Unsetenv("NOMINEE_CONSUL_TOKEN")
+	_ = os.Unsetenv("NOMINEE_CONSUL_DATACENTER")
+	_ = os.Unsetenv("NOMINEE_CONSUL_SESSION_TTL")
+}
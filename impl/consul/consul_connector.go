@@ -0,0 +1,99 @@
+package consul
+
+import (
+	"context"
+	"github.com/hashicorp/consul/api"
+	"github/mlyahmed.io/nominee/pkg/base"
+)
+
+// Client ...
+type Client interface {
+	// extracted from api.KV
+	List(prefix string, q *api.QueryOptions) (api.KVPairs, *api.QueryMeta, error)
+}
+
+// Election ...
+type Election interface {
+	// Campaign puts a value as eligible and blocks until it is elected
+	Campaign(ctx context.Context, val string) error
+
+	// Observe returns a channel that receives the leader each time it changes
+	Observe(ctx context.Context) <-chan api.KVPair
+}
+
+// Connector ...
+type Connector interface {
+	base.Cleaner
+	Connect(ctx context.Context, config *ConfigSpec) (Client, error)
+	NewElection(ctx context.Context, electionKey string) (Election, error)
+	Stop() base.DoneChan
+}
+
+// DefaultConnector ...
+type DefaultConnector struct {
+	client   *api.Client
+	session  string
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewDefaultConnector ...
+func NewDefaultConnector() *DefaultConnector {
+	return &DefaultConnector{doneChan: make(chan struct{})}
+}
+
+// Connect ...
+func (server *DefaultConnector) Connect(ctx context.Context, config *ConfigSpec) (Client, error) {
+	var err error
+	log.Infof("create new session. Address %s", config.Address)
+
+	if server.client == nil {
+		cfg := api.DefaultConfig()
+		cfg.Address = config.Address
+		cfg.Token = config.Token
+		cfg.Datacenter = config.Datacenter
+		if server.client, err = api.NewClient(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := &api.SessionEntry{TTL: config.SessionTTL, Behavior: api.SessionBehaviorDelete}
+	if server.session, _, err = server.client.Session().Create(entry, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	server.stopChan = make(chan struct{})
+	server.keepAlive(ctx, config.SessionTTL)
+	return server.client.KV(), nil
+}
+
+// NewElection ...
+func (server *DefaultConnector) NewElection(_ context.Context, electionKey string) (Election, error) {
+	return NewDefaultElection(server.client.KV(), server.session, electionKey), nil
+}
+
+// Stop ...
+func (server *DefaultConnector) Stop() base.DoneChan {
+	return server.stopChan
+}
+
+// Cleanup ...
+func (server *DefaultConnector) Cleanup() {
+	select {
+	case <-server.doneChan:
+	default:
+		close(server.doneChan)
+	}
+}
+
+func (server *DefaultConnector) keepAlive(ctx context.Context, ttl string) {
+	session, stop, done := server.session, server.stopChan, server.doneChan
+	go func() {
+		_ = server.client.Session().RenewPeriodic(ttl, session, (&api.WriteOptions{}).WithContext(ctx), done)
+		select {
+		case <-done: // Cleaned up, the session has been destroyed on purpose.
+		default:
+			close(stop)
+		}
+	}()
+}
@@ -0,0 +1,102 @@
+package consul
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+// DefaultElection implements Election on top of the Consul KV store. Each candidate acquires its own key
+// under the election prefix with its session, the one holding the oldest key is the leader.
+// It is the Consul counterpart of the etcd concurrency.Election.
+type DefaultElection struct {
+	kv      *api.KV
+	session string
+	prefix  string
+}
+
+// NewDefaultElection ...
+func NewDefaultElection(kv *api.KV, session, prefix string) *DefaultElection {
+	return &DefaultElection{kv: kv, session: session, prefix: prefix}
+}
+
+// Campaign ...
+func (e *DefaultElection) Campaign(ctx context.Context, val string) error {
+	key := fmt.Sprintf("%s/%s", e.prefix, e.session)
+	pair := &api.KVPair{Key: key, Value: []byte(val), Session: e.session}
+	acquired, _, err := e.kv.Acquire(pair, (&api.WriteOptions{}).WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !acquired {
+		return errors.Errorf("failed to acquire the key %s", key)
+	}
+
+	var index uint64
+	for {
+		leader, lastIndex, err := e.leader(ctx, index)
+		if err != nil {
+			return err
+		}
+
+		if leader != nil && leader.Key == key {
+			return nil
+		}
+		index = lastIndex
+	}
+}
+
+// Observe ...
+func (e *DefaultElection) Observe(ctx context.Context) <-chan api.KVPair {
+	observe := make(chan api.KVPair)
+	go func() {
+		defer close(observe)
+		var current *api.KVPair
+		var index uint64
+		for {
+			leader, lastIndex, err := e.leader(ctx, index)
+			if err != nil {
+				return
+			}
+			index = lastIndex
+
+			if leader == nil || (current != nil && current.Key == leader.Key && bytes.Equal(current.Value, leader.Value)) {
+				continue
+			}
+
+			current = leader
+			select {
+			case observe <- *leader:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return observe
+}
+
+func (e *DefaultElection) leader(ctx context.Context, index uint64) (*api.KVPair, uint64, error) {
+	pairs, meta, err := e.kv.List(e.prefix, (&api.QueryOptions{WaitIndex: index}).WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var leader *api.KVPair
+	for _, pair := range pairs {
+		if pair.Session == "" {
+			continue
+		}
+		if leader == nil || pair.CreateIndex < leader.CreateIndex {
+			leader = pair
+		}
+	}
+
+	lastIndex := meta.LastIndex
+	if lastIndex < index { // The index went backward, see https://www.consul.io/api-docs/features/blocking
+		lastIndex = 0
+	}
+	return leader, lastIndex, nil
+}
@@ -0,0 +1,79 @@
+package consul
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/node"
+)
+
+// Elector ...
+type Elector struct {
+	*Consul
+	*election.DefaultElector
+	election Election
+}
+
+// NewElector ...
+func NewElector(cl ConfigLoader) *Elector {
+	cl.Load(context.Background())
+	spec := cl.GetSpec()
+	log = logger.G(context.Background()).WithFields(logrus.Fields{"elector": "consul", "domain": spec.Domain, "cluster": spec.Cluster})
+	elector := Elector{Consul: NewConsul(cl)}
+	elector.failBackFn = func() error { return elector.connect(true) }
+	return &elector
+}
+
+// Run ...
+func (e *Elector) Run(n node.Node) error {
+	log = log.WithFields(logrus.Fields{"daemon": n.GetDaemonName(), "node": n.GetName()})
+	log.Infof("starting...")
+	e.DefaultElector = election.NewElector(n)
+
+	if err := e.connect(false); err != nil {
+		return err
+	}
+	e.listenToTheConnectorSession()
+	log.Infof("started.")
+	return nil
+}
+
+func (e *Elector) connect(reconnect bool) error {
+	if reconnect {
+		e.Reset()
+	}
+
+	if _, err := e.Connector.Connect(e.Ctx, e.ConfigSpec); err != nil {
+		return err
+	}
+
+	log.Infof("new election...")
+	e.election, _ = e.Connector.NewElection(e.Ctx, e.electionKey())
+
+	e.campaign()
+	e.observe()
+	log.Infof("session created.")
+	return nil
+}
+
+func (e *Elector) campaign() {
+	ctx, current := e.Ctx, e.election
+	go func() {
+		log.Infof("campaign as %v...", e.Managed.GetName())
+		if err := current.Campaign(ctx, e.Managed.GetSpec().Marshal()); err != nil && ctx.Err() == nil {
+			e.Stonith(context.TODO())
+		}
+	}()
+}
+
+func (e *Elector) observe() {
+	ctx, current := e.Ctx, e.election
+	go func() {
+		o := current.Observe(ctx)
+		for leader := range o {
+			spec := e.toNodeSpec(&leader)
+			_ = e.UpdateLeader(&spec)
+		}
+	}()
+}
@@ -0,0 +1,159 @@
+package consul_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/consul"
+	consulmock "github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func TestConsulElector_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			server.Stall()
+			election.TestElector(t, func() election.Elector {
+				return consul.NewElector(example.config(server))
+			})
+		})
+	}
+}
+
+func TestConsulElector_when_run_then_create_a_session(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			server.Stall()
+			elector := consul.NewElector(example.config(server))
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, &node.Spec{})); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+
+			if len(server.Sessions()) != 1 {
+				t.Fatalf("\t\t%s FAIL: ConsulElector, expected to create a session. But actually not.", testutils.Failed)
+			}
+		})
+	}
+}
+
+func TestConsulElector_must_campaign_for_leadership_under_the_election_key(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			server.Stall()
+			elector := consul.NewElector(example.config(server))
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+
+			electionKey := fmt.Sprintf("nominee/domain/%s/cluster/%s", example.domain, example.cluster)
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return len(server.Pairs(electionKey)) == 1
+			})
+
+			pair := server.Pairs(electionKey)[0]
+			if !strings.HasPrefix(pair.Key, electionKey+"/") || pair.Session == "" {
+				t.Fatalf("\t\t%s FAIL: ConsulElector, expected to hold a key under <%s> but actual is <%s>", testutils.Failed, electionKey, pair.Key)
+			}
+
+			if string(pair.Value) != example.nodeSpec.Marshal() {
+				t.Fatalf("\t\t%s FAIL: ConsulElector, expected campaign with value <%s> but actual is <%s>", testutils.Failed, example.nodeSpec.Marshal(), pair.Value)
+			}
+		})
+	}
+}
+
+func TestConsulElector_when_alone_then_lead(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			elector := consul.NewElector(example.config(server))
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return nod.LeadHits == 1 && nod.Leader.Name == example.nodeSpec.Name
+			})
+		})
+	}
+}
+
+func TestConsulElector_when_another_node_is_the_leader_then_follow_it(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			leader := mock.NewNode(t, &node.Spec{Name: "the-leader", Address: "10.0.0.1", Port: 5432})
+			leader.LeadFn = func(context.Context, node.Spec) error { return nil }
+			first := consul.NewElector(example.config(server))
+			defer first.Cleanup()
+			if err := first.Run(leader); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return leader.LeadHits == 1 })
+
+			follower := mock.NewNode(t, example.nodeSpec)
+			follower.FollowFn = func(context.Context, node.Spec) error { return nil }
+			second := consul.NewElector(example.config(server))
+			defer second.Cleanup()
+			if err := second.Run(follower); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return follower.FollowHits == 1 && follower.Leader.Name == "the-leader"
+			})
+		})
+	}
+}
+
+func TestConsulElector_when_the_session_expires_retry_to_connect(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			server.Stall()
+			elector := consul.NewElector(example.config(server))
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, error when RUN %v", testutils.Failed, err)
+			}
+
+			expired := server.Sessions()[0]
+			server.ExpireSession(expired)
+
+			electionKey := fmt.Sprintf("nominee/domain/%s/cluster/%s", example.domain, example.cluster)
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				sessions := server.Sessions()
+				pairs := server.Pairs(electionKey)
+				return len(sessions) == 1 && sessions[0] != expired && len(pairs) == 1 && pairs[0].Session == sessions[0]
+			})
+		})
+	}
+}
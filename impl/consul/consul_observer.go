@@ -0,0 +1,143 @@
+package consul
+
+import (
+	"context"
+	"github.com/hashicorp/consul/api"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/proxy"
+)
+
+// Observer ...
+type Observer struct {
+	*Consul
+	*election.BasicObserver
+	client   Client
+	election Election
+}
+
+// NewObserver ...
+func NewObserver(cl ConfigLoader) *Observer {
+	cl.Load(context.Background())
+	log = logrus.WithFields(logrus.Fields{"observer": "consul"})
+	o := Observer{Consul: NewConsul(cl)}
+	o.failBackFn = o.failBack
+	return &o
+}
+
+// Observe ...
+func (observer *Observer) Observe(proxy proxy.Proxy) error {
+	observer.BasicObserver = election.NewBasicObserver(proxy)
+
+	if err := observer.subscribe(); err != nil {
+		return err
+	}
+
+	observer.listenToTheConnectorSession()
+	nodes, index := observer.pushCurrentNodes()
+	observer.observeLeader()
+	observer.observeNodes(nodes, index)
+	return nil
+}
+
+// observeNodes turns the successive states of the election prefix into UpdateNodes/RemoveNodes calls,
+// the same way the etcd observer does from the PUT/DELETE events.
+func (observer *Observer) observeNodes(known map[string]*node.Spec, index uint64) {
+	ctx := observer.Ctx
+	go func() {
+		for {
+			pairs, meta, err := observer.client.List(observer.electionKey(), (&api.QueryOptions{WaitIndex: index}).WithContext(ctx))
+			if err != nil {
+				return
+			}
+			index = meta.LastIndex
+
+			current := observer.toNodeSpecs(pairs)
+			for key, spec := range known {
+				if _, ok := current[key]; !ok {
+					if err := observer.RemoveNodes(spec); err != nil {
+						panic(err)
+					}
+					log.Infof("Node deleted : %s", spec.Marshal())
+				}
+			}
+
+			for key, spec := range current {
+				if previous, ok := known[key]; !ok || *previous != *spec {
+					if err := observer.UpdateNodes(spec); err != nil {
+						panic(err)
+					}
+					log.Infof("node updated : %s", spec.Marshal())
+				}
+			}
+			known = current
+		}
+	}()
+}
+
+func (observer *Observer) observeLeader() {
+	ctx := observer.Ctx
+	go func() {
+		observe := observer.election.Observe(ctx)
+		for leader := range observe {
+			decoded := observer.toNodeSpec(&leader)
+			if err := observer.UpdateLeader(&decoded); err != nil {
+				panic(err)
+			}
+			log.Infof("Leader updated : %s", decoded.Marshal())
+		}
+	}()
+}
+
+func (observer *Observer) pushCurrentNodes() (map[string]*node.Spec, uint64) {
+	pairs, meta, err := observer.client.List(observer.electionKey(), (&api.QueryOptions{}).WithContext(observer.Ctx))
+	if err != nil {
+		panic(err)
+	}
+
+	known := observer.toNodeSpecs(pairs)
+	nodes := make([]*node.Spec, 0, len(known))
+	for _, spec := range known {
+		nodes = append(nodes, spec)
+	}
+
+	if err := observer.UpdateNodes(nodes...); err != nil {
+		panic(err)
+	}
+	log.Infof("Current nodes updated: %v", nodes)
+	return known, meta.LastIndex
+}
+
+func (observer *Observer) toNodeSpecs(pairs api.KVPairs) map[string]*node.Spec {
+	nodes := make(map[string]*node.Spec, len(pairs))
+	for _, pair := range pairs {
+		decoded := observer.toNodeSpec(pair)
+		nodes[decoded.ElectionKey] = &decoded
+	}
+	return nodes
+}
+
+func (observer *Observer) subscribe() error {
+	log.Infof("Subscribe to the election %s...", observer.electionKey())
+	var err error
+	if observer.client, err = observer.Connector.Connect(observer.Ctx, observer.ConfigSpec); err != nil {
+		return err
+	}
+	log.Infof("new election...")
+	observer.election, _ = observer.Connector.NewElection(observer.Ctx, observer.electionKey())
+
+	log.Infof("subscribed to Consul server.")
+	return nil
+}
+
+func (observer *Observer) failBack() error {
+	observer.Reset()
+	if err := observer.subscribe(); err != nil {
+		return err
+	}
+	nodes, index := observer.pushCurrentNodes()
+	observer.observeLeader()
+	observer.observeNodes(nodes, index)
+	return nil
+}
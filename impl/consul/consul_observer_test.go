@@ -0,0 +1,82 @@
+package consul_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/consul"
+	consulmock "github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"testing"
+)
+
+func TestConsulObserver_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			election.TestObserver(t, func() election.Observer {
+				return consul.NewObserver(example.config(server))
+			})
+		})
+	}
+}
+
+func TestConsulObserver_when_a_node_is_elected_then_publish_it_as_leader(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			observer := consul.NewObserver(example.config(server))
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulObserver, %v", testutils.Failed, err)
+			}
+
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+			elector := consul.NewElector(example.config(server))
+			defer elector.Cleanup()
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == example.nodeSpec.Name
+			})
+		})
+	}
+}
+
+func TestConsulObserver_when_the_leader_leaves_then_remove_it(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			server := consulmock.NewConsulServer(t)
+			defer server.Close()
+			observer := consul.NewObserver(example.config(server))
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulObserver, %v", testutils.Failed, err)
+			}
+
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+			elector := consul.NewElector(example.config(server))
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: ConsulElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == example.nodeSpec.Name
+			})
+
+			elector.Cleanup() // Destroys the session, so its key
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader == nil && len(proxy.Followers) == 0
+			})
+		})
+	}
+}
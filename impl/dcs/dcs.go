@@ -0,0 +1,106 @@
+package dcs
+
+import (
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/impl/consul"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/impl/kubernetes"
+	"github/mlyahmed.io/nominee/impl/raft"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"strings"
+)
+
+// The features only the etcd backend supports, the raft, consul and kubernetes backends run the bare election:
+//
+//	feature                     etcd  raft  consul  kubernetes
+//	nofailover tag              yes   no    no      no
+//	clonefrom tag               yes   no    no      no
+//	pause                       yes   no    no      no
+//	switchover                  yes   no    no      no
+//	synchronous standby         yes   no    no      no
+//	replication slots           yes   no    no      no
+//	initialize key              yes   no    no      no
+//	dynamic configuration       yes   no    no      no
+const (
+	NoFailover         = "the nofailover tag"
+	CloneFrom          = "the clonefrom tag"
+	Pause              = "the pause"
+	Switchover         = "the switchover"
+	SynchronousStandby = "the synchronous standby"
+	ReplicationSlots   = "the replication slots"
+	InitializeKey      = "the initialize key"
+	DynamicConfig      = "the dynamic configuration"
+)
+
+// NewElector returns the elector of the NOMINEE_DCS backend, etcd by default. It refuses a managed node tagged
+// nofailover on a backend which would have it run for the leadership anyway.
+func NewElector(cl ConfigLoader, managed node.Node) (election.Elector, error) {
+	cl.Load(context.Background())
+	backend := cl.GetSpec().Backend
+	if backend != Etcd && managed.GetSpec().Tags.NoFailover {
+		return nil, fmt.Errorf("dcs: %s does not support %s, refuse to run %s", backend, NoFailover, managed.GetName())
+	}
+	switch backend {
+	case Raft:
+		return raft.NewElector(raft.NewConfigLoader()), nil
+	case Consul:
+		return consul.NewElector(consul.NewConfigLoader()), nil
+	case Kubernetes:
+		return kubernetes.NewElector(kubernetes.NewConfigLoader()), nil
+	default:
+		return etcd.NewElector(etcd.NewConfigLoader()), nil
+	}
+}
+
+// NewObserver returns the observer of the NOMINEE_DCS backend, etcd by default.
+func NewObserver(cl ConfigLoader) election.Observer {
+	cl.Load(context.Background())
+	switch cl.GetSpec().Backend {
+	case Raft:
+		return raft.NewObserver(raft.NewConfigLoader())
+	case Consul:
+		return consul.NewObserver(consul.NewConfigLoader())
+	case Kubernetes:
+		return kubernetes.NewObserver(kubernetes.NewConfigLoader())
+	default:
+		return etcd.NewObserver(etcd.NewConfigLoader())
+	}
+}
+
+// Unsupported returns the features of the managed node the NOMINEE_DCS backend lacks, none with etcd.
+func Unsupported(cl ConfigLoader, managed node.Node) []string {
+	cl.Load(context.Background())
+	if cl.GetSpec().Backend == Etcd {
+		return nil
+	}
+	features := []string{NoFailover, Pause}
+	if _, ok := managed.(node.Demoter); ok {
+		features = append(features, Switchover)
+	}
+	if _, ok := managed.(node.Synchronizer); ok {
+		features = append(features, SynchronousStandby)
+	}
+	if _, ok := managed.(node.SlotKeeper); ok {
+		features = append(features, ReplicationSlots)
+	}
+	if _, ok := managed.(node.Initializer); ok {
+		features = append(features, InitializeKey)
+	}
+	if _, ok := managed.(node.Configurer); ok {
+		features = append(features, DynamicConfig)
+	}
+	if _, ok := managed.(node.Cloner); ok {
+		features = append(features, CloneFrom)
+	}
+	return features
+}
+
+// WarnUnsupported logs the features of the managed node the NOMINEE_DCS backend lacks.
+func WarnUnsupported(cl ConfigLoader, managed node.Node) {
+	if features := Unsupported(cl, managed); len(features) > 0 {
+		logger.G(context.Background()).Warnf("dcs: %s does not support %s, they are off.", cl.GetSpec().Backend, strings.Join(features, ", "))
+	}
+}
@@ -0,0 +1,66 @@
+package dcs
+
+import (
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"sort"
+	"strings"
+)
+
+// The backends which run the election.
+const (
+	// Etcd runs the election in an etcd cluster.
+	Etcd = "etcd"
+	// Raft runs the election among the nominee processes themselves, without any external store.
+	Raft = "raft"
+	// Consul runs the election in a Consul cluster.
+	Consul = "consul"
+	// Kubernetes runs the election on a Lease of the Kubernetes API.
+	Kubernetes = "kubernetes"
+)
+
+var backends = map[string]bool{Etcd: true, Raft: true, Consul: true, Kubernetes: true}
+
+// ConfigLoader ...
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	Backend string
+	Loaded  bool
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{}
+}
+
+// Load ...
+func (conf *ConfigSpec) Load(context.Context) {
+	if conf.Loaded {
+		return
+	}
+	config.SetDefault("NOMINEE_DCS", Etcd)
+	conf.Backend = config.GetString("NOMINEE_DCS")
+	if !backends[conf.Backend] {
+		names := make([]string, 0, len(backends))
+		for name := range backends {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		panic(fmt.Sprintf("You must specify the env var NOMINEE_DCS to one of %s.", strings.Join(names, ", ")))
+	}
+	conf.Loaded = true
+}
+
+// GetSpec ...
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	if !conf.Loaded {
+		panic("config not loaded.")
+	}
+	return conf
+}
@@ -0,0 +1,47 @@
+package dcs_test
+
+import "github/mlyahmed.io/nominee/impl/dcs"
+
+type configurationExample struct {
+	description string
+	backend     string
+	expected    string
+}
+
+var validExamples = []configurationExample{
+	{
+		description: "the default backend",
+		expected:    dcs.Etcd,
+	},
+	{
+		description: "etcd",
+		backend:     "etcd",
+		expected:    dcs.Etcd,
+	},
+	{
+		description: "raft",
+		backend:     "raft",
+		expected:    dcs.Raft,
+	},
+	{
+		description: "consul",
+		backend:     "consul",
+		expected:    dcs.Consul,
+	},
+	{
+		description: "kubernetes",
+		backend:     "kubernetes",
+		expected:    dcs.Kubernetes,
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "an unknown backend",
+		backend:     "zookeeper",
+	},
+	{
+		description: "the backend in upper case",
+		backend:     "ETCD",
+	},
+}
@@ -0,0 +1,48 @@
+package dcs_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/dcs"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"testing"
+)
+
+func TestDCSConfig_it_must_load_all_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := dcs.NewConfigLoader()
+			loader.Load(context.TODO())
+			if actual := loader.GetSpec().Backend; actual != example.expected {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Backend (%s), expected <%s> but actual is <%s>", testutils.Failed, example.description, example.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDCSConfig_it_must_panic_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load() (%s). Expected the program to panic. Actual not.", testutils.Failed, example.description)
+				}
+			}()
+			dcs.NewConfigLoader().Load(context.TODO())
+		})
+	}
+}
+
+func declareConfigurationExample(example configurationExample) {
+	if example.backend != "" {
+		_ = os.Setenv("NOMINEE_DCS", example.backend)
+	}
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_DCS")
+}
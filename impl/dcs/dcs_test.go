@@ -0,0 +1,71 @@
+package dcs_test
+
+import (
+	"github/mlyahmed.io/nominee/impl/dcs"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestUnsupported_when_etcd_then_none(t *testing.T) {
+	t.Logf("Given the etcd backend")
+	{
+		defer tearsDown()
+		_ = os.Setenv("NOMINEE_DCS", dcs.Etcd)
+		managed := mock.NewNode(t, &node.Spec{Name: "node-001"})
+
+		t.Logf("\tWhen ask for the unsupported features")
+		{
+			if features := dcs.Unsupported(dcs.NewConfigLoader(), managed); len(features) != 0 {
+				t.Fatalf("\t\t%s FAIL: Unsupported, expected none but actual is <%v>", testutils.Failed, features)
+			}
+			t.Logf("\t\t%s Then there must be none.", testutils.Succeed)
+		}
+	}
+}
+
+func TestUnsupported_when_not_etcd_then_the_features_of_the_node(t *testing.T) {
+	for _, backend := range []string{dcs.Raft, dcs.Consul, dcs.Kubernetes} {
+		t.Run(backend, func(t *testing.T) {
+			t.Logf("Given the %s backend and a node able to step down", backend)
+			{
+				defer tearsDown()
+				_ = os.Setenv("NOMINEE_DCS", backend)
+				managed := mock.NewNode(t, &node.Spec{Name: "node-001"})
+
+				t.Logf("\tWhen ask for the unsupported features")
+				{
+					expected := []string{dcs.NoFailover, dcs.Pause, dcs.Switchover}
+					if features := dcs.Unsupported(dcs.NewConfigLoader(), managed); !reflect.DeepEqual(features, expected) {
+						t.Fatalf("\t\t%s FAIL: Unsupported, expected <%v> but actual is <%v>", testutils.Failed, expected, features)
+					}
+					t.Logf("\t\t%s Then they must be the nofailover tag, the pause and the switchover.", testutils.Succeed)
+				}
+			}
+		})
+	}
+}
+
+func TestNewElector_when_not_etcd_and_the_node_is_tagged_nofailover_then_refuse(t *testing.T) {
+	for _, backend := range []string{dcs.Raft, dcs.Consul, dcs.Kubernetes} {
+		t.Run(backend, func(t *testing.T) {
+			t.Logf("Given the %s backend and a node tagged nofailover", backend)
+			{
+				defer tearsDown()
+				_ = os.Setenv("NOMINEE_DCS", backend)
+				managed := mock.NewNode(t, &node.Spec{Name: "node-001", Tags: node.Tags{NoFailover: true}})
+
+				t.Logf("\tWhen ask for the elector")
+				{
+					if elector, err := dcs.NewElector(dcs.NewConfigLoader(), managed); err == nil || elector != nil {
+						t.Fatalf("\t\t%s FAIL: NewElector, expected an error but actual is <%v>", testutils.Failed, elector)
+					}
+					t.Logf("\t\t%s Then it must refuse to run the node.", testutils.Succeed)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package envoy
+
+import (
+	"context"
+	"fmt"
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	clusterservice "github.com/envoyproxy/go-control-plane/envoy/service/cluster/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	endpointservice "github.com/envoyproxy/go-control-plane/envoy/service/endpoint/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/server/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/proxy"
+	"google.golang.org/grpc"
+	"net"
+	"strconv"
+	"sync"
+)
+
+const (
+	// PrimaryCluster is the Envoy cluster routing to the leader.
+	PrimaryCluster string = "primary"
+	// StandbyCluster is the Envoy cluster routing to the followers.
+	StandbyCluster string = "standby"
+
+	snapshotKey string = "nominee"
+)
+
+// Envoy is a control plane serving the primary and standby clusters to the Envoy instances over xDS.
+type Envoy struct {
+	*proxy.BasicProxy
+	config  *ConfigSpec
+	cache   cache.SnapshotCache
+	server  *grpc.Server
+	version int64
+	mutex   *sync.Mutex
+}
+
+// sameSnapshot hands the same snapshot to every Envoy whatever its node id.
+type sameSnapshot struct{}
+
+func (sameSnapshot) ID(*corev3.Node) string {
+	return snapshotKey
+}
+
+// NewEnvoy ...
+func NewEnvoy(cl ConfigLoader) *Envoy {
+	cl.Load(context.Background())
+	envoy := Envoy{
+		BasicProxy: proxy.NewBasicProxy(),
+		config:     cl.GetSpec(),
+		server:     grpc.NewServer(),
+		mutex:      &sync.Mutex{},
+	}
+
+	log := logger.G(envoy.Ctx)
+	envoy.cache = cache.NewSnapshotCache(true, sameSnapshot{}, log)
+	xds := server.NewServer(envoy.Ctx, envoy.cache, nil)
+	discoverygrpc.RegisterAggregatedDiscoveryServiceServer(envoy.server, xds)
+	clusterservice.RegisterClusterDiscoveryServiceServer(envoy.server, xds)
+	endpointservice.RegisterEndpointDiscoveryServiceServer(envoy.server, xds)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", envoy.config.Address, envoy.config.Port))
+	if err != nil {
+		panic(err)
+	}
+
+	_ = envoy.Publish(nil)
+
+	go func() {
+		log.Infof("xDS server listening on %s", listener.Addr())
+		if err := envoy.server.Serve(listener); err != nil {
+			log.Errorf("xDS server stopped: %v", err)
+		}
+		envoy.Stonith(envoy.Ctx)
+	}()
+
+	go func() {
+		<-envoy.Ctx.Done()
+		envoy.server.Stop()
+	}()
+
+	return &envoy
+}
+
+// Publish replaces the snapshot, so every subscribed Envoy receives the new endpoints.
+func (p *Envoy) Publish(leader *node.Spec, followers ...*node.Spec) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.version++
+	version := strconv.FormatInt(p.version, 10)
+	snapshot := cache.NewSnapshot(version,
+		[]types.Resource{p.loadAssignment(PrimaryCluster, leader), p.loadAssignment(StandbyCluster, followers...)},
+		[]types.Resource{p.cluster(PrimaryCluster), p.cluster(StandbyCluster)},
+		nil, nil, nil, nil,
+	)
+
+	if err := snapshot.Consistent(); err != nil {
+		return err
+	}
+
+	if err := p.cache.SetSnapshot(snapshotKey, snapshot); err != nil {
+		return err
+	}
+
+	logger.G(p.Ctx).Infof("snapshot %s published", version)
+	return nil
+}
+
+func (p *Envoy) cluster(name string) *clusterv3.Cluster {
+	return &clusterv3.Cluster{
+		Name:                 name,
+		ConnectTimeout:       ptypes.DurationProto(p.config.ConnectTimeout),
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{Type: clusterv3.Cluster_EDS},
+		LbPolicy:             clusterv3.Cluster_ROUND_ROBIN,
+		EdsClusterConfig: &clusterv3.Cluster_EdsClusterConfig{
+			EdsConfig: &corev3.ConfigSource{
+				ResourceApiVersion:    corev3.ApiVersion_V3,
+				ConfigSourceSpecifier: &corev3.ConfigSource_Ads{Ads: &corev3.AggregatedConfigSource{}},
+			},
+		},
+	}
+}
+
+func (p *Envoy) loadAssignment(name string, nodes ...*node.Spec) *endpointv3.ClusterLoadAssignment {
+	var endpoints []*endpointv3.LbEndpoint
+	for _, nod := range nodes {
+		if nod == nil || nod.Address == "" {
+			continue
+		}
+		endpoints = append(endpoints, &endpointv3.LbEndpoint{
+			HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+				Endpoint: &endpointv3.Endpoint{
+					Hostname: nod.Name,
+					Address: &corev3.Address{
+						Address: &corev3.Address_SocketAddress{
+							SocketAddress: &corev3.SocketAddress{
+								Protocol:      corev3.SocketAddress_TCP,
+								Address:       nod.Address,
+								PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: uint32(nod.Port)},
+							},
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return &endpointv3.ClusterLoadAssignment{
+		ClusterName: name,
+		Endpoints:   []*endpointv3.LocalityLbEndpoints{{LbEndpoints: endpoints}},
+	}
+}
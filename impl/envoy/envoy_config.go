@@ -0,0 +1,51 @@
+package envoy
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"time"
+)
+
+// ConfigLoader ...
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	Address        string
+	Port           int64
+	ConnectTimeout time.Duration
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{BasicConfig: config.NewBasicConfig()}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+
+	config.SetDefault("NOMINEE_ENVOY_XDS_ADDRESS", "0.0.0.0")
+	config.SetDefault("NOMINEE_ENVOY_XDS_PORT", 18000)
+	config.SetDefault("NOMINEE_ENVOY_CONNECT_TIMEOUT", "1s")
+
+	conf.Address = config.GetStringOrPanic("NOMINEE_ENVOY_XDS_ADDRESS")
+	conf.Port = int64(config.GetIntOrPanic("NOMINEE_ENVOY_XDS_PORT"))
+	conf.ConnectTimeout = getDurationOrPanic("NOMINEE_ENVOY_CONNECT_TIMEOUT")
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	return conf
+}
+
+func getDurationOrPanic(key string) time.Duration {
+	duration, err := time.ParseDuration(config.GetStringOrPanic(key))
+	if err != nil {
+		panic(err)
+	}
+	return duration
+}
@@ -0,0 +1,51 @@
+package envoy_test
+
+type configurationExample struct {
+	description    string
+	cluster        string
+	domain         string
+	xdsAddress     string
+	xdsPort        string
+	connectTimeout string
+}
+
+var validExamples = []configurationExample{
+	{
+		description: "minimum configuration",
+		cluster:     "cluster-001",
+		domain:      "domain-002",
+	},
+	{
+		description:    "full configuration #1",
+		cluster:        "cluster-111",
+		domain:         "domain-542",
+		xdsAddress:     "127.0.0.1",
+		xdsPort:        "19000",
+		connectTimeout: "250ms",
+	},
+	{
+		description:    "full configuration #2",
+		cluster:        "cluster-65888",
+		domain:         "domain/6565659",
+		xdsAddress:     "envoy.control-plane.priv",
+		xdsPort:        "18001",
+		connectTimeout: "5s",
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "cluster name is missing",
+		domain:      "domain-002",
+	},
+	{
+		description: "domain name is missing",
+		cluster:     "cluster",
+	},
+	{
+		description:    "connect timeout is not a duration",
+		cluster:        "cluster-001",
+		domain:         "domain-002",
+		connectTimeout: "one second",
+	},
+}
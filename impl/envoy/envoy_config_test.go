@@ -0,0 +1,110 @@
+package envoy_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/envoy"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEnvoyConfig_loads_configurations(t *testing.T) {
+	t.Logf("Given a valid Envoy configuration")
+	{
+		for i, example := range validExamples {
+			t.Run("", func(t *testing.T) {
+				defer tearsDown()
+				declareConfigurationExample(example)
+
+				t.Logf("\tTest %d: When load configuration and %s.", i, example.description)
+				{
+					loader := envoy.NewConfigLoader()
+					loader.Load(context.TODO())
+					config := loader.GetSpec()
+
+					if config.Cluster != example.cluster {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, config.Cluster)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Cluster should be loaded.", testutils.Succeed)
+
+					if config.Domain != example.domain {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, config.Domain)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Domain should be loaded.", testutils.Succeed)
+
+					expectedAddress := example.xdsAddress
+					if expectedAddress == "" {
+						expectedAddress = "0.0.0.0"
+					}
+					if config.Address != expectedAddress {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Address, expected <%s> but actual is <%s>", testutils.Failed, expectedAddress, config.Address)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Address should be loaded.", testutils.Succeed)
+
+					expectedPort := example.xdsPort
+					if expectedPort == "" {
+						expectedPort = "18000"
+					}
+					if strconv.Itoa(int(config.Port)) != expectedPort {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Port, expected <%s> but actual is <%d>", testutils.Failed, expectedPort, config.Port)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Port should be loaded.", testutils.Succeed)
+
+					expectedTimeout := time.Second
+					if example.connectTimeout != "" {
+						expectedTimeout, _ = time.ParseDuration(example.connectTimeout)
+					}
+					if config.ConnectTimeout != expectedTimeout {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.ConnectTimeout, expected <%v> but actual is <%v>", testutils.Failed, expectedTimeout, config.ConnectTimeout)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.ConnectTimeout should be loaded.", testutils.Succeed)
+				}
+			})
+		}
+	}
+}
+
+func TestEnvoyConfig_panics_when_bad_configuration(t *testing.T) {
+	t.Logf("Given an invalid Envoy configuration")
+	{
+		for i, example := range invalidExamples {
+			t.Run("", func(t *testing.T) {
+				defer tearsDown()
+				declareConfigurationExample(example)
+
+				t.Logf("\tTest %d: When load configuration and %s.", i, example.description)
+				{
+					defer func() {
+						if r := recover(); r == nil {
+							t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+						} else {
+							t.Logf("\t\t%s Then the program must panic.", testutils.Succeed)
+						}
+					}()
+
+					envoyConfig := envoy.NewConfigLoader()
+					envoyConfig.Load(context.TODO())
+				}
+
+			})
+		}
+	}
+}
+
+func declareConfigurationExample(example configurationExample) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_ENVOY_XDS_ADDRESS", example.xdsAddress)
+	_ = os.Setenv("NOMINEE_ENVOY_XDS_PORT", example.xdsPort)
+	_ = os.Setenv("NOMINEE_ENVOY_CONNECT_TIMEOUT", example.connectTimeout)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_ENVOY_XDS_ADDRESS")
+	_ = os.Unsetenv("NOMINEE_ENVOY_XDS_PORT")
+	_ = os.Unsetenv("NOMINEE_ENVOY_CONNECT_TIMEOUT")
+}
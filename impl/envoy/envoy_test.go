@@ -0,0 +1,269 @@
+package envoy_test
+
+import (
+	"context"
+	"fmt"
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	discoverygrpc "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/envoy"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var (
+	leader    = node.Spec{Name: "postgres-01", Address: "node01.postgres.priv", Port: 5432}
+	follower1 = node.Spec{Name: "postgres-02", Address: "node02.postgres.priv", Port: 5432}
+	follower2 = node.Spec{Name: "postgres-03", Address: "node03.postgres.priv", Port: 5433}
+)
+
+type subscription struct {
+	discoverygrpc.AggregatedDiscoveryService_StreamAggregatedResourcesClient
+	typeURL string
+	names   []string
+}
+
+func newEnvoy(t *testing.T) (*envoy.Envoy, string) {
+	address := mock.FreeAddress(t)
+	host, port, _ := net.SplitHostPort(address)
+	portNumber, _ := strconv.Atoi(port)
+	proxy := envoy.NewEnvoy(&mock.EnvoyConfigSpec{ConfigSpec: &envoy.ConfigSpec{
+		BasicConfig:    &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		Address:        host,
+		Port:           int64(portNumber),
+		ConnectTimeout: time.Second,
+	}})
+	t.Cleanup(func() { proxy.Stonith(context.Background()) })
+	return proxy, address
+}
+
+func subscribe(t *testing.T, address, typeURL string, names ...string) *subscription {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: grpc.Dial, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream, err := discoverygrpc.NewAggregatedDiscoveryServiceClient(conn).StreamAggregatedResources(ctx)
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: StreamAggregatedResources, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+
+	sub := &subscription{AggregatedDiscoveryService_StreamAggregatedResourcesClient: stream, typeURL: typeURL, names: names}
+	sub.ack(t, nil)
+	return sub
+}
+
+func (sub *subscription) ack(t *testing.T, response *discoverygrpc.DiscoveryResponse) {
+	request := &discoverygrpc.DiscoveryRequest{
+		Node:          &corev3.Node{Id: "envoy-" + t.Name()},
+		TypeUrl:       sub.typeURL,
+		ResourceNames: sub.names,
+	}
+	if response != nil {
+		request.VersionInfo = response.VersionInfo
+		request.ResponseNonce = response.Nonce
+	}
+	if err := sub.Send(request); err != nil {
+		t.Fatalf("\t\t%s FAIL: DiscoveryRequest, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+}
+
+func (sub *subscription) receive(t *testing.T) *discoverygrpc.DiscoveryResponse {
+	responses, errs := make(chan *discoverygrpc.DiscoveryResponse), make(chan error)
+	go func() {
+		response, err := sub.Recv()
+		if err != nil {
+			errs <- err
+			return
+		}
+		responses <- response
+	}()
+
+	select {
+	case response := <-responses:
+		sub.ack(t, response)
+		return response
+	case err := <-errs:
+		t.Fatalf("\t\t%s FAIL: DiscoveryResponse, expected no error but actual is <%v>", testutils.Failed, err)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("\t\t%s FAIL: DiscoveryResponse, expected a response but actual is none", testutils.Failed)
+	}
+	return nil
+}
+
+func endpointsOf(t *testing.T, response *discoverygrpc.DiscoveryResponse) map[string][]string {
+	endpoints := make(map[string][]string)
+	for _, any := range response.Resources {
+		assignment := &endpointv3.ClusterLoadAssignment{}
+		if err := ptypes.UnmarshalAny(any, assignment); err != nil {
+			t.Fatalf("\t\t%s FAIL: ClusterLoadAssignment, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+		addresses := make([]string, 0)
+		for _, locality := range assignment.Endpoints {
+			for _, lbEndpoint := range locality.LbEndpoints {
+				socket := lbEndpoint.GetEndpoint().Address.GetSocketAddress()
+				addresses = append(addresses, fmt.Sprintf("%s:%d", socket.Address, socket.GetPortValue()))
+			}
+		}
+		sort.Strings(addresses)
+		endpoints[assignment.ClusterName] = addresses
+	}
+	return endpoints
+}
+
+func TestEnvoy_serves_the_primary_and_standby_clusters(t *testing.T) {
+	t.Logf("Given a running Envoy control plane")
+	{
+		_, address := newEnvoy(t)
+		t.Logf("\tWhen an Envoy subscribes to the clusters")
+		{
+			response := subscribe(t, address, resource.ClusterType).receive(t)
+
+			names := make([]string, 0)
+			for _, any := range response.Resources {
+				cluster := &clusterv3.Cluster{}
+				if err := ptypes.UnmarshalAny(any, cluster); err != nil {
+					t.Fatalf("\t\t%s FAIL: Cluster, expected no error but actual is <%v>", testutils.Failed, err)
+				}
+				if cluster.GetType() != clusterv3.Cluster_EDS {
+					t.Fatalf("\t\t%s FAIL: Cluster.Type, expected <%v> but actual is <%v>", testutils.Failed, clusterv3.Cluster_EDS, cluster.GetType())
+				}
+				names = append(names, cluster.Name)
+			}
+			sort.Strings(names)
+
+			expected := []string{envoy.PrimaryCluster, envoy.StandbyCluster}
+			if !reflect.DeepEqual(names, expected) {
+				t.Fatalf("\t\t%s FAIL: clusters, expected <%v> but actual is <%v>", testutils.Failed, expected, names)
+			}
+			t.Logf("\t\t%s Then it must receive the primary and standby EDS clusters.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEnvoy_publishes_the_leader_and_the_followers_endpoints(t *testing.T) {
+	t.Logf("Given a running Envoy control plane")
+	{
+		proxy, address := newEnvoy(t)
+		t.Logf("\tWhen it publishes a leader and two followers")
+		{
+			if err := proxy.Publish(&leader, &follower1, &follower2); err != nil {
+				t.Fatalf("\t\t%s FAIL: Publish, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			response := subscribe(t, address, resource.EndpointType, envoy.PrimaryCluster, envoy.StandbyCluster).receive(t)
+
+			expected := map[string][]string{
+				envoy.PrimaryCluster: {"node01.postgres.priv:5432"},
+				envoy.StandbyCluster: {"node02.postgres.priv:5432", "node03.postgres.priv:5433"},
+			}
+			if actual := endpointsOf(t, response); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: endpoints, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the leader must be the primary endpoint and the followers the standby ones.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEnvoy_pushes_the_endpoints_when_the_leader_changes(t *testing.T) {
+	t.Logf("Given an Envoy subscribed to the endpoints")
+	{
+		proxy, address := newEnvoy(t)
+		_ = proxy.Publish(&leader, &follower1)
+		sub := subscribe(t, address, resource.EndpointType, envoy.PrimaryCluster, envoy.StandbyCluster)
+		first := sub.receive(t)
+
+		t.Logf("\tWhen the follower becomes the leader")
+		{
+			_ = proxy.Publish(&follower1, &leader)
+			second := sub.receive(t)
+
+			if second.VersionInfo == first.VersionInfo {
+				t.Fatalf("\t\t%s FAIL: VersionInfo, expected a new version but actual is <%s>", testutils.Failed, second.VersionInfo)
+			}
+			t.Logf("\t\t%s Then it must receive a new version.", testutils.Succeed)
+
+			expected := map[string][]string{
+				envoy.PrimaryCluster: {"node02.postgres.priv:5432"},
+				envoy.StandbyCluster: {"node01.postgres.priv:5432"},
+			}
+			if actual := endpointsOf(t, second); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: endpoints, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the primary and standby endpoints must be swapped.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEnvoy_publishes_no_primary_endpoint_when_there_is_no_leader(t *testing.T) {
+	t.Logf("Given a running Envoy control plane")
+	{
+		proxy, address := newEnvoy(t)
+		t.Logf("\tWhen it publishes followers without a leader")
+		{
+			_ = proxy.Publish(&node.Spec{}, &follower1)
+			response := subscribe(t, address, resource.EndpointType, envoy.PrimaryCluster, envoy.StandbyCluster).receive(t)
+
+			expected := map[string][]string{
+				envoy.PrimaryCluster: {},
+				envoy.StandbyCluster: {"node02.postgres.priv:5432"},
+			}
+			if actual := endpointsOf(t, response); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: endpoints, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the primary cluster must have no endpoint.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEnvoy_stops_serving_when_stonithed(t *testing.T) {
+	t.Logf("Given a running Envoy control plane")
+	{
+		proxy, address := newEnvoy(t)
+		t.Logf("\tWhen it is stonithed")
+		{
+			proxy.Stonith(context.Background())
+
+			select {
+			case <-proxy.Done():
+			case <-time.After(time.Second):
+				t.Fatalf("\t\t%s FAIL: Done, expected to be closed but actual is not", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must be done.", testutils.Succeed)
+
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				conn, err := net.DialTimeout("tcp", address, 100*time.Millisecond)
+				if err != nil {
+					break
+				}
+				_ = conn.Close()
+				if time.Now().After(deadline) {
+					t.Fatalf("\t\t%s FAIL: xDS server, expected to be stopped but actual still listens on <%s>", testutils.Failed, address)
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+			t.Logf("\t\t%s Then the xDS server must stop listening.", testutils.Succeed)
+		}
+	}
+}
@@ -1,12 +1,33 @@
 package etcd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/coreos/etcd/clientv3"
 	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/metrics"
 	"github/mlyahmed.io/nominee/pkg/node"
+	"sort"
 )
 
+// Switchover is the intent to hand the leadership over to the candidate.
+type Switchover struct {
+	Leader    string
+	Candidate string
+}
+
+// Sync is the synchronous standby the leader has recorded, empty when it replicates asynchronously.
+type Sync struct {
+	Leader      string
+	SyncStandby string
+}
+
+// DynamicConfig is the cluster-wide configuration the nodes apply.
+type DynamicConfig struct {
+	Parameters node.Parameters
+}
+
 // Etcd ...
 type Etcd struct {
 	*ConfigSpec
@@ -37,6 +58,7 @@ func (etcd *Etcd) listenToTheConnectorSession() {
 		for { //TODO: retries limit
 			<-etcd.Connector.Stop()
 			log.Infof("session closed. Try to reconnect...")
+			metrics.SessionReconnected("etcd")
 			_ = etcd.failBackFn()
 		}
 	}()
@@ -51,6 +73,130 @@ func (etcd *Etcd) toNodeSpec(response clientv3.GetResponse) node.Spec {
 	return value
 }
 
+// members lists the members of the election, the leader first then the followers in their order of arrival.
+func (etcd *Etcd) members(ctx context.Context, client Client) ([]Member, error) {
+	members, err := etcd.list(ctx, client, etcd.electionKey()+"/")
+	if err == nil && len(members) > 0 {
+		members[0].Role = LeaderRole
+	}
+	return members, err
+}
+
+// replicas lists the nodes registered out of the election, in their order of arrival.
+func (etcd *Etcd) replicas(ctx context.Context, client Client) ([]Member, error) {
+	return etcd.list(ctx, client, etcd.replicaKey()+"/")
+}
+
+// sync returns the synchronous standby recorded by the leader, nil when there is none.
+func (etcd *Etcd) sync(ctx context.Context, client Client) (*Sync, error) {
+	response, err := client.Get(ctx, etcd.syncKey())
+	if err != nil || len(response.Kvs) == 0 {
+		return nil, err
+	}
+	record := Sync{}
+	if err := json.Unmarshal(response.Kvs[0].Value, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// systemID returns the system identifier the cluster is initialized with, empty when it is not yet.
+func (etcd *Etcd) systemID(ctx context.Context, client Client) (string, error) {
+	response, err := client.Get(ctx, etcd.initializeKey())
+	if err != nil || len(response.Kvs) == 0 {
+		return "", err
+	}
+	return string(response.Kvs[0].Value), nil
+}
+
+// config returns the cluster-wide configuration, empty when there is none, and the revision it is read at.
+func (etcd *Etcd) config(ctx context.Context, client Client) (*DynamicConfig, int64, error) {
+	response, err := client.Get(ctx, etcd.configKey())
+	if err != nil {
+		return nil, 0, err
+	}
+	var revision int64
+	if response.Header != nil {
+		revision = response.Header.Revision
+	}
+	if len(response.Kvs) == 0 {
+		return &DynamicConfig{}, revision, nil
+	}
+	config, err := toDynamicConfig(response.Kvs[0].Value)
+	return config, revision, err
+}
+
+func toDynamicConfig(value []byte) (*DynamicConfig, error) {
+	config := DynamicConfig{}
+	if err := json.Unmarshal(value, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (etcd *Etcd) list(ctx context.Context, client Client, prefix string) ([]Member, error) {
+	response, err := client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	kvs := response.Kvs
+	sort.SliceStable(kvs, func(a, b int) bool { return kvs[a].CreateRevision < kvs[b].CreateRevision })
+
+	members := make([]Member, len(kvs))
+	for index, kv := range kvs {
+		spec, _ := node.Unmarshal(kv.Value)
+		spec.ElectionKey = string(kv.Key)
+		members[index] = Member{Spec: spec, Role: FollowerRole, LeaseTTL: -1}
+		if kv.Lease != 0 {
+			if ttl, err := client.TimeToLive(ctx, clientv3.LeaseID(kv.Lease)); err == nil {
+				members[index].LeaseTTL = ttl.TTL
+			}
+		}
+	}
+	return members, nil
+}
+
 func (etcd *Etcd) electionKey() string {
 	return fmt.Sprintf("nominee/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
 }
+
+// switchoverKey is out of the election key prefix, otherwise the intent would run for the election.
+func (etcd *Etcd) switchoverKey() string {
+	return fmt.Sprintf("nominee/switchover/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
+
+// replicaKey is the prefix the nodes which never run for the election register under, so that they are known anyway.
+func (etcd *Etcd) replicaKey() string {
+	return fmt.Sprintf("nominee/replica/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
+
+// pauseKey pauses the whole cluster, out of the election key prefix like the switchover intent.
+func (etcd *Etcd) pauseKey() string {
+	return fmt.Sprintf("nominee/pause/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
+
+// nodePauseKey pauses the node only.
+func (etcd *Etcd) nodePauseKey(name string) string {
+	return etcd.pauseKey() + "/" + name
+}
+
+// syncKey records the synchronous standby, out of the election key prefix like the switchover intent.
+func (etcd *Etcd) syncKey() string {
+	return fmt.Sprintf("nominee/sync/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
+
+// initializeKey records the system identifier of the cluster, written once by the node which initialized it.
+func (etcd *Etcd) initializeKey() string {
+	return fmt.Sprintf("nominee/initialize/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
+
+// configKey holds the cluster-wide configuration, out of the election key prefix like the switchover intent.
+func (etcd *Etcd) configKey() string {
+	return fmt.Sprintf("nominee/config/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
+
+// pendingRestartKey is the prefix the nodes waiting for a restart to apply the configuration are flagged under.
+func (etcd *Etcd) pendingRestartKey() string {
+	return fmt.Sprintf("nominee/pending/domain/%s/cluster/%s", etcd.Domain, etcd.Cluster)
+}
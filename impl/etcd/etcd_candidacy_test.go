@@ -0,0 +1,130 @@
+package etcd_test
+
+import (
+	"context"
+	"errors"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// candidateNode is a node telling how far behind it is.
+type candidateNode struct {
+	*mock.Node
+	MetadataFn    func(context.Context) (node.Metadata, error)
+	EligibilityFn func(context.Context, []node.Spec) error
+}
+
+func (n *candidateNode) Metadata(ctx context.Context) (node.Metadata, error) {
+	return n.MetadataFn(ctx)
+}
+
+func (n *candidateNode) CheckEligibility(ctx context.Context, standbys []node.Spec) error {
+	return n.EligibilityFn(ctx, standbys)
+}
+
+func newCandidateNode(t *testing.T, spec *node.Spec) *candidateNode {
+	return &candidateNode{
+		Node:          mock.NewNode(t, spec),
+		MetadataFn:    func(context.Context) (node.Metadata, error) { return node.Metadata{}, nil },
+		EligibilityFn: func(context.Context, []node.Spec) error { return nil },
+	}
+}
+
+func TestEtcdElector_when_elected_but_lagging_then_yield(t *testing.T) {
+	t.Logf("Given a standby lagging behind another one")
+	{
+		delay := etcd.CampaignDelay
+		etcd.CampaignDelay = 10 * time.Millisecond
+		defer func() { etcd.CampaignDelay = delay }()
+
+		nd := newCandidateNode(t, &me)
+		nd.EligibilityFn = func(context.Context, []node.Spec) error { return errors.New("lagging") }
+		elector, connector := runElectorOn(t, nd, newDCS(me, candidate))
+
+		t.Logf("\tWhen it is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if !eventually(func() bool { return connector.Election.ResignHits == 1 && connector.Election.CampaignHits == 2 }) {
+				t.Fatalf("\t\t%s FAIL: yield, expected to resign and campaign again but actual is <%d> resignations and <%d> campaigns", testutils.Failed, connector.Election.ResignHits, connector.Election.CampaignHits)
+			}
+			if nd.LeadHits != 0 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected <0> but actual is <%d>", testutils.Failed, nd.LeadHits)
+			}
+			testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+			t.Logf("\t\t%s Then it must yield without leading.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_elected_and_up_to_date_then_lead(t *testing.T) {
+	t.Logf("Given the most up to date standby")
+	{
+		nd := newCandidateNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		compared := make(chan []string, 1)
+		nd.EligibilityFn = func(_ context.Context, standbys []node.Spec) error {
+			names := make([]string, 0)
+			for _, standby := range standbys {
+				names = append(names, standby.Name)
+			}
+			compared <- names
+			return nil
+		}
+		elector, connector := runElectorOn(t, nd, newDCS(me, candidate, third))
+
+		t.Logf("\tWhen it is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			expected := []string{candidate.Name, third.Name}
+			if actual := <-compared; !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: CheckEligibility, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then it must be compared with the other members.", testutils.Succeed)
+
+			if nd.LeadHits != 1 || connector.Election.ResignHits != 0 || elector.GetRole() != node.Primary {
+				t.Fatalf("\t\t%s FAIL: Lead, expected to lead but actual is <%d> leads, <%d> resignations and <%s>", testutils.Failed, nd.LeadHits, connector.Election.ResignHits, elector.GetRole())
+			}
+			t.Logf("\t\t%s Then it must lead.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_following_then_publish_the_metadata(t *testing.T) {
+	t.Logf("Given a standby")
+	{
+		interval := etcd.MetadataInterval
+		etcd.MetadataInterval = 10 * time.Millisecond
+		defer func() { etcd.MetadataInterval = interval }()
+
+		nd := newCandidateNode(t, &candidate)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		nd.MetadataFn = func(context.Context) (node.Metadata, error) {
+			return node.Metadata{ReceivedLSN: 4096, ReplayedLSN: 2048}, nil
+		}
+		d := newDCS(me, candidate)
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it follows the leader")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if !eventually(func() bool { return d.put(keyPrefix+candidate.Name) != "" }) {
+				t.Fatalf("\t\t%s FAIL: Put, expected to publish in <%s> but actual is nothing", testutils.Failed, keyPrefix+candidate.Name)
+			}
+			published, _ := node.Unmarshal([]byte(d.put(keyPrefix + candidate.Name)))
+			expected := candidate
+			expected.Metadata = node.Metadata{ReceivedLSN: 4096, ReplayedLSN: 2048}
+			if published != expected {
+				t.Fatalf("\t\t%s FAIL: Put, expected <%v> but actual is <%v>", testutils.Failed, expected, published)
+			}
+			t.Logf("\t\t%s Then its metadata must be published in its election key.", testutils.Succeed)
+		}
+	}
+}
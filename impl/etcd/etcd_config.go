@@ -2,8 +2,19 @@ package etcd
 
 import (
 	"context"
+	"fmt"
 	"github/mlyahmed.io/nominee/pkg/config"
 	"strings"
+	"time"
+)
+
+const (
+	// watchdogSafetyMargin is how long before the session expires the watchdog resets the host by default.
+	watchdogSafetyMargin = time.Second
+	// watchdogSessionTTL is the default session TTL with a watchdog, long enough for its timeout and the renewals.
+	watchdogSessionTTL = "10s"
+	// watchdogMinSessionTTL is the shortest session TTL which leaves room for a timeout of one second and the renewals.
+	watchdogMinSessionTTL = 4 * time.Second
 )
 
 type ConfigLoader interface {
@@ -14,10 +25,13 @@ type ConfigLoader interface {
 // ConfigSpec ...
 type ConfigSpec struct {
 	*config.BasicConfig
-	Endpoints []string
-	Username  string
-	Password  string
-	Loaded    bool
+	Endpoints       []string
+	Username        string
+	Password        string
+	SessionTTL      time.Duration
+	Watchdog        string
+	WatchdogTimeout time.Duration
+	Loaded          bool
 }
 
 // NewConfigLoader ...
@@ -31,9 +45,41 @@ func (conf *ConfigSpec) Load(ctx context.Context) {
 	conf.Endpoints = strings.Split(config.GetStringOrPanic("NOMINEE_ETCD_ENDPOINTS"), ",")
 	conf.Username = config.GetString("NOMINEE_ETCD_USERNAME")
 	conf.Password = config.GetString("NOMINEE_ETCD_PASSWORD")
+	conf.Watchdog = config.GetString("NOMINEE_WATCHDOG_DEVICE")
+	if conf.Watchdog != "" {
+		config.SetDefault("NOMINEE_ETCD_SESSION_TTL", watchdogSessionTTL)
+	} else {
+		config.SetDefault("NOMINEE_ETCD_SESSION_TTL", "1s")
+	}
+	conf.SessionTTL = getSecondsOrPanic("NOMINEE_ETCD_SESSION_TTL")
+	if conf.Watchdog != "" {
+		if minimum := watchdogMinSessionTTL; conf.SessionTTL < minimum {
+			panic(fmt.Sprintf("You must specify the env var NOMINEE_ETCD_SESSION_TTL to at least %v with the watchdog device %s.", minimum, conf.Watchdog))
+		}
+		conf.WatchdogTimeout = ((conf.SessionTTL - watchdogSafetyMargin) / 2).Truncate(time.Second)
+		if config.GetString("NOMINEE_WATCHDOG_TIMEOUT") != "" {
+			conf.WatchdogTimeout = getSecondsOrPanic("NOMINEE_WATCHDOG_TIMEOUT")
+		}
+		// The host must reset before the session expires, otherwise another node may lead meanwhile. The watchdog is
+		// fed while the timeout elapses before the TTL minus the margin since the last renewal, which the session
+		// renews every third of the TTL.
+		if limit := conf.SessionTTL - watchdogSafetyMargin - conf.SessionTTL/3; conf.WatchdogTimeout < time.Second || conf.WatchdogTimeout >= limit {
+			panic(fmt.Sprintf("You must specify the env var NOMINEE_WATCHDOG_TIMEOUT to whole seconds below %v with the session TTL %v.", limit, conf.SessionTTL))
+		}
+	}
 	conf.Loaded = true
 }
 
+// getSecondsOrPanic rejects the durations which are not a positive number of seconds, the granularity of the leases
+// and of the watchdog.
+func getSecondsOrPanic(key string) time.Duration {
+	duration, err := time.ParseDuration(config.GetStringOrPanic(key))
+	if err != nil || duration < time.Second || duration%time.Second != 0 {
+		panic(fmt.Sprintf("You must specify the env var %s to a positive number of seconds.", key))
+	}
+	return duration
+}
+
 func (conf *ConfigSpec) GetSpec() *ConfigSpec {
 	if !conf.Loaded {
 		panic("config not loaded.")
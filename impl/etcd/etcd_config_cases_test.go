@@ -1,12 +1,15 @@
 package etcd_test
 
 type configurationExample struct {
-	description string
-	cluster     string
-	domain      string
-	endpoints   string
-	username    string
-	password    string
+	description     string
+	cluster         string
+	domain          string
+	endpoints       string
+	username        string
+	password        string
+	sessionTTL      string
+	watchdog        string
+	watchdogTimeout string
 }
 
 var validExamples = []configurationExample{
@@ -23,6 +26,32 @@ var validExamples = []configurationExample{
 		endpoints:   "192.168.0.1:2378,192.168.0.2:2378,192.168.0.13:2378",
 		username:    "configure",
 		password:    "confi9ure",
+		sessionTTL:  "10s",
+		watchdog:    "/dev/watchdog",
+	},
+	{
+		description:     "the watchdog timeout is given",
+		cluster:         "cluster-004",
+		domain:          "domain-004",
+		endpoints:       "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:      "10s",
+		watchdog:        "/dev/watchdog",
+		watchdogTimeout: "5s",
+	},
+	{
+		description: "the watchdog without the session TTL",
+		cluster:     "cluster-005",
+		domain:      "domain-005",
+		endpoints:   "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		watchdog:    "/dev/watchdog",
+	},
+	{
+		description: "the watchdog with the shortest session TTL",
+		cluster:     "cluster-006",
+		domain:      "domain-006",
+		endpoints:   "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:  "4s",
+		watchdog:    "/dev/watchdog",
 	},
 	{
 		description: "full configuration",
@@ -50,4 +79,61 @@ var invalidExamples = []configurationExample{
 		cluster:     "nominee",
 		domain:      "postgres",
 	},
+	{
+		description: "the session TTL is not a number of seconds",
+		cluster:     "nominee",
+		domain:      "postgres",
+		endpoints:   "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:  "1500ms",
+	},
+	{
+		description: "the session TTL is zero",
+		cluster:     "nominee",
+		domain:      "postgres",
+		endpoints:   "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:  "0s",
+	},
+	{
+		description: "the session TTL leaves no room for the watchdog",
+		cluster:     "nominee",
+		domain:      "postgres",
+		endpoints:   "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:  "1s",
+		watchdog:    "/dev/watchdog",
+	},
+	{
+		description: "the session TTL leaves no room for the renewals of the lease",
+		cluster:     "nominee",
+		domain:      "postgres",
+		endpoints:   "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:  "3s",
+		watchdog:    "/dev/watchdog",
+	},
+	{
+		description:     "the watchdog timeout would elapse after the lease expires",
+		cluster:         "nominee",
+		domain:          "postgres",
+		endpoints:       "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:      "10s",
+		watchdog:        "/dev/watchdog",
+		watchdogTimeout: "6s",
+	},
+	{
+		description:     "the watchdog timeout is not below the session TTL",
+		cluster:         "nominee",
+		domain:          "postgres",
+		endpoints:       "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:      "10s",
+		watchdog:        "/dev/watchdog",
+		watchdogTimeout: "10s",
+	},
+	{
+		description:     "the watchdog timeout is not a number of seconds",
+		cluster:         "nominee",
+		domain:          "postgres",
+		endpoints:       "etcd-1:2378,etcd-2:2378,etcd-3:2378",
+		sessionTTL:      "10s",
+		watchdog:        "/dev/watchdog",
+		watchdogTimeout: "500ms",
+	},
 }
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestEtcdConfig_it_must_load_all_configurations(t *testing.T) {
@@ -37,6 +38,27 @@ func TestEtcdConfig_it_must_load_all_configurations(t *testing.T) {
 			if etcdConfig.Password != example.password {
 				t.Fatalf("\t\t%s FAIL: ConfigSpec.Password, expected <%s> but actual is <%s>", testutils.Failed, example.password, etcdConfig.Password)
 			}
+
+			defaultSessionTTL := "1s"
+			if example.watchdog != "" {
+				defaultSessionTTL = "10s"
+			}
+			expectedSessionTTL, _ := time.ParseDuration(orDefault(example.sessionTTL, defaultSessionTTL))
+			if etcdConfig.SessionTTL != expectedSessionTTL {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.SessionTTL, expected <%v> but actual is <%v>", testutils.Failed, expectedSessionTTL, etcdConfig.SessionTTL)
+			}
+
+			if etcdConfig.Watchdog != example.watchdog {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Watchdog, expected <%s> but actual is <%s>", testutils.Failed, example.watchdog, etcdConfig.Watchdog)
+			}
+
+			var expectedWatchdogTimeout time.Duration
+			if example.watchdog != "" {
+				expectedWatchdogTimeout, _ = time.ParseDuration(orDefault(example.watchdogTimeout, ((expectedSessionTTL - time.Second) / 2).Truncate(time.Second).String()))
+			}
+			if etcdConfig.WatchdogTimeout != expectedWatchdogTimeout {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.WatchdogTimeout, expected <%v> but actual is <%v>", testutils.Failed, expectedWatchdogTimeout, etcdConfig.WatchdogTimeout)
+			}
 		})
 	}
 }
@@ -63,6 +85,9 @@ func declareConfigurationExample(example configurationExample) {
 	_ = os.Setenv("NOMINEE_ETCD_ENDPOINTS", example.endpoints)
 	_ = os.Setenv("NOMINEE_ETCD_USERNAME", example.username)
 	_ = os.Setenv("NOMINEE_ETCD_PASSWORD", example.password)
+	_ = os.Setenv("NOMINEE_ETCD_SESSION_TTL", example.sessionTTL)
+	_ = os.Setenv("NOMINEE_WATCHDOG_DEVICE", example.watchdog)
+	_ = os.Setenv("NOMINEE_WATCHDOG_TIMEOUT", example.watchdogTimeout)
 }
 
 func tearsDown() {
@@ -71,4 +96,14 @@ func tearsDown() {
 	_ = os.Unsetenv("NOMINEE_ETCD_ENDPOINTS")
 	_ = os.Unsetenv("NOMINEE_ETCD_USERNAME")
 	_ = os.Unsetenv("NOMINEE_ETCD_PASSWORD")
+	_ = os.Unsetenv("NOMINEE_ETCD_SESSION_TTL")
+	_ = os.Unsetenv("NOMINEE_WATCHDOG_DEVICE")
+	_ = os.Unsetenv("NOMINEE_WATCHDOG_TIMEOUT")
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
 }
@@ -5,6 +5,8 @@ import (
 	"github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/clientv3/concurrency"
 	"github/mlyahmed.io/nominee/pkg/base"
+	"sync"
+	"time"
 )
 
 // Client ...
@@ -14,6 +16,12 @@ type Client interface {
 
 	// extracted from clientv3.KV
 	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error)
+	Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error)
+	Txn(ctx context.Context) clientv3.Txn
+
+	// extracted from clientv3.Lease
+	TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error)
 }
 
 // Election ...
@@ -23,6 +31,9 @@ type Election interface {
 
 	// extracted from concurrency.Observe
 	Observe(ctx context.Context) <-chan clientv3.GetResponse
+
+	// extracted from concurrency.Resign
+	Resign(ctx context.Context) error
 }
 
 // Connector ...
@@ -31,18 +42,24 @@ type Connector interface {
 	Connect(ctx context.Context, config *ConfigSpec) (Client, error)
 	NewElection(ctx context.Context, electionKey string) (Election, error)
 	ResumeElection(ctx context.Context, electionKey string, leader clientv3.GetResponse) (Election, error)
+	Lease() clientv3.LeaseID
+	// LastKeepAlive tells the lease of the session and when it was last renewed.
+	LastKeepAlive() (clientv3.LeaseID, time.Time)
 	Stop() base.DoneChan
 }
 
 // DefaultConnector ...
 type DefaultConnector struct {
-	client  *clientv3.Client
-	session *concurrency.Session
+	client    *clientv3.Client
+	session   *concurrency.Session
+	mutex     *sync.Mutex
+	lease     clientv3.LeaseID
+	renewedAt time.Time
 }
 
 // NewDefaultConnector ...
 func NewDefaultConnector() *DefaultConnector {
-	return &DefaultConnector{}
+	return &DefaultConnector{mutex: &sync.Mutex{}}
 }
 
 // Connect ...
@@ -57,13 +74,39 @@ func (server *DefaultConnector) Connect(ctx context.Context, config *ConfigSpec)
 		}
 	}
 
-	if server.session, err = concurrency.NewSession(server.client, concurrency.WithTTL(1), concurrency.WithContext(ctx)); err != nil {
+	if server.session, err = concurrency.NewSession(server.client, concurrency.WithTTL(int(config.SessionTTL/time.Second)), concurrency.WithContext(ctx)); err != nil {
+		return nil, err
+	}
+	if err := server.trackKeepAlives(ctx, server.session.Lease()); err != nil {
 		return nil, err
 	}
 
 	return server.client, nil
 }
 
+// trackKeepAlives records when the lease is renewed. The session renews it on its own, the client hands the responses
+// to each of the keep-alive channels of the lease.
+func (server *DefaultConnector) trackKeepAlives(ctx context.Context, lease clientv3.LeaseID) error {
+	server.mutex.Lock()
+	server.lease, server.renewedAt = lease, time.Now()
+	server.mutex.Unlock()
+
+	responses, err := server.client.KeepAlive(ctx, lease)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for range responses {
+			server.mutex.Lock()
+			if server.lease == lease {
+				server.renewedAt = time.Now()
+			}
+			server.mutex.Unlock()
+		}
+	}()
+	return nil
+}
+
 // NewElection ...
 func (server *DefaultConnector) NewElection(_ context.Context, electionKey string) (Election, error) {
 	e := concurrency.NewElection(server.session, electionKey)
@@ -76,6 +119,18 @@ func (server *DefaultConnector) ResumeElection(_ context.Context, electionKey st
 	return e, nil
 }
 
+// Lease is the lease of the session, the keys put with it are deleted when the session ends.
+func (server *DefaultConnector) Lease() clientv3.LeaseID {
+	return server.session.Lease()
+}
+
+// LastKeepAlive ...
+func (server *DefaultConnector) LastKeepAlive() (clientv3.LeaseID, time.Time) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.lease, server.renewedAt
+}
+
 // Stop ...
 func (server *DefaultConnector) Stop() base.DoneChan {
 	return server.session.Done()
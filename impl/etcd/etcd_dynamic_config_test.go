@@ -0,0 +1,179 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	configKey         = "nominee/config/domain/domain-001/cluster/cluster-001"
+	pendingRestartKey = "nominee/pending/domain/domain-001/cluster/cluster-001/"
+)
+
+// configurerNode is a node applying the cluster-wide configuration.
+type configurerNode struct {
+	*mock.Node
+	mutex      *sync.Mutex
+	configured chan node.Parameters
+	pending    bool
+}
+
+func (n *configurerNode) Configure(_ context.Context, parameters node.Parameters) error {
+	n.configured <- parameters
+	return nil
+}
+
+func (n *configurerNode) PendingRestart(context.Context) (bool, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.pending, nil
+}
+
+func (n *configurerNode) waitForRestart(pending bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.pending = pending
+}
+
+func newConfigurerNode(t *testing.T, spec *node.Spec) *configurerNode {
+	nd := &configurerNode{Node: mock.NewNode(t, spec), mutex: &sync.Mutex{}, configured: make(chan node.Parameters, 1)}
+	nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+	return nd
+}
+
+func (d *dcs) editConfig(value string) {
+	d.configs <- clientv3.WatchResponse{Events: []*clientv3.Event{
+		{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte(configKey), Value: []byte(value)}},
+	}}
+}
+
+func (d *dcs) hasPut(key string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	_, ok := d.puts[key]
+	return ok
+}
+
+func (d *dcs) watchedFromRevision(key string) int64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.watchedFrom[key]
+}
+
+func fastConfig(t *testing.T) {
+	interval := etcd.ConfigInterval
+	etcd.ConfigInterval = 10 * time.Millisecond
+	t.Cleanup(func() { etcd.ConfigInterval = interval })
+}
+
+func expectConfigured(t *testing.T, nd *configurerNode, expected node.Parameters) {
+	select {
+	case actual := <-nd.configured:
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("\t\t%s FAIL: Configure, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("\t\t%s FAIL: Configure, expected <%v> but actual is nothing", testutils.Failed, expected)
+	}
+}
+
+func TestEtcdElector_when_the_node_runs_then_apply_the_config(t *testing.T) {
+	t.Logf("Given a cluster-wide configuration")
+	{
+		fastConfig(t)
+		nd := newConfigurerNode(t, &me)
+		d := newDCS(candidate, me)
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(configKey), Value: []byte(`{"Parameters":{"work_mem":"16MB"}}`)})
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen the node does not run yet")
+		{
+			select {
+			case actual := <-nd.configured:
+				t.Fatalf("\t\t%s FAIL: Configure, expected no call but actual is <%v>", testutils.Failed, actual)
+			case <-time.After(50 * time.Millisecond):
+			}
+			t.Logf("\t\t%s Then the configuration must not be applied.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen it follows")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+
+			expectConfigured(t, nd, node.Parameters{"work_mem": "16MB"})
+			t.Logf("\t\t%s Then the configuration must be applied.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the configuration is edited")
+		{
+			d.editConfig(`{"Parameters":{"work_mem":"16MB","max_connections":"200"}}`)
+
+			expectConfigured(t, nd, node.Parameters{"work_mem": "16MB", "max_connections": "200"})
+			t.Logf("\t\t%s Then the new configuration must be applied.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_the_config_is_read_then_watch_it_from_the_next_revision(t *testing.T) {
+	t.Logf("Given a cluster-wide configuration read at a revision")
+	{
+		nd := newConfigurerNode(t, &me)
+		d := newDCS(candidate, me)
+		d.revision = 42
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(configKey), Value: []byte(`{"Parameters":{"work_mem":"16MB"}}`), ModRevision: 40})
+
+		t.Logf("\tWhen the node runs")
+		{
+			runElectorOn(t, nd, d)
+
+			if !eventually(func() bool { return d.watchedFromRevision(configKey) == 43 }) {
+				t.Fatalf("\t\t%s FAIL: Watch, expected from the revision <43> but actual is <%d>", testutils.Failed, d.watchedFromRevision(configKey))
+			}
+			t.Logf("\t\t%s Then the edits made since the read must not be missed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_the_config_waits_for_a_restart_then_flag_the_node(t *testing.T) {
+	t.Logf("Given a follower with a cluster-wide configuration")
+	{
+		fastConfig(t)
+		nd := newConfigurerNode(t, &me)
+		d := newDCS(candidate, me)
+		_, connector := runElectorOn(t, nd, d)
+		connector.Election.PushLeader(leaderResponse(candidate))
+		expectConfigured(t, nd, nil)
+
+		t.Logf("\tWhen a parameter waits for a restart")
+		{
+			nd.waitForRestart(true)
+			d.editConfig(`{"Parameters":{"max_connections":"200"}}`)
+			expectConfigured(t, nd, node.Parameters{"max_connections": "200"})
+
+			if !eventually(func() bool { return d.hasPut(pendingRestartKey + me.Name) }) {
+				t.Fatalf("\t\t%s FAIL: Put, expected the key <%s> but actual is none", testutils.Failed, pendingRestartKey+me.Name)
+			}
+			t.Logf("\t\t%s Then the node must be flagged pending restart.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the node is restarted")
+		{
+			nd.waitForRestart(false)
+
+			expected := []string{pendingRestartKey + me.Name}
+			if !eventually(func() bool { return reflect.DeepEqual(d.deletedKeys(), expected) }) {
+				t.Fatalf("\t\t%s FAIL: Delete, expected <%v> but actual is <%v>", testutils.Failed, expected, d.deletedKeys())
+			}
+			t.Logf("\t\t%s Then the flag must be deleted.", testutils.Succeed)
+		}
+	}
+}
@@ -2,19 +2,46 @@ package etcd
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
 	"github.com/sirupsen/logrus"
 	"github/mlyahmed.io/nominee/pkg/election"
 	"github/mlyahmed.io/nominee/pkg/logger"
 	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/watchdog"
+	"sync"
+	"time"
+)
+
+var (
+	// SwitchoverTimeout is how long the leader waits for the candidate to be the next in line before it gives up.
+	SwitchoverTimeout = 30 * time.Second
+	// MetadataInterval is how often a follower publishes its metadata in its election key.
+	MetadataInterval = time.Second
+	// CampaignDelay is how long a node waits per point of priority before it campaigns.
+	CampaignDelay = time.Second
+	// SyncInterval is how often the leader checks its synchronous standby.
+	SyncInterval = time.Second
+	// SlotsInterval is how often the leader checks the slots of its followers.
+	SlotsInterval = 5 * time.Second
+	// ConfigInterval is how often a node checks whether the configuration waits for a restart, or failed to apply.
+	ConfigInterval = time.Second
 )
 
 // Elector ...
 type Elector struct {
 	*Etcd
 	*election.DefaultElector
-	leader   clientv3.GetResponse
-	election Election
+	Watchdog       watchdog.Watchdog
+	leader         clientv3.GetResponse
+	election       Election
+	client         Client
+	mutex          *sync.Mutex
+	cancelCampaign context.CancelFunc
+	campaignDone   chan struct{}
+	leading        bool
 }
 
 // NewElector ...
@@ -22,7 +49,10 @@ func NewElector(cl ConfigLoader) *Elector {
 	cl.Load(context.Background())
 	spec := cl.GetSpec()
 	log = logger.G(context.Background()).WithFields(logrus.Fields{"elector": "etcd", "domain": spec.Domain, "cluster": spec.Cluster})
-	elector := Elector{Etcd: NewEtcd(cl)}
+	elector := Elector{Etcd: NewEtcd(cl), mutex: &sync.Mutex{}}
+	if spec.Watchdog != "" {
+		elector.Watchdog = watchdog.NewDevice(spec.Watchdog, spec.WatchdogTimeout)
+	}
 	elector.failBackFn = func() error { return elector.connect(true) }
 	return &elector
 }
@@ -41,14 +71,23 @@ func (e *Elector) Run(n node.Node) error {
 	return nil
 }
 
+// Cleanup disarms the watchdog, the node is stopped by now.
+func (e *Elector) Cleanup() {
+	e.disarmWatchdog()
+	e.Etcd.Cleanup()
+}
+
 func (e *Elector) connect(reconnect bool) error {
 	if reconnect {
+		e.setLeading(false)
 		e.Reset()
 	}
 
-	if _, err := e.Connector.Connect(e.Ctx, e.ConfigSpec); err != nil {
+	client, err := e.Connector.Connect(e.Ctx, e.ConfigSpec)
+	if err != nil {
 		return err
 	}
+	e.client = client
 
 	if len(e.leader.Kvs) > 0 {
 		log.Infof("resume election...")
@@ -58,28 +97,641 @@ func (e *Elector) connect(reconnect bool) error {
 		e.election, _ = e.Connector.NewElection(e.Ctx, e.electionKey())
 	}
 
-	e.campaign()
+	if e.Managed.GetSpec().Tags.NoFailover {
+		if err := e.register(); err != nil {
+			return err
+		}
+	} else {
+		e.campaign()
+	}
 	e.observe()
+	e.watchSwitchover()
+	e.publishMetadata()
+	e.synchronize()
+	e.keepSlots()
+	if err := e.watchPause(); err != nil {
+		return err
+	}
+	if err := e.watchConfig(); err != nil {
+		return err
+	}
 	log.Infof("session created.")
 	return nil
 }
 
 func (e *Elector) campaign() {
+	parent := e.Ctx
+	ctx, cancel := context.WithCancel(parent)
+	done := make(chan struct{})
+	e.mutex.Lock()
+	e.cancelCampaign, e.campaignDone = cancel, done
+	e.mutex.Unlock()
+
 	go func() {
+		defer close(done)
+		if delay := time.Duration(e.Managed.GetSpec().Tags.Priority) * CampaignDelay; delay > 0 {
+			log.Infof("campaign in %v...", delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+		}
 		log.Infof("campaign as %v...", e.Managed.GetName())
-		if err := e.election.Campaign(e.Ctx, e.Managed.GetSpec().Marshal()); err != nil {
+		err := e.election.Campaign(ctx, e.Managed.GetSpec().Marshal())
+		requeued := ctx.Err() != nil && parent.Err() == nil
+		if err != nil && !requeued {
 			e.Stonith(context.TODO())
 		}
 	}()
 }
 
+// register makes a node which never runs for the election known to the others, as long as its session lives.
+func (e *Elector) register() error {
+	log.Infof("register as %v without campaigning...", e.Managed.GetName())
+	_, err := e.client.Put(e.Ctx, e.replicaKey()+"/"+e.Managed.GetName(), e.Managed.GetSpec().Marshal(), clientv3.WithLease(e.Connector.Lease()))
+	return err
+}
+
+// requeue withdraws the candidacy and campaigns again, so that the candidates already waiting come first.
+func (e *Elector) requeue() {
+	e.mutex.Lock()
+	cancel, done := e.cancelCampaign, e.campaignDone
+	e.mutex.Unlock()
+	cancel()
+	<-done
+	e.campaign()
+}
+
+func (e *Elector) watchSwitchover() {
+	go func() {
+		watch := e.client.Watch(e.Ctx, e.switchoverKey())
+		for response := range watch {
+			for _, event := range response.Events {
+				if event.Type != mvccpb.PUT {
+					continue
+				}
+				intent := Switchover{}
+				if err := json.Unmarshal(event.Kv.Value, &intent); err != nil {
+					log.Warnf("switchover: invalid intent %s: %v", event.Kv.Value, err)
+					continue
+				}
+				e.onSwitchover(intent)
+			}
+		}
+	}()
+}
+
+// watchPause applies the pause flags set when connecting then follows their changes.
+func (e *Elector) watchPause() error {
+	response, err := e.client.Get(e.Ctx, e.pauseKey(), clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	// The keys deleted while the session was down are resumed, as the watch only tells the events after the read.
+	present := make(map[string]bool)
+	for _, kv := range response.Kvs {
+		present[string(kv.Key)] = true
+	}
+	for _, key := range []string{e.pauseKey(), e.nodePauseKey(e.Managed.GetName())} {
+		e.onPause(key, present[key])
+	}
+
+	options := []clientv3.OpOption{clientv3.WithPrefix()}
+	if response.Header != nil {
+		options = append(options, clientv3.WithRev(response.Header.Revision+1))
+	}
+	go func() {
+		watch := e.client.Watch(e.Ctx, e.pauseKey(), options...)
+		for response := range watch {
+			for _, event := range response.Events {
+				e.onPause(string(event.Kv.Key), event.Type == mvccpb.PUT)
+			}
+		}
+	}()
+	return nil
+}
+
+func (e *Elector) onPause(key string, paused bool) {
+	switch key {
+	case e.pauseKey():
+		log.Infof("pause: cluster paused <%v>.", paused)
+		e.PauseCluster(paused)
+	case e.nodePauseKey(e.Managed.GetName()):
+		log.Infof("pause: node paused <%v>.", paused)
+		e.PauseNode(paused)
+	}
+}
+
+func (e *Elector) onSwitchover(intent Switchover) {
+	me := e.Managed.GetName()
+	leader := e.GetLeader()
+	switch {
+	case intent.Candidate == me:
+		log.Infof("switchover: waiting to take over from %s...", intent.Leader)
+	case leader != nil && leader.Name == me:
+		if err := e.switchover(intent.Candidate); err != nil {
+			log.Errorf("switchover to %s rejected: %v", intent.Candidate, err)
+		}
+		if _, err := e.client.Delete(e.Ctx, e.switchoverKey()); err != nil {
+			log.Warnf("switchover: failed to clear the intent: %v", err)
+		}
+	case e.Managed.GetSpec().Tags.NoFailover:
+		log.Infof("switchover: not running for the election, nothing to do.")
+	default:
+		log.Infof("switchover: stepping back for %s...", intent.Candidate)
+		e.requeue()
+	}
+}
+
+// switchover checks the candidate, waits for it to be the next in line, then demotes the node and resigns.
+func (e *Elector) switchover(name string) error {
+	candidate, err := e.nextInLine(name)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("switchover: handing over to %s...", name)
+	if err := e.StepDown(e.Ctx); err != nil {
+		return err
+	}
+
+	if err := e.election.Resign(e.Ctx); err != nil {
+		e.Stonith(context.TODO())
+		return err
+	}
+	log.Infof("switchover: resigned in favor of %s.", candidate.Name)
+	e.campaign()
+	return nil
+}
+
+func (e *Elector) nextInLine(name string) (*node.Spec, error) {
+	deadline := time.Now().Add(SwitchoverTimeout)
+	checked := false
+	for {
+		members, err := e.members(e.Ctx, e.client)
+		if err != nil {
+			return nil, err
+		}
+
+		index := indexOfName(members, name)
+		if index < 0 {
+			return nil, fmt.Errorf("%s is not a member of the election", name)
+		}
+
+		candidate := members[index].Spec
+		if !checked {
+			if err := e.CheckSwitchover(e.Ctx, candidate); err != nil {
+				return nil, err
+			}
+			checked = true
+		}
+		if index == 1 {
+			return &candidate, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is still not the next in line after %v", name, SwitchoverTimeout)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
 func (e *Elector) observe() {
 	go func() {
 		o := e.election.Observe(e.Ctx)
 		for leader := range o {
 			e.leader = leader
 			spec := e.toNodeSpec(leader)
+			e.setLeading(spec.Name == e.Managed.GetName())
+			if spec.Name == e.Managed.GetName() && e.GetRole() != node.Primary {
+				if err := e.checkEligibility(); err != nil {
+					e.yield(err)
+					continue
+				}
+				e.armWatchdog()
+			} else if spec.Name != e.Managed.GetName() && spec.Name != "" {
+				e.cloneFrom()
+			}
+			wasPrimary := e.GetRole() == node.Primary
 			_ = e.UpdateLeader(&spec)
+			if e.GetRole() != node.Primary {
+				e.disarmWatchdog()
+			} else if !wasPrimary {
+				e.initialize()
+			}
 		}
 	}()
 }
+
+// armWatchdog arms the watchdog before leading and feeds it while the node leads and the lease of its session is
+// renewed, so that the host resets when the leader is stuck without a session. The feeding stops once the watchdog
+// timeout would no longer elapse before the TTL minus the safety margin since the last renewal, so that the host resets
+// before the lease expires and another node leads. It stops too on reconnect, as the leadership went with the former
+// lease.
+func (e *Elector) armWatchdog() {
+	if e.Watchdog == nil {
+		return
+	}
+	lease, _ := e.Connector.LastKeepAlive()
+	alive := func() bool {
+		current, renewedAt := e.Connector.LastKeepAlive()
+		return e.isLeading() && current == lease && time.Since(renewedAt)+e.WatchdogTimeout < e.SessionTTL-watchdogSafetyMargin
+	}
+	if err := e.Watchdog.Arm(alive); err != nil {
+		log.Errorf("watchdog: failed to arm: %v", err)
+	}
+}
+
+func (e *Elector) disarmWatchdog() {
+	if e.Watchdog == nil {
+		return
+	}
+	if err := e.Watchdog.Disarm(); err != nil {
+		log.Errorf("watchdog: failed to disarm: %v", err)
+	}
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.leading = leading
+}
+
+func (e *Elector) isLeading() bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.leading
+}
+
+// checkEligibility asks the node whether it is up to date enough to lead, compared with the other members. When a
+// synchronous standby is recorded, only it or the former leader can lead. The node must have the data of the cluster.
+func (e *Elector) checkEligibility() error {
+	if initializer, ok := e.Managed.(node.Initializer); ok {
+		systemID, err := e.systemID(e.Ctx, e.client)
+		if err != nil {
+			return err
+		}
+		if err := initializer.CheckInitialized(e.Ctx, systemID); err != nil {
+			return err
+		}
+	}
+
+	record, err := e.sync(e.Ctx, e.client)
+	if err != nil {
+		return err
+	}
+	me := e.Managed.GetName()
+	if record != nil && record.SyncStandby != "" && record.SyncStandby != me && record.Leader != me {
+		return fmt.Errorf("%s is the synchronous standby of %s", record.SyncStandby, record.Leader)
+	}
+
+	candidate, ok := e.Managed.(node.Candidate)
+	if !ok {
+		return nil
+	}
+	standbys, err := e.followers()
+	if err != nil {
+		return err
+	}
+	return candidate.CheckEligibility(e.Ctx, standbys)
+}
+
+// initialize records the system identifier of the first leader, the one which has run initdb.
+func (e *Elector) initialize() {
+	initializer, ok := e.Managed.(node.Initializer)
+	if !ok {
+		return
+	}
+	recorded, err := e.systemID(e.Ctx, e.client)
+	if err != nil || recorded != "" {
+		return
+	}
+	systemID, err := initializer.SystemID(e.Ctx)
+	if err != nil || systemID == "" {
+		log.Warnf("initialize: failed to get the system identifier: %v", err)
+		return
+	}
+	key := e.initializeKey()
+	response, err := e.client.Txn(e.Ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, systemID)).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		log.Warnf("initialize: failed to record %s: %v", systemID, err)
+		return
+	}
+	if !response.Succeeded {
+		// Another leader has recorded its own meanwhile, the data of the node belong to another cluster.
+		if recorded := recordedValue(response); recorded != systemID {
+			e.yield(fmt.Errorf("initialize: the cluster is initialized with %s, not %s", recorded, systemID))
+		}
+		return
+	}
+	log.Infof("initialize: cluster initialized with %s.", systemID)
+}
+
+// recordedValue is the value read by the Else branch of a transaction.
+func recordedValue(response *clientv3.TxnResponse) string {
+	for _, op := range response.Responses {
+		if get := op.GetResponseRange(); get != nil && len(get.Kvs) > 0 {
+			return string(get.Kvs[0].Value)
+		}
+	}
+	return ""
+}
+
+// yield resigns before leading and campaigns again, so that a more up to date member takes the leadership.
+func (e *Elector) yield(reason error) {
+	log.Warnf("yield the leadership: %v", reason)
+	if err := e.election.Resign(e.Ctx); err != nil {
+		e.Stonith(context.TODO())
+		return
+	}
+	select {
+	case <-time.After(CampaignDelay):
+	case <-e.Ctx.Done():
+		return
+	}
+	e.requeue()
+}
+
+// publishMetadata refreshes the metadata in the election key of the node while it follows.
+func (e *Elector) publishMetadata() {
+	candidate, ok := e.Managed.(node.Candidate)
+	if !ok {
+		return
+	}
+	go func() {
+		published := node.Metadata{}
+		ticker := time.NewTicker(MetadataInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-e.Ctx.Done():
+				return
+			}
+			if e.GetRole() != node.Replica {
+				continue
+			}
+			metadata, err := candidate.Metadata(e.Ctx)
+			if err != nil {
+				log.Warnf("failed to get the metadata: %v", err)
+				continue
+			}
+			if metadata == published {
+				continue
+			}
+			if err := e.putMetadata(metadata); err != nil {
+				log.Warnf("failed to publish the metadata: %v", err)
+				continue
+			}
+			published = metadata
+		}
+	}()
+}
+
+func (e *Elector) putMetadata(metadata node.Metadata) error {
+	spec := *e.Managed.GetSpec()
+	spec.ElectionKey, spec.Metadata = "", metadata
+	if spec.Tags.NoFailover {
+		_, err := e.client.Put(e.Ctx, e.replicaKey()+"/"+spec.Name, spec.Marshal(), clientv3.WithIgnoreLease())
+		return err
+	}
+
+	members, err := e.members(e.Ctx, e.client)
+	if err != nil {
+		return err
+	}
+	index := indexOfName(members, e.Managed.GetName())
+	if index < 0 {
+		return fmt.Errorf("%s is not a member of the election", e.Managed.GetName())
+	}
+	_, err = e.client.Put(e.Ctx, members[index].ElectionKey, spec.Marshal(), clientv3.WithIgnoreLease())
+	return err
+}
+
+// synchronize has the leader pick its synchronous standby among the followers and record it once it is synchronous.
+// On failure, the last record stays, so that the failover still goes to the standby which had all the writes.
+func (e *Elector) synchronize() {
+	synchronizer, ok := e.Managed.(node.Synchronizer)
+	if !ok || !synchronizer.Synchronous() {
+		return
+	}
+	go func() {
+		recorded := Sync{}
+		ticker := time.NewTicker(SyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-e.Ctx.Done():
+				return
+			}
+			if e.GetRole() != node.Primary {
+				recorded = Sync{}
+				continue
+			}
+			followers, err := e.followers()
+			if err != nil {
+				log.Warnf("sync: failed to get the followers: %v", err)
+				continue
+			}
+			name, err := synchronizer.Synchronize(e.Ctx, followers)
+			if err != nil {
+				log.Warnf("sync: %v", err)
+				continue
+			}
+			record := Sync{Leader: e.Managed.GetName(), SyncStandby: name}
+			if record == recorded {
+				continue
+			}
+			value, _ := json.Marshal(record)
+			if _, err := e.client.Put(e.Ctx, e.syncKey(), string(value)); err != nil {
+				log.Warnf("sync: failed to record %s: %v", name, err)
+				continue
+			}
+			log.Infof("sync: %s is the synchronous standby.", name)
+			recorded = record
+		}
+	}()
+}
+
+// cloneFrom tells the node the members tagged clonefrom, running for the election or not, before it follows.
+func (e *Elector) cloneFrom() {
+	cloner, ok := e.Managed.(node.Cloner)
+	if !ok {
+		return
+	}
+	followers, err := e.followers()
+	if err != nil {
+		log.Warnf("clonefrom: failed to get the followers: %v", err)
+		return
+	}
+	replicas, err := e.replicas(e.Ctx, e.client)
+	if err != nil {
+		log.Warnf("clonefrom: failed to get the replicas: %v", err)
+		return
+	}
+	for _, replica := range replicas {
+		if replica.Name != e.Managed.GetName() {
+			followers = append(followers, replica.Spec)
+		}
+	}
+	members := make([]node.Spec, 0, len(followers))
+	for _, follower := range followers {
+		if follower.Tags.CloneFrom {
+			members = append(members, follower)
+		}
+	}
+	cloner.CloneFrom(members)
+}
+
+// followers lists the other members of the election, the nodes which never run for the election cannot take over.
+func (e *Elector) followers() ([]node.Spec, error) {
+	members, err := e.members(e.Ctx, e.client)
+	if err != nil {
+		return nil, err
+	}
+	followers := make([]node.Spec, 0, len(members))
+	for _, member := range members {
+		if member.Name != e.Managed.GetName() {
+			followers = append(followers, member.Spec)
+		}
+	}
+	return followers, nil
+}
+
+// keepSlots has the leader keep the changes its followers, running for the election or not, have not received yet.
+func (e *Elector) keepSlots() {
+	keeper, ok := e.Managed.(node.SlotKeeper)
+	if !ok {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(SlotsInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-e.Ctx.Done():
+				return
+			}
+			if e.GetRole() != node.Primary {
+				continue
+			}
+			followers, err := e.followers()
+			if err != nil {
+				log.Warnf("slots: failed to get the followers: %v", err)
+				continue
+			}
+			replicas, err := e.replicas(e.Ctx, e.client)
+			if err != nil {
+				log.Warnf("slots: failed to get the replicas: %v", err)
+				continue
+			}
+			for _, replica := range replicas {
+				followers = append(followers, replica.Spec)
+			}
+			if err := keeper.KeepSlots(e.Ctx, followers); err != nil {
+				log.Warnf("slots: %v", err)
+			}
+		}
+	}()
+}
+
+// watchConfig has the node apply the cluster-wide configuration once it runs, again on each change or role change, and
+// flags the node under the pending restart key, as long as its session lives, while some parameters wait for a restart.
+func (e *Elector) watchConfig() error {
+	configurer, ok := e.Managed.(node.Configurer)
+	if !ok {
+		return nil
+	}
+	config, revision, err := e.config(e.Ctx, e.client)
+	if err != nil {
+		return err
+	}
+
+	var options []clientv3.OpOption
+	if revision > 0 {
+		options = append(options, clientv3.WithRev(revision+1))
+	}
+	updates := make(chan *DynamicConfig, 1)
+	updates <- config
+	go func() {
+		watch := e.client.Watch(e.Ctx, e.configKey(), options...)
+		for response := range watch {
+			for _, event := range response.Events {
+				update := &DynamicConfig{}
+				if event.Type == mvccpb.PUT {
+					parsed, err := toDynamicConfig(event.Kv.Value)
+					if err != nil {
+						log.Warnf("config: invalid configuration %s: %v", event.Kv.Value, err)
+						continue
+					}
+					update = parsed
+				}
+				select {
+				case <-updates:
+				default:
+				}
+				updates <- update
+			}
+		}
+	}()
+
+	go func() {
+		var applied *DynamicConfig
+		appliedAs, pending := node.Unknown, false
+		ticker := time.NewTicker(ConfigInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case config = <-updates:
+				applied = nil
+			case <-ticker.C:
+			case <-e.Ctx.Done():
+				return
+			}
+			role := e.GetRole()
+			if role != node.Primary && role != node.Replica {
+				continue
+			}
+			if applied != config || appliedAs != role {
+				if err := configurer.Configure(e.Ctx, config.Parameters); err != nil {
+					log.Warnf("config: %v", err)
+					continue
+				}
+				log.Infof("config: %d parameters applied.", len(config.Parameters))
+				applied, appliedAs = config, role
+			}
+
+			restart, err := configurer.PendingRestart(e.Ctx)
+			if err != nil {
+				log.Warnf("config: %v", err)
+				continue
+			}
+			if restart == pending {
+				continue
+			}
+			if err := e.flagPendingRestart(restart); err != nil {
+				log.Warnf("config: failed to flag the pending restart: %v", err)
+				continue
+			}
+			log.Infof("config: pending restart <%v>.", restart)
+			pending = restart
+		}
+	}()
+	return nil
+}
+
+func (e *Elector) flagPendingRestart(pending bool) error {
+	key := e.pendingRestartKey() + "/" + e.Managed.GetName()
+	if !pending {
+		_, err := e.client.Delete(e.Ctx, key)
+		return err
+	}
+	_, err := e.client.Put(e.Ctx, key, "", clientv3.WithLease(e.Connector.Lease()))
+	return err
+}
@@ -0,0 +1,149 @@
+package etcd_test
+
+import (
+	"context"
+	"errors"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"testing"
+	"time"
+)
+
+const initializeKey = "nominee/initialize/domain/domain-001/cluster/cluster-001"
+
+// initializerNode is a node whose data belong to a cluster.
+type initializerNode struct {
+	*mock.Node
+	systemID string
+	checked  chan string
+}
+
+func (n *initializerNode) CheckInitialized(_ context.Context, systemID string) error {
+	n.checked <- systemID
+	if systemID != "" && systemID != n.systemID {
+		return errors.New("another cluster")
+	}
+	return nil
+}
+
+func (n *initializerNode) SystemID(context.Context) (string, error) {
+	return n.systemID, nil
+}
+
+func newInitializerNode(t *testing.T, spec *node.Spec, systemID string) *initializerNode {
+	nd := &initializerNode{Node: mock.NewNode(t, spec), systemID: systemID, checked: make(chan string, 1)}
+	nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+	return nd
+}
+
+func (d *dcs) initialize(systemID string) {
+	d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(initializeKey), Value: []byte(systemID)})
+}
+
+func TestEtcdElector_when_the_first_leader_then_record_the_system_identifier(t *testing.T) {
+	t.Logf("Given a cluster not initialized yet")
+	{
+		nd := newInitializerNode(t, &me, "6930542190186614812")
+		d := newDCS(me, candidate)
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen a node leads")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if checked := <-nd.checked; checked != "" || nd.LeadHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected to lead a cluster not initialized but actual is <%s> and <%d> leads", testutils.Failed, checked, nd.LeadHits)
+			}
+			if !eventually(func() bool { return d.put(initializeKey) == nd.systemID }) {
+				t.Fatalf("\t\t%s FAIL: Put, expected <%s> but actual is <%s>", testutils.Failed, nd.systemID, d.put(initializeKey))
+			}
+			t.Logf("\t\t%s Then its system identifier must be recorded.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_initialized_with_the_same_system_identifier_then_lead(t *testing.T) {
+	t.Logf("Given a node with the data of the cluster")
+	{
+		nd := newInitializerNode(t, &me, "6930542190186614812")
+		d := newDCS(me, candidate)
+		d.initialize(nd.systemID)
+		elector, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if checked := <-nd.checked; checked != nd.systemID {
+				t.Fatalf("\t\t%s FAIL: CheckInitialized, expected <%s> but actual is <%s>", testutils.Failed, nd.systemID, checked)
+			}
+			if nd.LeadHits != 1 || elector.GetRole() != node.Primary {
+				t.Fatalf("\t\t%s FAIL: Lead, expected to lead but actual is <%d> leads and <%s>", testutils.Failed, nd.LeadHits, elector.GetRole())
+			}
+			if actual := d.put(initializeKey); actual != "" {
+				t.Fatalf("\t\t%s FAIL: Put, expected nothing but actual is <%s>", testutils.Failed, actual)
+			}
+			t.Logf("\t\t%s Then it must lead without recording it again.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_initialized_with_another_system_identifier_then_yield(t *testing.T) {
+	t.Logf("Given a wiped node while the cluster is initialized")
+	{
+		delay := etcd.CampaignDelay
+		etcd.CampaignDelay = 10 * time.Millisecond
+		defer func() { etcd.CampaignDelay = delay }()
+
+		nd := newInitializerNode(t, &me, "")
+		d := newDCS(me, candidate)
+		d.initialize("6930542190186614812")
+		elector, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if !eventually(func() bool { return connector.Election.ResignHits == 1 && connector.Election.CampaignHits == 2 }) {
+				t.Fatalf("\t\t%s FAIL: yield, expected to resign and campaign again but actual is <%d> resignations and <%d> campaigns", testutils.Failed, connector.Election.ResignHits, connector.Election.CampaignHits)
+			}
+			if nd.LeadHits != 0 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected <0> but actual is <%d>", testutils.Failed, nd.LeadHits)
+			}
+			testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+			t.Logf("\t\t%s Then it must yield without leading.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_another_leader_initialized_the_cluster_meanwhile_then_yield(t *testing.T) {
+	t.Logf("Given a first leader racing with another one")
+	{
+		delay := etcd.CampaignDelay
+		etcd.CampaignDelay = 10 * time.Millisecond
+		defer func() { etcd.CampaignDelay = delay }()
+
+		nd := newInitializerNode(t, &me, "6930542190186614812")
+		d := newDCS(me, candidate)
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen the other one records its system identifier first")
+		{
+			d.mutex.Lock()
+			d.puts[initializeKey] = "6930542190186614999"
+			d.mutex.Unlock()
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if !eventually(func() bool { return connector.Election.ResignHits == 1 && connector.Election.CampaignHits == 2 }) {
+				t.Fatalf("\t\t%s FAIL: yield, expected to resign and campaign again but actual is <%d> resignations and <%d> campaigns", testutils.Failed, connector.Election.ResignHits, connector.Election.CampaignHits)
+			}
+			if actual := d.put(initializeKey); actual != "6930542190186614999" {
+				t.Fatalf("\t\t%s FAIL: Txn, expected <%s> to be kept but actual is <%s>", testutils.Failed, "6930542190186614999", actual)
+			}
+			t.Logf("\t\t%s Then it must not overwrite it and must yield.", testutils.Succeed)
+		}
+	}
+}
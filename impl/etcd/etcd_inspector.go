@@ -0,0 +1,297 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"strings"
+)
+
+// The roles of the members.
+const (
+	LeaderRole      = "leader"
+	FollowerRole    = "follower"
+	SyncStandbyRole = "sync_standby"
+)
+
+// The types of the changes.
+const (
+	Joined        = "joined"
+	Left          = "left"
+	LeaderChanged = "leader"
+)
+
+// Member is a node running for the election, as seen in etcd.
+type Member struct {
+	node.Spec
+	Role           string
+	LeaseTTL       int64
+	Paused         bool
+	PendingRestart bool
+}
+
+// Change is a change of the membership or of the leadership.
+type Change struct {
+	Type   string
+	Member Member
+}
+
+// Inspector reads the members of the election without running for it.
+type Inspector struct {
+	*Etcd
+	client Client
+}
+
+// NewInspector ...
+func NewInspector(cl ConfigLoader) *Inspector {
+	cl.Load(context.Background())
+	log = logrus.WithFields(logrus.Fields{"inspector": "etcd"})
+	return &Inspector{Etcd: NewEtcd(cl)}
+}
+
+// Connect ...
+func (i *Inspector) Connect(ctx context.Context) error {
+	var err error
+	i.client, err = i.Connector.Connect(ctx, i.ConfigSpec)
+	return err
+}
+
+// Members returns the members of the election, the leader first then the followers in their order of arrival, then the
+// nodes which never run for the election.
+func (i *Inspector) Members(ctx context.Context) ([]Member, error) {
+	members, err := i.members(ctx, i.client)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := i.replicas(ctx, i.client)
+	if err != nil {
+		return nil, err
+	}
+	members = append(members, replicas...)
+
+	response, err := i.client.Get(ctx, i.pauseKey(), clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	paused := make(map[string]bool)
+	for _, kv := range response.Kvs {
+		paused[string(kv.Key)] = true
+	}
+	for index := range members {
+		members[index].Paused = paused[i.pauseKey()] || paused[i.nodePauseKey(members[index].Name)]
+	}
+
+	response, err = i.client.Get(ctx, i.pendingRestartKey()+"/", clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]bool)
+	for _, kv := range response.Kvs {
+		pending[strings.TrimPrefix(string(kv.Key), i.pendingRestartKey()+"/")] = true
+	}
+	for index := range members {
+		members[index].PendingRestart = pending[members[index].Name]
+	}
+
+	record, err := i.sync(ctx, i.client)
+	if err != nil {
+		return nil, err
+	}
+	for index := range members {
+		if record != nil && members[index].Role == FollowerRole && members[index].Name == record.SyncStandby {
+			members[index].Role = SyncStandbyRole
+		}
+	}
+	return members, nil
+}
+
+// Leader returns the current leader, nil when there is none.
+func (i *Inspector) Leader(ctx context.Context) (*Member, error) {
+	members, err := i.Members(ctx)
+	if err != nil || len(members) == 0 || members[0].Role != LeaderRole {
+		return nil, err
+	}
+	return &members[0], nil
+}
+
+// Watch streams the changes until the context is done. The current members and leader come first as changes too.
+func (i *Inspector) Watch(ctx context.Context) (<-chan Change, error) {
+	elections := i.client.Watch(ctx, i.electionKey()+"/", clientv3.WithPrefix())
+	replicas := i.client.Watch(ctx, i.replicaKey()+"/", clientv3.WithPrefix())
+	current, err := i.Members(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan Change)
+	go func() {
+		defer close(changes)
+		previous := make([]Member, 0)
+		for {
+			for _, change := range diff(previous, current) {
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+			previous = current
+
+			select {
+			case _, ok := <-elections:
+				if !ok {
+					return
+				}
+			case _, ok := <-replicas:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+			if current, err = i.Members(ctx); err != nil {
+				log.Errorf("failed to get the members: %v", err)
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+func diff(previous, current []Member) []Change {
+	changes := make([]Change, 0)
+	for _, member := range previous {
+		if indexOfMember(current, member.ElectionKey) < 0 {
+			changes = append(changes, Change{Type: Left, Member: member})
+		}
+	}
+	for _, member := range current {
+		if indexOfMember(previous, member.ElectionKey) < 0 {
+			changes = append(changes, Change{Type: Joined, Member: member})
+		}
+	}
+	if len(current) > 0 && current[0].Role == LeaderRole && (len(previous) == 0 || previous[0].ElectionKey != current[0].ElectionKey) {
+		changes = append(changes, Change{Type: LeaderChanged, Member: current[0]})
+	}
+	return changes
+}
+
+func indexOfName(members []Member, name string) int {
+	for index, member := range members {
+		if member.Name == name {
+			return index
+		}
+	}
+	return -1
+}
+
+func indexOfMember(members []Member, electionKey string) int {
+	for index, member := range members {
+		if member.ElectionKey == electionKey {
+			return index
+		}
+	}
+	return -1
+}
+
+// Switchover asks the leader to hand the leadership over to the candidate, it returns the current leader.
+func (i *Inspector) Switchover(ctx context.Context, candidate string) (*Member, error) {
+	members, err := i.Members(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 || members[0].Role != LeaderRole {
+		return nil, errors.New("no leader")
+	}
+	leader := members[0]
+	if leader.Name == candidate {
+		return nil, fmt.Errorf("%s is already the leader", candidate)
+	}
+	index := indexOfName(members, candidate)
+	if index < 0 {
+		return nil, fmt.Errorf("%s is not a member of the cluster", candidate)
+	}
+	if members[index].Tags.NoFailover {
+		return nil, fmt.Errorf("%s never runs for the election", candidate)
+	}
+
+	intent, _ := json.Marshal(Switchover{Leader: leader.Name, Candidate: candidate})
+	if _, err := i.client.Put(ctx, i.switchoverKey(), string(intent)); err != nil {
+		return nil, err
+	}
+	return &leader, nil
+}
+
+// Pause holds the electors back from promoting, demoting or stonithing the nodes, the member only when named, the whole
+// cluster otherwise.
+func (i *Inspector) Pause(ctx context.Context, name string) error {
+	key, err := i.pauseKeyOf(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = i.client.Put(ctx, key, "")
+	return err
+}
+
+// Resume lifts the pause set by Pause, even of a node which has left the cluster meanwhile.
+func (i *Inspector) Resume(ctx context.Context, name string) error {
+	key := i.pauseKey()
+	if name != "" {
+		key = i.nodePauseKey(name)
+	}
+	_, err := i.client.Delete(ctx, key)
+	return err
+}
+
+func (i *Inspector) pauseKeyOf(ctx context.Context, name string) (string, error) {
+	if name == "" {
+		return i.pauseKey(), nil
+	}
+	members, err := i.Members(ctx)
+	if err != nil {
+		return "", err
+	}
+	if indexOfName(members, name) < 0 {
+		return "", fmt.Errorf("%s is not a member of the cluster", name)
+	}
+	return i.nodePauseKey(name), nil
+}
+
+// Config returns the cluster-wide configuration, empty when there is none, and the revision it was read at, 0 when
+// there is none.
+func (i *Inspector) Config(ctx context.Context) (*DynamicConfig, int64, error) {
+	response, err := i.client.Get(ctx, i.configKey())
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(response.Kvs) == 0 {
+		return &DynamicConfig{}, 0, nil
+	}
+	config, err := toDynamicConfig(response.Kvs[0].Value)
+	return config, response.Kvs[0].ModRevision, err
+}
+
+// EditConfig replaces the cluster-wide configuration read at the revision, unless it has been edited since. The nodes
+// apply it as soon as they see it.
+func (i *Inspector) EditConfig(ctx context.Context, config DynamicConfig, revision int64) error {
+	value, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	key := i.configKey()
+	response, err := i.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", revision)).
+		Then(clientv3.OpPut(key, string(value))).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if !response.Succeeded {
+		return errors.New("the configuration has been edited meanwhile, edit it again")
+	}
+	return nil
+}
@@ -0,0 +1,445 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	etcdmock "github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type members struct {
+	mutex   *sync.Mutex
+	kvs     []*mvccpb.KeyValue
+	puts    map[string]string
+	deletes []string
+	watch   chan clientv3.WatchResponse
+}
+
+func (m *members) put(key string, spec node.Spec, revision int64, lease int64) {
+	m.mutex.Lock()
+	m.kvs = append(m.kvs, &mvccpb.KeyValue{Key: []byte(key), Value: []byte(spec.Marshal()), CreateRevision: revision, Lease: lease})
+	m.mutex.Unlock()
+	m.watch <- clientv3.WatchResponse{}
+}
+
+// commit runs the transactions comparing the revision of a key, 0 when the key does not exist.
+func (m *members) commit(txn *etcdmock.Txn) (*clientv3.TxnResponse, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, cmp := range txn.Cmps {
+		revision := int64(0)
+		for _, kv := range m.kvs {
+			if string(kv.Key) == string(cmp.KeyBytes()) {
+				revision = kv.ModRevision
+			}
+		}
+		if revision != cmp.TargetUnion.(*etcdserverpb.Compare_ModRevision).ModRevision {
+			return &clientv3.TxnResponse{}, nil
+		}
+	}
+	for _, op := range txn.Success {
+		m.puts[string(op.KeyBytes())] = string(op.ValueBytes())
+	}
+	return &clientv3.TxnResponse{Succeeded: true}, nil
+}
+
+func (m *members) delete(key string) {
+	m.mutex.Lock()
+	for i, kv := range m.kvs {
+		if string(kv.Key) == key {
+			m.kvs = append(m.kvs[:i], m.kvs[i+1:]...)
+			break
+		}
+	}
+	m.mutex.Unlock()
+	m.watch <- clientv3.WatchResponse{}
+}
+
+func newInspector(t *testing.T) (*etcd.Inspector, *members, *[]string) {
+	inspector := etcd.NewInspector(examples[0].config)
+	t.Cleanup(inspector.Cleanup)
+	m := &members{mutex: &sync.Mutex{}, puts: make(map[string]string), watch: make(chan clientv3.WatchResponse)}
+	prefixes := make([]string, 0)
+
+	connector := etcdmock.NewConnector(t)
+	connector.ConnectFn = func(context.Context, *etcd.ConfigSpec) (etcd.Client, error) {
+		client := etcdmock.NewClient()
+		client.GetFn = func(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			prefixes = append(prefixes, key)
+			// etcd sorts the keys, not by creation
+			kvs := make([]*mvccpb.KeyValue, 0)
+			for _, kv := range m.kvs {
+				if strings.HasPrefix(string(kv.Key), key) {
+					kvs = append(kvs, kv)
+				}
+			}
+			return &clientv3.GetResponse{Kvs: kvs}, nil
+		}
+		client.TTLFn = func(_ context.Context, id clientv3.LeaseID, _ ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+			return &clientv3.LeaseTimeToLiveResponse{ID: id, TTL: int64(id) * 10}, nil
+		}
+		client.WatchFn = func(context.Context, string, ...clientv3.OpOption) clientv3.WatchChan {
+			return m.watch
+		}
+		client.PutFn = func(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			m.puts[key] = val
+			return &clientv3.PutResponse{}, nil
+		}
+		client.DeleteFn = func(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			m.deletes = append(m.deletes, key)
+			return &clientv3.DeleteResponse{}, nil
+		}
+		client.TxnFn = func(context.Context) clientv3.Txn {
+			return &etcdmock.Txn{CommitFn: m.commit}
+		}
+		return client, nil
+	}
+	inspector.Connector = connector
+
+	if err := inspector.Connect(context.Background()); err != nil {
+		t.Fatalf("\t\t%s FAIL: Connect, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	return inspector, m, &prefixes
+}
+
+var (
+	keyPrefix = "nominee/domain/domain-001/cluster/cluster-001/"
+	first     = node.Spec{Name: "nominee-1", Address: "10.0.0.1", Port: 5432}
+	second    = node.Spec{Name: "nominee-2", Address: "10.0.0.2", Port: 5432}
+)
+
+func TestEtcdInspector_when_list_then_the_leader_is_the_oldest_member(t *testing.T) {
+	t.Logf("Given two members of the election")
+	{
+		inspector, m, prefixes := newInspector(t)
+		m.kvs = []*mvccpb.KeyValue{
+			{Key: []byte(keyPrefix + "b"), Value: []byte(second.Marshal()), CreateRevision: 12, Lease: 2},
+			{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10, Lease: 1},
+		}
+
+		t.Logf("\tWhen list the members")
+		{
+			actual, err := inspector.Members(context.Background())
+			if err != nil {
+				t.Fatalf("\t\t%s FAIL: Members, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			leader, follower := first, second
+			leader.ElectionKey, follower.ElectionKey = keyPrefix+"a", keyPrefix+"b"
+			expected := []etcd.Member{
+				{Spec: leader, Role: etcd.LeaderRole, LeaseTTL: 10},
+				{Spec: follower, Role: etcd.FollowerRole, LeaseTTL: 20},
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: Members, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the oldest member must be the leader and the lease TTLs must be given.", testutils.Succeed)
+
+			if (*prefixes)[0] != keyPrefix {
+				t.Fatalf("\t\t%s FAIL: Get, expected the prefix <%s> but actual is <%s>", testutils.Failed, keyPrefix, (*prefixes)[0])
+			}
+			t.Logf("\t\t%s Then the election key must be the prefix.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen get the leader")
+		{
+			leader, _ := inspector.Leader(context.Background())
+			if leader == nil || leader.Name != first.Name {
+				t.Fatalf("\t\t%s FAIL: Leader, expected <%s> but actual is <%v>", testutils.Failed, first.Name, leader)
+			}
+			t.Logf("\t\t%s Then the oldest member must be returned.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_no_member_then_no_leader(t *testing.T) {
+	t.Logf("Given no member in the election")
+	{
+		inspector, _, _ := newInspector(t)
+		t.Logf("\tWhen get the leader")
+		{
+			leader, err := inspector.Leader(context.Background())
+			if err != nil || leader != nil {
+				t.Fatalf("\t\t%s FAIL: Leader, expected none but actual is <%v, %v>", testutils.Failed, leader, err)
+			}
+			t.Logf("\t\t%s Then there must be none.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_watch_then_stream_the_changes(t *testing.T) {
+	t.Logf("Given a member leading the election")
+	{
+		inspector, m, _ := newInspector(t)
+		m.kvs = []*mvccpb.KeyValue{{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10}}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes, err := inspector.Watch(ctx)
+		if err != nil {
+			t.Fatalf("\t\t%s FAIL: Watch, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+		next := func() string {
+			select {
+			case change := <-changes:
+				return change.Type + " " + change.Member.Name
+			case <-time.After(time.Second):
+				return "none"
+			}
+		}
+
+		t.Logf("\tWhen start to watch")
+		{
+			actual := []string{next(), next()}
+			expected := []string{"joined nominee-1", "leader nominee-1"}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: changes, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the current members and leader must come first.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen another member joins then the leader leaves")
+		{
+			go func() {
+				m.put(keyPrefix+"b", second, 12, 0)
+				m.delete(keyPrefix + "a")
+			}()
+
+			actual := []string{next(), next(), next()}
+			expected := []string{"joined nominee-2", "left nominee-1", "leader nominee-2"}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: changes, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the changes must be streamed.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the context is done")
+		{
+			cancel()
+			select {
+			case _, ok := <-changes:
+				if ok {
+					t.Fatalf("\t\t%s FAIL: changes, expected to be closed but actual is not", testutils.Failed)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("\t\t%s FAIL: changes, expected to be closed but actual is not", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then the stream must be closed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_switchover_then_write_the_intent(t *testing.T) {
+	t.Logf("Given two members of the election")
+	{
+		inspector, m, _ := newInspector(t)
+		m.kvs = []*mvccpb.KeyValue{
+			{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10},
+			{Key: []byte(keyPrefix + "b"), Value: []byte(second.Marshal()), CreateRevision: 12},
+		}
+
+		t.Logf("\tWhen switchover to the follower")
+		{
+			leader, err := inspector.Switchover(context.Background(), second.Name)
+			if err != nil || leader.Name != first.Name {
+				t.Fatalf("\t\t%s FAIL: Switchover, expected the leader <%s> but actual is <%v, %v>", testutils.Failed, first.Name, leader, err)
+			}
+
+			expected := `{"Leader":"nominee-1","Candidate":"nominee-2"}`
+			if actual := m.puts["nominee/switchover/domain/domain-001/cluster/cluster-001"]; actual != expected {
+				t.Fatalf("\t\t%s FAIL: intent, expected <%s> but actual is <%s>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the intent must be written out of the election key prefix.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen switchover to the leader or to an unknown node")
+		{
+			for _, name := range []string{first.Name, "unknown"} {
+				if _, err := inspector.Switchover(context.Background(), name); err == nil {
+					t.Fatalf("\t\t%s FAIL: Switchover to %s, expected an error but actual is none", testutils.Failed, name)
+				}
+			}
+			t.Logf("\t\t%s Then it must be rejected.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_pause_then_write_the_flag(t *testing.T) {
+	t.Logf("Given two members of the election")
+	{
+		inspector, m, _ := newInspector(t)
+		m.kvs = []*mvccpb.KeyValue{
+			{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10},
+			{Key: []byte(keyPrefix + "b"), Value: []byte(second.Marshal()), CreateRevision: 12},
+		}
+		clusterKey := "nominee/pause/domain/domain-001/cluster/cluster-001"
+
+		t.Logf("\tWhen pause the cluster then a member")
+		{
+			if err := inspector.Pause(context.Background(), ""); err != nil {
+				t.Fatalf("\t\t%s FAIL: Pause, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if err := inspector.Pause(context.Background(), second.Name); err != nil {
+				t.Fatalf("\t\t%s FAIL: Pause, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			for _, key := range []string{clusterKey, clusterKey + "/" + second.Name} {
+				if _, ok := m.puts[key]; !ok {
+					t.Fatalf("\t\t%s FAIL: Pause, expected the key <%s> but actual is <%v>", testutils.Failed, key, m.puts)
+				}
+			}
+			t.Logf("\t\t%s Then the flags must be written out of the election key prefix.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen pause an unknown node")
+		{
+			if err := inspector.Pause(context.Background(), "unknown"); err == nil {
+				t.Fatalf("\t\t%s FAIL: Pause, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must be rejected.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen list the members of a paused member")
+		{
+			m.kvs = append(m.kvs, &mvccpb.KeyValue{Key: []byte(clusterKey + "/" + second.Name)})
+			members, err := inspector.Members(context.Background())
+			if err != nil || len(members) != 2 || members[0].Paused || !members[1].Paused {
+				t.Fatalf("\t\t%s FAIL: Members, expected only <%s> to be paused but actual is <%v, %v>", testutils.Failed, second.Name, members, err)
+			}
+			t.Logf("\t\t%s Then only this member must be paused.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen resume the member then the cluster")
+		{
+			_ = inspector.Resume(context.Background(), second.Name)
+			_ = inspector.Resume(context.Background(), "")
+			expected := []string{clusterKey + "/" + second.Name, clusterKey}
+			if !reflect.DeepEqual(m.deletes, expected) {
+				t.Fatalf("\t\t%s FAIL: Resume, expected <%v> but actual is <%v>", testutils.Failed, expected, m.deletes)
+			}
+			t.Logf("\t\t%s Then the flags must be deleted.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_a_node_never_runs_for_the_election_then_list_it_but_never_switch_over_to_it(t *testing.T) {
+	t.Logf("Given a leader and a nofailover replica")
+	{
+		inspector, m, _ := newInspector(t)
+		replica := node.Spec{Name: "nominee-dr", Address: "10.1.0.1", Port: 5432, Tags: node.Tags{NoFailover: true}}
+		m.kvs = []*mvccpb.KeyValue{
+			{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10},
+			{Key: []byte("nominee/replica/domain/domain-001/cluster/cluster-001/dr"), Value: []byte(replica.Marshal()), CreateRevision: 11},
+		}
+
+		t.Logf("\tWhen list the members")
+		{
+			members, err := inspector.Members(context.Background())
+			if err != nil || len(members) != 2 || members[1].Name != replica.Name || members[1].Role != etcd.FollowerRole {
+				t.Fatalf("\t\t%s FAIL: Members, expected the replica as a follower but actual is <%v, %v>", testutils.Failed, members, err)
+			}
+			t.Logf("\t\t%s Then the replica must be listed as a follower.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen switchover to the replica")
+		{
+			if _, err := inspector.Switchover(context.Background(), replica.Name); err == nil {
+				t.Fatalf("\t\t%s FAIL: Switchover, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must be rejected.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_a_sync_standby_is_recorded_then_tell_it(t *testing.T) {
+	t.Logf("Given a leader which has recorded its sync standby")
+	{
+		inspector, m, _ := newInspector(t)
+		m.kvs = []*mvccpb.KeyValue{
+			{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10},
+			{Key: []byte(keyPrefix + "b"), Value: []byte(second.Marshal()), CreateRevision: 12},
+			{Key: []byte(syncKey), Value: []byte(`{"Leader":"nominee-1","SyncStandby":"nominee-2"}`)},
+		}
+
+		t.Logf("\tWhen list the members")
+		{
+			members, err := inspector.Members(context.Background())
+			if err != nil {
+				t.Fatalf("\t\t%s FAIL: Members, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			roles := []string{members[0].Role, members[1].Role}
+			if expected := []string{etcd.LeaderRole, etcd.SyncStandbyRole}; !reflect.DeepEqual(roles, expected) {
+				t.Fatalf("\t\t%s FAIL: Role, expected <%v> but actual is <%v>", testutils.Failed, expected, roles)
+			}
+			t.Logf("\t\t%s Then the sync standby must be told apart.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdInspector_when_edit_the_config_then_write_it_and_tell_the_pending_restarts(t *testing.T) {
+	t.Logf("Given two members of the election")
+	{
+		inspector, m, _ := newInspector(t)
+		m.kvs = []*mvccpb.KeyValue{
+			{Key: []byte(keyPrefix + "a"), Value: []byte(first.Marshal()), CreateRevision: 10},
+			{Key: []byte(keyPrefix + "b"), Value: []byte(second.Marshal()), CreateRevision: 12},
+		}
+
+		t.Logf("\tWhen edit the config")
+		{
+			config := etcd.DynamicConfig{Parameters: node.Parameters{"max_connections": "200", "work_mem": "16MB"}}
+			if err := inspector.EditConfig(context.Background(), config, 0); err != nil {
+				t.Fatalf("\t\t%s FAIL: EditConfig, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			expected := `{"Parameters":{"max_connections":"200","work_mem":"16MB"}}`
+			if actual := m.puts[configKey]; actual != expected {
+				t.Fatalf("\t\t%s FAIL: config, expected <%s> but actual is <%s>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then it must be written out of the election key prefix.", testutils.Succeed)
+
+			m.kvs = append(m.kvs, &mvccpb.KeyValue{Key: []byte(configKey), Value: []byte(expected), ModRevision: 42})
+			actual, revision, err := inspector.Config(context.Background())
+			if err != nil || !reflect.DeepEqual(*actual, config) || revision != 42 {
+				t.Fatalf("\t\t%s FAIL: Config, expected <%v> at <42> but actual is <%v> at <%d>, <%v>", testutils.Failed, config, actual, revision, err)
+			}
+			t.Logf("\t\t%s Then it must be read back with its revision.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen edit the config read before the last edition")
+		{
+			stale := etcd.DynamicConfig{Parameters: node.Parameters{"work_mem": "4MB"}}
+			if err := inspector.EditConfig(context.Background(), stale, 0); err == nil {
+				t.Fatalf("\t\t%s FAIL: EditConfig, expected an error but actual is none", testutils.Failed)
+			}
+			expected := `{"Parameters":{"max_connections":"200","work_mem":"16MB"}}`
+			if actual := m.puts[configKey]; actual != expected {
+				t.Fatalf("\t\t%s FAIL: config, expected <%s> but actual is <%s>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then it must be refused.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen a member waits for a restart")
+		{
+			m.kvs = append(m.kvs, &mvccpb.KeyValue{Key: []byte(pendingRestartKey + second.Name)})
+			members, err := inspector.Members(context.Background())
+			if err != nil || len(members) != 2 || members[0].PendingRestart || !members[1].PendingRestart {
+				t.Fatalf("\t\t%s FAIL: Members, expected only <%s> to wait for a restart but actual is <%v, %v>", testutils.Failed, second.Name, members, err)
+			}
+			t.Logf("\t\t%s Then only this member must be told pending restart.", testutils.Succeed)
+		}
+	}
+}
@@ -36,15 +36,18 @@ func (observer *Observer) Observe(proxy proxy.Proxy) error {
 	}
 
 	observer.listenToTheConnectorSession()
-	observer.pushCurrentNodes()
+	observer.pushCurrentNodes(observer.electionKey())
+	observer.pushCurrentNodes(observer.replicaKey() + "/")
 	observer.observeLeader()
-	observer.observeNodes()
+	observer.observeNodes(observer.electionKey())
+	observer.observeNodes(observer.replicaKey() + "/")
 	return nil
 }
 
-func (observer *Observer) observeNodes() {
+// observeNodes follows the nodes under the prefix, either the election key or the prefix of the nodes out of the election.
+func (observer *Observer) observeNodes(prefix string) {
 	go func() {
-		watch := observer.client.Watch(observer.Ctx, observer.electionKey(), clientv3.WithPrefix())
+		watch := observer.client.Watch(observer.Ctx, prefix, clientv3.WithPrefix())
 		for response := range watch {
 			for _, v := range response.Events {
 				decoded, _ := node.Unmarshal(v.Kv.Value)
@@ -81,8 +84,8 @@ func (observer *Observer) observeLeader() {
 	}()
 }
 
-func (observer *Observer) pushCurrentNodes() {
-	response, err := observer.client.Get(observer.Ctx, observer.electionKey(), clientv3.WithPrefix())
+func (observer *Observer) pushCurrentNodes(prefix string) {
+	response, err := observer.client.Get(observer.Ctx, prefix, clientv3.WithPrefix())
 	if err != nil {
 		panic(err)
 	}
@@ -120,6 +123,7 @@ func (observer *Observer) failBack() error {
 		return err
 	}
 	observer.observeLeader()
-	observer.observeNodes()
+	observer.observeNodes(observer.electionKey())
+	observer.observeNodes(observer.replicaKey() + "/")
 	return nil
 }
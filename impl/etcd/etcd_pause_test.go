@@ -0,0 +1,117 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"testing"
+)
+
+func (d *dcs) remove(key string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	kvs := make([]*mvccpb.KeyValue, 0, len(d.kvs))
+	for _, kv := range d.kvs {
+		if string(kv.Key) != key {
+			kvs = append(kvs, kv)
+		}
+	}
+	d.kvs = kvs
+}
+
+func TestEtcdElector_when_the_cluster_is_paused_then_do_nothing_until_resumed(t *testing.T) {
+	t.Logf("Given a cluster paused before the node connects")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		d := newDCS(me, candidate)
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(pauseKey)})
+		elector, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen the node is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+			if !elector.IsPaused() || nd.LeadHits != 0 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected to be paused and not to lead but actual is <%v> and <%d> leads", testutils.Failed, elector.IsPaused(), nd.LeadHits)
+			}
+			t.Logf("\t\t%s Then it must not lead.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the cluster is resumed")
+		{
+			d.pause(pauseKey, false)
+			if !eventually(func() bool { return elector.GetRole() == node.Primary }) {
+				t.Fatalf("\t\t%s FAIL: role, expected <%s> but actual is <%s>", testutils.Failed, node.Primary, elector.GetRole())
+			}
+			if nd.LeadHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected <1> but actual is <%d>", testutils.Failed, nd.LeadHits)
+			}
+			t.Logf("\t\t%s Then it must lead.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_a_node_is_paused_then_only_this_node_is_paused(t *testing.T) {
+	t.Logf("Given a follower")
+	{
+		nd := mock.NewNode(t, &candidate)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		elector, _, d := runElector(t, nd, me, candidate)
+
+		t.Logf("\tWhen another node is paused")
+		{
+			d.pause(pauseKey+"/"+me.Name, true)
+			d.pause(pauseKey+"-002", true)
+			if elector.IsPaused() {
+				t.Fatalf("\t\t%s FAIL: IsPaused, expected <false> but actual is <true>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must not be paused.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen it is paused")
+		{
+			d.pause(pauseKey+"/"+candidate.Name, true)
+			if !eventually(elector.IsPaused) {
+				t.Fatalf("\t\t%s FAIL: IsPaused, expected <true> but actual is <false>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must be paused.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen it is resumed")
+		{
+			d.pause(pauseKey+"/"+candidate.Name, false)
+			if !eventually(func() bool { return !elector.IsPaused() }) {
+				t.Fatalf("\t\t%s FAIL: IsPaused, expected <false> but actual is <true>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must not be paused anymore.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_resumed_while_disconnected_then_resume_on_reconnect(t *testing.T) {
+	t.Logf("Given a cluster and a node paused")
+	{
+		nd := mock.NewNode(t, &candidate)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		d := newDCS(me, candidate)
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(pauseKey)}, &mvccpb.KeyValue{Key: []byte(pauseKey + "/" + candidate.Name)})
+		elector, connector := runElectorOn(t, nd, d)
+		if !elector.IsPaused() {
+			t.Fatalf("\t\t%s FAIL: IsPaused, expected <true> but actual is <false>", testutils.Failed)
+		}
+
+		t.Logf("\tWhen both are resumed while the session is down, then it reconnects")
+		{
+			d.remove(pauseKey)
+			d.remove(pauseKey + "/" + candidate.Name)
+			connector.CloseSession()
+
+			if !eventually(func() bool { return !elector.IsPaused() }) {
+				t.Fatalf("\t\t%s FAIL: IsPaused, expected <false> but actual is <true>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must not be paused anymore.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// slotKeeperNode is a node keeping a slot per follower.
+type slotKeeperNode struct {
+	*mock.Node
+	KeepSlotsFn func(context.Context, []node.Spec) error
+}
+
+func (n *slotKeeperNode) KeepSlots(ctx context.Context, followers []node.Spec) error {
+	return n.KeepSlotsFn(ctx, followers)
+}
+
+func TestEtcdElector_when_leading_then_keep_a_slot_per_follower(t *testing.T) {
+	t.Logf("Given a leader with a follower and a node which never runs for the election")
+	{
+		interval := etcd.SlotsInterval
+		etcd.SlotsInterval = 10 * time.Millisecond
+		defer func() { etcd.SlotsInterval = interval }()
+
+		kept := make(chan []string, 1)
+		nd := &slotKeeperNode{Node: mock.NewNode(t, &me)}
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		nd.KeepSlotsFn = func(_ context.Context, followers []node.Spec) error {
+			names := make([]string, 0)
+			for _, follower := range followers {
+				names = append(names, follower.Name)
+			}
+			select {
+			case kept <- names:
+			default:
+			}
+			return nil
+		}
+		dr := node.Spec{Name: "postgres-dr", Address: "10.1.0.1", Port: 5432, Tags: node.Tags{NoFailover: true}}
+		d := newDCS(me, candidate)
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(replicaKey + dr.Name), Value: []byte(dr.Marshal()), CreateRevision: 20})
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it follows")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+			time.Sleep(50 * time.Millisecond)
+
+			select {
+			case names := <-kept:
+				t.Fatalf("\t\t%s FAIL: KeepSlots, expected no call but actual is <%v>", testutils.Failed, names)
+			default:
+			}
+			t.Logf("\t\t%s Then it must not keep any slot.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen it leads")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			expected := []string{candidate.Name, dr.Name}
+			select {
+			case actual := <-kept:
+				if !reflect.DeepEqual(actual, expected) {
+					t.Fatalf("\t\t%s FAIL: KeepSlots, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("\t\t%s FAIL: KeepSlots, expected <%v> but actual is nothing", testutils.Failed, expected)
+			}
+			t.Logf("\t\t%s Then it must keep a slot per follower.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package etcd_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/etcdserverpb"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	etcdmock "github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	switchoverKey = "nominee/switchover/domain/domain-001/cluster/cluster-001"
+	pauseKey      = "nominee/pause/domain/domain-001/cluster/cluster-001"
+)
+
+var (
+	me        = node.Spec{Name: "postgres-01", Address: "10.0.0.1", Port: 5432}
+	candidate = node.Spec{Name: "postgres-02", Address: "10.0.0.2", Port: 5432}
+	third     = node.Spec{Name: "postgres-03", Address: "10.0.0.3", Port: 5432}
+)
+
+type dcs struct {
+	mutex       *sync.Mutex
+	revision    int64
+	kvs         []*mvccpb.KeyValue
+	deleted     []string
+	puts        map[string]string
+	watchedFrom map[string]int64
+	watch       chan clientv3.WatchResponse
+	pauses      chan clientv3.WatchResponse
+	configs     chan clientv3.WatchResponse
+}
+
+func (d *dcs) deletedKeys() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]string{}, d.deleted...)
+}
+
+func (d *dcs) put(key string) string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.puts[key]
+}
+
+// commit runs the transactions, which only compare the creation of keys: they succeed when no key exists yet, otherwise
+// they read the existing one.
+func (d *dcs) commit(txn *etcdmock.Txn) (*clientv3.TxnResponse, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, cmp := range txn.Cmps {
+		if kv := d.find(string(cmp.KeyBytes())); kv != nil {
+			return &clientv3.TxnResponse{Responses: []*etcdserverpb.ResponseOp{
+				{Response: &etcdserverpb.ResponseOp_ResponseRange{ResponseRange: &etcdserverpb.RangeResponse{Kvs: []*mvccpb.KeyValue{kv}}}},
+			}}, nil
+		}
+	}
+	for _, op := range txn.Success {
+		d.puts[string(op.KeyBytes())] = string(op.ValueBytes())
+	}
+	return &clientv3.TxnResponse{Succeeded: true}, nil
+}
+
+func (d *dcs) find(key string) *mvccpb.KeyValue {
+	if value, ok := d.puts[key]; ok {
+		return &mvccpb.KeyValue{Key: []byte(key), Value: []byte(value)}
+	}
+	for _, kv := range d.kvs {
+		if string(kv.Key) == key {
+			return kv
+		}
+	}
+	return nil
+}
+
+func (d *dcs) requestSwitchover(leader, to string) {
+	intent, _ := json.Marshal(etcd.Switchover{Leader: leader, Candidate: to})
+	d.watch <- clientv3.WatchResponse{Events: []*clientv3.Event{
+		{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte(switchoverKey), Value: intent}},
+	}}
+}
+
+func (d *dcs) pause(key string, paused bool) {
+	event := &clientv3.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte(key)}}
+	if !paused {
+		event.Type = mvccpb.DELETE
+	}
+	d.pauses <- clientv3.WatchResponse{Events: []*clientv3.Event{event}}
+}
+
+func newDCS(members ...node.Spec) *dcs {
+	d := &dcs{mutex: &sync.Mutex{}, puts: make(map[string]string), watchedFrom: make(map[string]int64), watch: make(chan clientv3.WatchResponse), pauses: make(chan clientv3.WatchResponse), configs: make(chan clientv3.WatchResponse)}
+	for i, member := range members {
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(keyPrefix + member.Name), Value: []byte(member.Marshal()), CreateRevision: int64(10 + i)})
+	}
+	return d
+}
+
+func runElector(t *testing.T, nd *mock.Node, members ...node.Spec) (*etcd.Elector, *etcdmock.Connector, *dcs) {
+	d := newDCS(members...)
+	elector, connector := runElectorOn(t, nd, d)
+	return elector, connector, d
+}
+
+func runElectorOn(t *testing.T, nd node.Node, d *dcs) (*etcd.Elector, *etcdmock.Connector) {
+
+	elector := etcd.NewElector(examples[0].config)
+	t.Cleanup(elector.Cleanup)
+	connector := etcdmock.NewConnector(t)
+	connector.ConnectFn = func(context.Context, *etcd.ConfigSpec) (etcd.Client, error) {
+		client := etcdmock.NewClient()
+		client.GetFn = func(_ context.Context, prefix string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+			d.mutex.Lock()
+			defer d.mutex.Unlock()
+			kvs := make([]*mvccpb.KeyValue, 0)
+			for _, kv := range d.kvs {
+				if strings.HasPrefix(string(kv.Key), prefix) {
+					kvs = append(kvs, kv)
+				}
+			}
+			return &clientv3.GetResponse{Header: &etcdserverpb.ResponseHeader{Revision: d.revision}, Kvs: kvs}, nil
+		}
+		client.PutFn = func(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+			d.mutex.Lock()
+			defer d.mutex.Unlock()
+			d.puts[key] = val
+			return &clientv3.PutResponse{}, nil
+		}
+		client.DeleteFn = func(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+			d.mutex.Lock()
+			defer d.mutex.Unlock()
+			d.deleted = append(d.deleted, key)
+			return &clientv3.DeleteResponse{}, nil
+		}
+		client.TxnFn = func(context.Context) clientv3.Txn {
+			return &etcdmock.Txn{CommitFn: d.commit}
+		}
+		client.WatchFn = func(_ context.Context, key string, options ...clientv3.OpOption) clientv3.WatchChan {
+			d.mutex.Lock()
+			d.watchedFrom[key] = clientv3.OpGet(key, options...).Rev()
+			d.mutex.Unlock()
+			switch key {
+			case switchoverKey:
+				return d.watch
+			case pauseKey:
+				return d.pauses
+			case configKey:
+				return d.configs
+			}
+			t.Fatalf("\t\t%s FAIL: Watch, expected the key <%s> or <%s> but actual is <%s>", testutils.Failed, switchoverKey, pauseKey, key)
+			return nil
+		}
+		return client, nil
+	}
+	elector.Connector = connector
+
+	if err := elector.Run(nd); err != nil {
+		t.Fatalf("\t\t%s FATAL: EtcdElector, error when RUN %v", testutils.Failed, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	return elector, connector
+}
+
+func leaderResponse(spec node.Spec) clientv3.GetResponse {
+	return clientv3.GetResponse{Kvs: []*mvccpb.KeyValue{{Key: []byte(keyPrefix + spec.Name), Value: []byte(spec.Marshal())}}}
+}
+
+func eventually(condition func() bool) bool {
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if condition() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEtcdElector_when_switchover_then_demote_resign_and_follow_the_candidate(t *testing.T) {
+	t.Logf("Given a leader with a candidate next in line")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		checked := make(chan node.Spec, 1)
+		nd.CandidateFn = func(_ context.Context, spec node.Spec) error {
+			checked <- spec
+			return nil
+		}
+		nd.DemoteFn = func(context.Context) error { return nil }
+
+		elector, connector, d := runElector(t, nd, me, candidate, third)
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen a switchover to the candidate is requested")
+		{
+			d.requestSwitchover(me.Name, candidate.Name)
+
+			if !eventually(func() bool { return connector.Election.ResignHits == 1 }) {
+				t.Fatalf("\t\t%s FAIL: Resign, expected <1> but actual is <%d>", testutils.Failed, connector.Election.ResignHits)
+			}
+			t.Logf("\t\t%s Then it must resign.", testutils.Succeed)
+
+			if spec := <-checked; spec.Name != candidate.Name {
+				t.Fatalf("\t\t%s FAIL: CheckCandidate, expected <%s> but actual is <%s>", testutils.Failed, candidate.Name, spec.Name)
+			}
+			if nd.DemoteHits != 1 || nd.StonithHits != 0 {
+				t.Fatalf("\t\t%s FAIL: node, expected to be demoted and not stonithed but actual is <%d> demotions and <%d> stonith", testutils.Failed, nd.DemoteHits, nd.StonithHits)
+			}
+			t.Logf("\t\t%s Then the candidate must be checked and the node demoted.", testutils.Succeed)
+
+			if !eventually(func() bool { return connector.Election.CampaignHits == 2 && len(d.deletedKeys()) == 1 }) {
+				t.Fatalf("\t\t%s FAIL: campaign, expected to campaign again and clear the intent but actual is <%d> campaigns and <%v> deleted", testutils.Failed, connector.Election.CampaignHits, d.deletedKeys())
+			}
+			t.Logf("\t\t%s Then it must campaign again and clear the intent.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the candidate is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+			if nd.FollowHits != 1 || nd.Leader.Name != candidate.Name {
+				t.Fatalf("\t\t%s FAIL: Follow, expected to follow <%s> but actual is <%d> follows of <%s>", testutils.Failed, candidate.Name, nd.FollowHits, nd.Leader.Name)
+			}
+			if elector.GetRole() != node.Replica {
+				t.Fatalf("\t\t%s FAIL: role, expected <%s> but actual is <%s>", testutils.Failed, node.Replica, elector.GetRole())
+			}
+			testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+			t.Logf("\t\t%s Then it must follow it without being stonithed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_the_candidate_is_not_fit_then_reject_the_switchover(t *testing.T) {
+	t.Logf("Given a leader with a lagging candidate")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		nd.CandidateFn = func(context.Context, node.Spec) error { return errors.New("lagging") }
+
+		elector, connector, d := runElector(t, nd, me, candidate)
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen a switchover to the candidate is requested")
+		{
+			d.requestSwitchover(me.Name, candidate.Name)
+
+			if !eventually(func() bool { return len(d.deletedKeys()) == 1 }) {
+				t.Fatalf("\t\t%s FAIL: Delete, expected to clear the intent but actual is <%v>", testutils.Failed, d.deletedKeys())
+			}
+			t.Logf("\t\t%s Then the intent must be cleared.", testutils.Succeed)
+
+			if nd.DemoteHits != 0 || connector.Election.ResignHits != 0 || elector.GetRole() != node.Primary {
+				t.Fatalf("\t\t%s FAIL: switchover, expected to keep leading but actual is <%d> demotions, <%d> resignations and <%s>", testutils.Failed, nd.DemoteHits, connector.Election.ResignHits, elector.GetRole())
+			}
+			t.Logf("\t\t%s Then it must keep leading.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_the_demotion_fails_then_stonith(t *testing.T) {
+	t.Logf("Given a leader failing to demote")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		nd.CandidateFn = func(context.Context, node.Spec) error { return nil }
+		nd.DemoteFn = func(context.Context) error { return errors.New("stuck") }
+		nd.StonithFn = func(context.Context) {}
+
+		elector, connector, d := runElector(t, nd, me, candidate)
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen a switchover is requested")
+		{
+			d.requestSwitchover(me.Name, candidate.Name)
+			testutils.AsyncAssertion.ItMustBeStopped(t, elector.Done())
+			if nd.StonithHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Stonith, expected <1> but actual is <%d>", testutils.Failed, nd.StonithHits)
+			}
+			t.Logf("\t\t%s Then the node and the elector must be stonithed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_another_node_is_the_candidate_then_step_back(t *testing.T) {
+	t.Logf("Given a follower waiting before the candidate")
+	{
+		nd := mock.NewNode(t, &third)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+
+		_, connector, d := runElector(t, nd, me, third, candidate)
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen a switchover to the candidate is requested")
+		{
+			d.requestSwitchover(me.Name, candidate.Name)
+			if !eventually(func() bool { return connector.Election.CampaignHits == 2 }) {
+				t.Fatalf("\t\t%s FAIL: Campaign, expected <2> but actual is <%d>", testutils.Failed, connector.Election.CampaignHits)
+			}
+			t.Logf("\t\t%s Then it must withdraw and campaign again.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_it_is_the_candidate_then_keep_campaigning(t *testing.T) {
+	t.Logf("Given the candidate of a switchover")
+	{
+		nd := mock.NewNode(t, &candidate)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+
+		_, connector, d := runElector(t, nd, me, candidate)
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen the switchover is requested")
+		{
+			d.requestSwitchover(me.Name, candidate.Name)
+			time.Sleep(100 * time.Millisecond)
+			if connector.Election.CampaignHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Campaign, expected <1> but actual is <%d>", testutils.Failed, connector.Election.CampaignHits)
+			}
+			t.Logf("\t\t%s Then it must keep its place.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package etcd_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const syncKey = "nominee/sync/domain/domain-001/cluster/cluster-001"
+
+// synchronizingNode is a node replicating synchronously to one of its followers.
+type synchronizingNode struct {
+	*mock.Node
+	SynchronizeFn func(context.Context, []node.Spec) (string, error)
+}
+
+func (n *synchronizingNode) Synchronous() bool {
+	return true
+}
+
+func (n *synchronizingNode) Synchronize(ctx context.Context, followers []node.Spec) (string, error) {
+	return n.SynchronizeFn(ctx, followers)
+}
+
+func newSynchronizingNode(t *testing.T, spec *node.Spec) *synchronizingNode {
+	nd := &synchronizingNode{Node: mock.NewNode(t, spec)}
+	nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+	nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+	return nd
+}
+
+func fastSync(t *testing.T) {
+	interval, delay := etcd.SyncInterval, etcd.CampaignDelay
+	etcd.SyncInterval, etcd.CampaignDelay = 10*time.Millisecond, 10*time.Millisecond
+	t.Cleanup(func() { etcd.SyncInterval, etcd.CampaignDelay = interval, delay })
+}
+
+func (d *dcs) recordSync(leader, standby string) {
+	value, _ := json.Marshal(etcd.Sync{Leader: leader, SyncStandby: standby})
+	d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(syncKey), Value: value})
+}
+
+func TestEtcdElector_when_leading_in_synchronous_mode_then_record_the_sync_standby(t *testing.T) {
+	t.Logf("Given a synchronous leader with two followers")
+	{
+		fastSync(t)
+		nd := newSynchronizingNode(t, &me)
+		followers := make(chan []string, 1)
+		nd.SynchronizeFn = func(_ context.Context, specs []node.Spec) (string, error) {
+			names := make([]string, 0)
+			for _, spec := range specs {
+				names = append(names, spec.Name)
+			}
+			select {
+			case followers <- names:
+			default:
+			}
+			return candidate.Name, nil
+		}
+		d := newDCS(me, candidate, third)
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it leads")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			expected := []string{candidate.Name, third.Name}
+			if actual := <-followers; !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then it must pick the sync standby among the followers.", testutils.Succeed)
+
+			if !eventually(func() bool { return d.put(syncKey) != "" }) {
+				t.Fatalf("\t\t%s FAIL: Put, expected to record in <%s> but actual is nothing", testutils.Failed, syncKey)
+			}
+			record := etcd.Sync{}
+			_ = json.Unmarshal([]byte(d.put(syncKey)), &record)
+			if expected := (etcd.Sync{Leader: me.Name, SyncStandby: candidate.Name}); record != expected {
+				t.Fatalf("\t\t%s FAIL: Put, expected <%v> but actual is <%v>", testutils.Failed, expected, record)
+			}
+			t.Logf("\t\t%s Then it must record the sync standby.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_no_sync_standby_is_available_then_keep_the_record(t *testing.T) {
+	t.Logf("Given a strict synchronous leader")
+	{
+		fastSync(t)
+		nd := newSynchronizingNode(t, &me)
+		synchronized := make(chan struct{}, 1)
+		nd.SynchronizeFn = func(context.Context, []node.Spec) (string, error) {
+			select {
+			case synchronized <- struct{}{}:
+			default:
+			}
+			return "", errors.New("no synchronous standby available")
+		}
+		d := newDCS(me)
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it has no sync standby")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+			<-synchronized
+			time.Sleep(50 * time.Millisecond)
+
+			if actual := d.put(syncKey); actual != "" {
+				t.Fatalf("\t\t%s FAIL: Put, expected nothing but actual is <%s>", testutils.Failed, actual)
+			}
+			t.Logf("\t\t%s Then the last record must stay.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_elected_but_not_the_sync_standby_then_yield(t *testing.T) {
+	t.Logf("Given a follower while the candidate is the sync standby of the former leader")
+	{
+		fastSync(t)
+		nd := mock.NewNode(t, &third)
+		d := newDCS(third, candidate)
+		d.recordSync(me.Name, candidate.Name)
+		elector, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(third))
+
+			if !eventually(func() bool { return connector.Election.ResignHits == 1 && connector.Election.CampaignHits == 2 }) {
+				t.Fatalf("\t\t%s FAIL: yield, expected to resign and campaign again but actual is <%d> resignations and <%d> campaigns", testutils.Failed, connector.Election.ResignHits, connector.Election.CampaignHits)
+			}
+			if nd.LeadHits != 0 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected <0> but actual is <%d>", testutils.Failed, nd.LeadHits)
+			}
+			testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+			t.Logf("\t\t%s Then it must yield without leading.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_elected_and_the_sync_standby_then_lead(t *testing.T) {
+	t.Logf("Given the sync standby of the former leader")
+	{
+		fastSync(t)
+		nd := mock.NewNode(t, &candidate)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		d := newDCS(candidate, third)
+		d.recordSync(me.Name, candidate.Name)
+		elector, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it is elected")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+
+			if nd.LeadHits != 1 || connector.Election.ResignHits != 0 || elector.GetRole() != node.Primary {
+				t.Fatalf("\t\t%s FAIL: Lead, expected to lead but actual is <%d> leads, <%d> resignations and <%s>", testutils.Failed, nd.LeadHits, connector.Election.ResignHits, elector.GetRole())
+			}
+			t.Logf("\t\t%s Then it must lead.", testutils.Succeed)
+		}
+	}
+}
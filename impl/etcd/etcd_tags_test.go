@@ -0,0 +1,121 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github/mlyahmed.io/nominee/impl/etcd"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const replicaKey = "nominee/replica/domain/domain-001/cluster/cluster-001/"
+
+func TestEtcdElector_when_nofailover_then_register_without_campaigning(t *testing.T) {
+	t.Logf("Given a node tagged nofailover")
+	{
+		dr := node.Spec{Name: "postgres-dr", Address: "10.1.0.1", Port: 5432, Tags: node.Tags{NoFailover: true}}
+		nd := mock.NewNode(t, &dr)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		d := newDCS(me)
+		elector, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen it runs")
+		{
+			if connector.Election.CampaignHits != 0 {
+				t.Fatalf("\t\t%s FAIL: Campaign, expected <0> but actual is <%d>", testutils.Failed, connector.Election.CampaignHits)
+			}
+			t.Logf("\t\t%s Then it must not campaign.", testutils.Succeed)
+
+			registered, _ := node.Unmarshal([]byte(d.put(replicaKey + dr.Name)))
+			if registered != dr {
+				t.Fatalf("\t\t%s FAIL: Put, expected <%v> but actual is <%v>", testutils.Failed, dr, registered)
+			}
+			t.Logf("\t\t%s Then it must register out of the election.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen another node leads")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+			if nd.FollowHits != 1 || elector.GetRole() != node.Replica {
+				t.Fatalf("\t\t%s FAIL: Follow, expected to follow but actual is <%d> follows and <%s>", testutils.Failed, nd.FollowHits, elector.GetRole())
+			}
+			t.Logf("\t\t%s Then it must follow it.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_priority_then_delay_the_campaign(t *testing.T) {
+	t.Logf("Given a node with a priority of 2")
+	{
+		delay := etcd.CampaignDelay
+		etcd.CampaignDelay = 100 * time.Millisecond
+		defer func() { etcd.CampaignDelay = delay }()
+
+		spec := node.Spec{Name: "postgres-04", Address: "10.0.0.4", Port: 5432, Tags: node.Tags{Priority: 2}}
+		_, connector := runElectorOn(t, mock.NewNode(t, &spec), newDCS())
+
+		t.Logf("\tWhen it runs")
+		{
+			if connector.Election.CampaignHits != 0 {
+				t.Fatalf("\t\t%s FAIL: Campaign, expected <0> but actual is <%d>", testutils.Failed, connector.Election.CampaignHits)
+			}
+			t.Logf("\t\t%s Then it must not campaign right away.", testutils.Succeed)
+
+			if !eventually(func() bool { return connector.Election.CampaignHits == 1 }) {
+				t.Fatalf("\t\t%s FAIL: Campaign, expected <1> but actual is <%d>", testutils.Failed, connector.Election.CampaignHits)
+			}
+			t.Logf("\t\t%s Then it must campaign after the delay.", testutils.Succeed)
+		}
+	}
+}
+
+// clonerNode is a node cloning its data from the members tagged clonefrom.
+type clonerNode struct {
+	*mock.Node
+	members []node.Spec
+}
+
+func (n *clonerNode) CloneFrom(members []node.Spec) {
+	n.members = members
+}
+
+func TestEtcdElector_when_follow_then_tell_the_members_tagged_clonefrom_first(t *testing.T) {
+	t.Logf("Given members tagged clonefrom, running for the election or not")
+	{
+		source := node.Spec{Name: "postgres-02", Address: "10.0.0.2", Port: 5432, Tags: node.Tags{CloneFrom: true}}
+		backup := node.Spec{Name: "postgres-backup", Address: "10.1.0.2", Port: 5432, Tags: node.Tags{NoFailover: true, CloneFrom: true}}
+		nd := &clonerNode{Node: mock.NewNode(t, &third)}
+		followed := make(chan []string, 1)
+		nd.FollowFn = func(context.Context, node.Spec) error {
+			names := make([]string, 0)
+			for _, member := range nd.members {
+				names = append(names, member.Name)
+			}
+			followed <- names
+			return nil
+		}
+		d := newDCS(me, source, third)
+		d.kvs = append(d.kvs, &mvccpb.KeyValue{Key: []byte(replicaKey + backup.Name), Value: []byte(backup.Marshal()), CreateRevision: 20})
+		_, connector := runElectorOn(t, nd, d)
+
+		t.Logf("\tWhen another node leads")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			expected := []string{source.Name, backup.Name}
+			select {
+			case actual := <-followed:
+				if !reflect.DeepEqual(actual, expected) {
+					t.Fatalf("\t\t%s FAIL: CloneFrom, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("\t\t%s FAIL: Follow, expected to follow but actual is not", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must be told the members tagged clonefrom before it follows.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package etcd_test
+
+import (
+	"context"
+	"github.com/coreos/etcd/clientv3"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWatchdog records whether it is armed, and tells whether it would still be fed.
+type fakeWatchdog struct {
+	mutex *sync.Mutex
+	armed bool
+	alive func() bool
+}
+
+func (w *fakeWatchdog) Arm(alive func() bool) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.armed, w.alive = true, alive
+	return nil
+}
+
+func (w *fakeWatchdog) Disarm() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.armed = false
+	return nil
+}
+
+func (w *fakeWatchdog) isArmed() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.armed
+}
+
+func TestEtcdElector_when_leading_then_arm_the_watchdog(t *testing.T) {
+	t.Logf("Given a node with a watchdog")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		nd.DemoteFn = func(context.Context) error { return nil }
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		wd := &fakeWatchdog{mutex: &sync.Mutex{}}
+		elector, connector := runElectorOn(t, nd, newDCS(me, candidate))
+		elector.Watchdog, elector.SessionTTL, elector.WatchdogTimeout = wd, 10*time.Second, 4*time.Second
+
+		t.Logf("\tWhen it leads")
+		{
+			connector.Election.PushLeader(leaderResponse(me))
+
+			if !wd.isArmed() || nd.LeadHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Arm, expected to be armed and lead but actual is <%v> and <%d> leads", testutils.Failed, wd.isArmed(), nd.LeadHits)
+			}
+			if !wd.alive() {
+				t.Fatalf("\t\t%s FAIL: alive, expected <true> but actual is <false>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then the watchdog must be armed and fed.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen another node leads")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+
+			if wd.isArmed() || nd.DemoteHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Disarm, expected to be demoted and disarmed but actual is <%v> and <%d> demotions", testutils.Failed, wd.isArmed(), nd.DemoteHits)
+			}
+			t.Logf("\t\t%s Then the watchdog must be disarmed once the node is demoted.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_the_lease_is_no_longer_renewed_then_stop_feeding_the_watchdog(t *testing.T) {
+	t.Logf("Given a leader with a watchdog")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		wd := &fakeWatchdog{mutex: &sync.Mutex{}}
+		elector, connector := runElectorOn(t, nd, newDCS(me, candidate))
+		elector.Watchdog, elector.SessionTTL, elector.WatchdogTimeout = wd, 10*time.Second, 4*time.Second
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen the lease is no longer renewed")
+		{
+			lastFed := time.Duration(-1)
+			for age := time.Duration(0); age <= elector.SessionTTL; age += 500 * time.Millisecond {
+				renewedAt := time.Now().Add(-age)
+				connector.LastKeepAliveFn = func() (clientv3.LeaseID, time.Time) {
+					return connector.LeaseFn(), renewedAt
+				}
+				if wd.alive() {
+					lastFed = age
+				}
+			}
+
+			if !wd.isArmed() || lastFed < 0 {
+				t.Fatalf("\t\t%s FAIL: alive, expected to stay armed and be fed at first but actual is <%v> and <%v>", testutils.Failed, wd.isArmed(), lastFed)
+			}
+			if reset := lastFed + elector.WatchdogTimeout; reset >= elector.SessionTTL-time.Second {
+				t.Fatalf("\t\t%s FAIL: alive, expected the host to reset before the lease expires in <%v> but actual is in <%v>", testutils.Failed, elector.SessionTTL, reset)
+			}
+			t.Logf("\t\t%s Then the watchdog must stop being fed early enough to reset the host before the lease expires.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the lease is renewed again")
+		{
+			connector.LastKeepAliveFn = func() (clientv3.LeaseID, time.Time) {
+				return connector.LeaseFn(), time.Now()
+			}
+
+			if !wd.alive() {
+				t.Fatalf("\t\t%s FAIL: alive, expected <true> but actual is <false>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then the watchdog must be fed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_reconnected_with_another_lease_then_stop_feeding_the_watchdog(t *testing.T) {
+	t.Logf("Given a leader with a watchdog")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		wd := &fakeWatchdog{mutex: &sync.Mutex{}}
+		elector, connector := runElectorOn(t, nd, newDCS(me, candidate))
+		elector.Watchdog, elector.SessionTTL, elector.WatchdogTimeout = wd, 10*time.Second, 4*time.Second
+		connector.Election.PushLeader(leaderResponse(me))
+
+		t.Logf("\tWhen the session is renewed under another lease")
+		{
+			connector.LastKeepAliveFn = func() (clientv3.LeaseID, time.Time) {
+				return connector.LeaseFn() + 1, time.Now()
+			}
+
+			if !wd.isArmed() || wd.alive() {
+				t.Fatalf("\t\t%s FAIL: alive, expected to stay armed and no longer fed but actual is <%v> and <%v>", testutils.Failed, wd.isArmed(), wd.alive())
+			}
+			t.Logf("\t\t%s Then the watchdog must stay armed without being fed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_the_leadership_is_lost_then_stop_feeding_the_watchdog(t *testing.T) {
+	t.Logf("Given a leader with a watchdog")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.LeadFn = func(context.Context, node.Spec) error { return nil }
+		nd.DemoteFn = func(context.Context) error { return nil }
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		wd := &fakeWatchdog{mutex: &sync.Mutex{}}
+		elector, connector := runElectorOn(t, nd, newDCS(me, candidate))
+		elector.Watchdog, elector.SessionTTL, elector.WatchdogTimeout = wd, 10*time.Second, 4*time.Second
+		connector.Election.PushLeader(leaderResponse(me))
+		alive := wd.alive
+
+		t.Logf("\tWhen another node leads while the lease is still renewed")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+
+			if alive() {
+				t.Fatalf("\t\t%s FAIL: alive, expected <false> but actual is <true>", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then the watchdog must no longer be fed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestEtcdElector_when_following_then_do_not_arm_the_watchdog(t *testing.T) {
+	t.Logf("Given a node with a watchdog")
+	{
+		nd := mock.NewNode(t, &me)
+		nd.FollowFn = func(context.Context, node.Spec) error { return nil }
+		wd := &fakeWatchdog{mutex: &sync.Mutex{}}
+		elector, connector := runElectorOn(t, nd, newDCS(candidate, me))
+		elector.Watchdog = wd
+
+		t.Logf("\tWhen it follows")
+		{
+			connector.Election.PushLeader(leaderResponse(candidate))
+
+			if wd.isArmed() || nd.FollowHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Arm, expected to follow without the watchdog but actual is <%v> and <%d> follows", testutils.Failed, wd.isArmed(), nd.FollowHits)
+			}
+			t.Logf("\t\t%s Then the watchdog must not be armed.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"os"
+	osexec "os/exec"
+	"sync"
+	"time"
+)
+
+var (
+	log = logrus.WithFields(logrus.Fields{"daemon": "exec"})
+)
+
+// Exec is a node driven by user supplied shell commands. Each command gets the leader spec
+// through the NOMINEE_LEADER_* environment variables.
+type Exec struct {
+	*node.Spec
+	config    *ConfigSpec
+	leader    node.Spec
+	mutex     *sync.RWMutex
+	doneCh    chan struct{}
+	doneOnce  *sync.Once
+	healthCtx context.Context
+}
+
+// NewExec ...
+func NewExec(cl ConfigLoader) *Exec {
+	cl.Load(context.Background())
+	config := cl.GetSpec()
+	e := &Exec{
+		Spec:     &config.NodeSpec,
+		config:   config,
+		mutex:    &sync.RWMutex{},
+		doneCh:   make(chan struct{}),
+		doneOnce: &sync.Once{},
+	}
+
+	log = logrus.WithFields(logrus.Fields{"daemon": e.GetDaemonName(), "node": e.GetName()})
+	return e
+}
+
+// GetDaemonName ...
+func (e *Exec) GetDaemonName() string {
+	return e.config.DaemonName
+}
+
+// Lead ...
+func (e *Exec) Lead(ctx context.Context, myself node.Spec) error {
+	log.Infof("exec: promote to primary as %v ...\n", myself.Name)
+	e.setLeader(myself)
+	if err := e.execOSCmd(ctx, e.config.LeadCmd, e.config.Retries); err != nil {
+		return err
+	}
+	e.watchHealth(ctx)
+	return nil
+}
+
+// Follow ...
+func (e *Exec) Follow(ctx context.Context, leader node.Spec) error {
+	log.Infof("exec: following the new leader: %v \n", leader.Name)
+	e.setLeader(leader)
+	if err := e.execOSCmd(ctx, e.config.FollowCmd, e.config.Retries); err != nil {
+		return err
+	}
+	e.watchHealth(ctx)
+	return nil
+}
+
+// Stonith ...
+func (e *Exec) Stonith(ctx context.Context) {
+	log.Infof("exec: stonithing... \n")
+	if err := e.execOSCmd(ctx, e.config.StonithCmd, 0); err != nil {
+		log.Errorf("exec: stonith command failed: %v", err)
+	}
+}
+
+// Done ...
+func (e *Exec) Done() base.DoneChan {
+	return e.doneCh
+}
+
+// watchHealth runs the health command periodically once the node got a role, as long as the context of the role.
+// It restarts with the context of the next role once the former one is done, on a reconnect to the DCS for instance.
+// The node is done as soon as the command fails after all the retries.
+func (e *Exec) watchHealth(ctx context.Context) {
+	if e.config.HealthCmd == "" {
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.healthCtx != nil && e.healthCtx.Err() == nil {
+		return
+	}
+	e.healthCtx = ctx
+	go func() {
+		ticker := time.NewTicker(e.config.HealthInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			if err := e.execOSCmd(ctx, e.config.HealthCmd, e.config.Retries); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Errorf("exec: the health check failed: %v", err)
+				e.doneOnce.Do(func() { close(e.doneCh) })
+				return
+			}
+		}
+	}()
+}
+
+func (e *Exec) execOSCmd(ctx context.Context, cmd string, retries int) error {
+	for i := retries; ; i-- {
+		err := e.run(ctx, cmd)
+		if err == nil {
+			return nil
+		}
+
+		if i <= 0 || ctx.Err() != nil {
+			return err
+		}
+
+		log.Warnf("exec: <%s> failed: %v. Retry...", cmd, err)
+		select {
+		case <-time.After(e.config.RetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (e *Exec) run(ctx context.Context, cmd string) error {
+	ctx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+	defer cancel()
+
+	command := osexec.Command("sh", "-c", cmd)
+	command.Env = append(os.Environ(), e.environment()...)
+	output := log.Writer()
+	defer output.Close()
+	command.Stdout, command.Stderr = output, output
+	setUpProcessGroup(command)
+	if err := command.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	defer close(exited)
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(command)
+		case <-exited:
+		}
+	}()
+
+	err := command.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// setLeader records the leader passed to the commands, the health command may be reading it meanwhile.
+func (e *Exec) setLeader(leader node.Spec) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.leader = leader
+}
+
+func (e *Exec) environment() []string {
+	e.mutex.RLock()
+	leader := e.leader
+	e.mutex.RUnlock()
+	return []string{
+		fmt.Sprintf("NOMINEE_LEADER_NAME=%s", leader.Name),
+		fmt.Sprintf("NOMINEE_LEADER_ADDRESS=%s", leader.Address),
+		fmt.Sprintf("NOMINEE_LEADER_PORT=%d", leader.Port),
+		fmt.Sprintf("NOMINEE_LEADER_ELECTION_KEY=%s", leader.ElectionKey),
+		fmt.Sprintf("NOMINEE_NODE_NAME=%s", e.Name),
+		fmt.Sprintf("NOMINEE_NODE_ADDRESS=%s", e.Address),
+		fmt.Sprintf("NOMINEE_NODE_PORT=%d", e.Port),
+		fmt.Sprintf("NOMINEE_CLUSTER_NAME=%s", e.config.Cluster),
+		fmt.Sprintf("NOMINEE_DOMAIN_NAME=%s", e.config.Domain),
+	}
+}
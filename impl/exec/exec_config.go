@@ -0,0 +1,83 @@
+package exec
+
+import (
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"time"
+)
+
+// ConfigLoader ...
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	NodeSpec       node.Spec
+	DaemonName     string
+	LeadCmd        string
+	FollowCmd      string
+	StonithCmd     string
+	HealthCmd      string
+	HealthInterval time.Duration
+	Timeout        time.Duration
+	Retries        int
+	RetryInterval  time.Duration
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{
+		BasicConfig: config.NewBasicConfig(),
+		NodeSpec:    node.Spec{},
+	}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	config.SetDefault("NOMINEE_EXEC_DAEMON_NAME", "exec")
+	config.SetDefault("NOMINEE_EXEC_HEALTH_INTERVAL", "10s")
+	config.SetDefault("NOMINEE_EXEC_TIMEOUT", "30s")
+	config.SetDefault("NOMINEE_EXEC_RETRIES", 3)
+	config.SetDefault("NOMINEE_EXEC_RETRY_INTERVAL", "2s")
+
+	conf.NodeSpec.Name = config.GetStringOrPanic("NOMINEE_EXEC_NODE_NAME")
+	conf.NodeSpec.Address = config.GetStringOrPanic("NOMINEE_EXEC_NODE_ADDRESS")
+	conf.NodeSpec.Port = int64(config.GetIntOrPanic("NOMINEE_EXEC_NODE_PORT"))
+	conf.NodeSpec.Tags = node.LoadTags("NOMINEE_EXEC_NODE")
+	conf.DaemonName = config.GetStringOrPanic("NOMINEE_EXEC_DAEMON_NAME")
+	conf.LeadCmd = config.GetStringOrPanic("NOMINEE_EXEC_LEAD_CMD")
+	conf.FollowCmd = config.GetStringOrPanic("NOMINEE_EXEC_FOLLOW_CMD")
+	conf.StonithCmd = config.GetStringOrPanic("NOMINEE_EXEC_STONITH_CMD")
+	conf.HealthCmd = config.GetString("NOMINEE_EXEC_HEALTH_CMD")
+	conf.HealthInterval = getPositiveDurationOrPanic("NOMINEE_EXEC_HEALTH_INTERVAL")
+	conf.Timeout = getPositiveDurationOrPanic("NOMINEE_EXEC_TIMEOUT")
+	conf.Retries = config.GetIntOrPanic("NOMINEE_EXEC_RETRIES")
+	conf.RetryInterval = getDurationOrPanic("NOMINEE_EXEC_RETRY_INTERVAL")
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	return conf
+}
+
+func getDurationOrPanic(key string) time.Duration {
+	duration, err := time.ParseDuration(config.GetStringOrPanic(key))
+	if err != nil {
+		panic(err)
+	}
+	return duration
+}
+
+// getPositiveDurationOrPanic rejects the durations the tickers cannot run with.
+func getPositiveDurationOrPanic(key string) time.Duration {
+	duration := getDurationOrPanic(key)
+	if duration <= 0 {
+		panic(fmt.Sprintf("You must specify the env var %s to a positive duration.", key))
+	}
+	return duration
+}
@@ -0,0 +1,180 @@
+package exec_test
+
+type configurationExamples struct {
+	description    string
+	cluster        string
+	domain         string
+	nodeName       string
+	nodeAddress    string
+	nodePort       string
+	daemonName     string
+	leadCmd        string
+	followCmd      string
+	stonithCmd     string
+	healthCmd      string
+	healthInterval string
+	timeout        string
+	retries        string
+	retryInterval  string
+}
+
+var validExamples = []configurationExamples{
+	{
+		description: "minimum configuration",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "daemon-01",
+		nodeAddress: "node01.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "/opt/hooks/lead.sh",
+		followCmd:   "/opt/hooks/follow.sh",
+		stonithCmd:  "/opt/hooks/stonith.sh",
+	},
+	{
+		description:    "full configuration #1",
+		cluster:        "cluster-009",
+		domain:         "domain-012",
+		nodeName:       "ldap-99",
+		nodeAddress:    "node99.ldap.priv",
+		nodePort:       "389",
+		daemonName:     "slapd",
+		leadCmd:        "ldap-promote",
+		followCmd:      "ldap-follow $NOMINEE_LEADER_ADDRESS",
+		stonithCmd:     "systemctl stop slapd",
+		healthCmd:      "ldapsearch -x -H ldap://localhost -b '' -s base",
+		healthInterval: "5s",
+		timeout:        "1m",
+		retries:        "5",
+		retryInterval:  "500ms",
+	},
+	{
+		description:    "full configuration #2",
+		cluster:        "cluster-209",
+		domain:         "domain-713",
+		nodeName:       "mongo-77",
+		nodeAddress:    "node77.mongo.priv",
+		nodePort:       "27017",
+		daemonName:     "mongod",
+		leadCmd:        "true",
+		followCmd:      "true",
+		stonithCmd:     "pkill mongod",
+		healthCmd:      "mongo --eval 'db.runCommand({ping: 1})'",
+		healthInterval: "30s",
+		timeout:        "10s",
+		retries:        "0",
+		retryInterval:  "1s",
+	},
+}
+
+var invalidExamples = []configurationExamples{
+	{
+		description: "cluster name is missing",
+		domain:      "domain-111",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "lead",
+		followCmd:   "follow",
+		stonithCmd:  "stonith",
+	},
+	{
+		description: "node port is missing",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		leadCmd:     "lead",
+		followCmd:   "follow",
+		stonithCmd:  "stonith",
+	},
+	{
+		description: "lead command is missing",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		followCmd:   "follow",
+		stonithCmd:  "stonith",
+	},
+	{
+		description: "follow command is missing",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "lead",
+		stonithCmd:  "stonith",
+	},
+	{
+		description: "stonith command is missing",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "lead",
+		followCmd:   "follow",
+	},
+	{
+		description: "the timeout is not a duration",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "lead",
+		followCmd:   "follow",
+		stonithCmd:  "stonith",
+		timeout:     "forever",
+	},
+	{
+		description:    "the health interval is zero",
+		cluster:        "cluster-001",
+		domain:         "domain-001",
+		nodeName:       "node-11",
+		nodeAddress:    "node11.daemon.priv",
+		nodePort:       "9000",
+		leadCmd:        "lead",
+		followCmd:      "follow",
+		stonithCmd:     "stonith",
+		healthInterval: "0s",
+	},
+	{
+		description:    "the health interval is negative",
+		cluster:        "cluster-001",
+		domain:         "domain-001",
+		nodeName:       "node-11",
+		nodeAddress:    "node11.daemon.priv",
+		nodePort:       "9000",
+		leadCmd:        "lead",
+		followCmd:      "follow",
+		stonithCmd:     "stonith",
+		healthInterval: "-10s",
+	},
+	{
+		description: "the timeout is zero",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "lead",
+		followCmd:   "follow",
+		stonithCmd:  "stonith",
+		timeout:     "0s",
+	},
+	{
+		description: "the timeout is negative",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "node-11",
+		nodeAddress: "node11.daemon.priv",
+		nodePort:    "9000",
+		leadCmd:     "lead",
+		followCmd:   "follow",
+		stonithCmd:  "stonith",
+		timeout:     "-30s",
+	},
+}
@@ -0,0 +1,129 @@
+package exec_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/exec"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExecConfig_loads_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run(example.description, func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := exec.NewConfigLoader()
+			loader.Load(context.TODO())
+			execConfig := loader.GetSpec()
+			if execConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, execConfig.Cluster)
+			}
+
+			if execConfig.NodeSpec.Name != example.nodeName {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Name, expected <%s> but actual is <%s>", testutils.Failed, example.nodeName, execConfig.NodeSpec.Name)
+			}
+
+			if execConfig.NodeSpec.Address != example.nodeAddress {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Address, expected <%s> but actual is <%s>", testutils.Failed, example.nodeAddress, execConfig.NodeSpec.Address)
+			}
+
+			if strconv.Itoa(int(execConfig.NodeSpec.Port)) != example.nodePort {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Port, expected <%s> but actual is <%d>", testutils.Failed, example.nodePort, execConfig.NodeSpec.Port)
+			}
+
+			expectedDaemonName := orDefault(example.daemonName, "exec")
+			if execConfig.DaemonName != expectedDaemonName {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.DaemonName, expected <%s> but actual is <%s>", testutils.Failed, expectedDaemonName, execConfig.DaemonName)
+			}
+
+			if execConfig.LeadCmd != example.leadCmd {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.LeadCmd, expected <%s> but actual is <%s>", testutils.Failed, example.leadCmd, execConfig.LeadCmd)
+			}
+
+			if execConfig.FollowCmd != example.followCmd {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.FollowCmd, expected <%s> but actual is <%s>", testutils.Failed, example.followCmd, execConfig.FollowCmd)
+			}
+
+			if execConfig.StonithCmd != example.stonithCmd {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.StonithCmd, expected <%s> but actual is <%s>", testutils.Failed, example.stonithCmd, execConfig.StonithCmd)
+			}
+
+			if execConfig.HealthCmd != example.healthCmd {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.HealthCmd, expected <%s> but actual is <%s>", testutils.Failed, example.healthCmd, execConfig.HealthCmd)
+			}
+
+			expectedHealthInterval, _ := time.ParseDuration(orDefault(example.healthInterval, "10s"))
+			if execConfig.HealthInterval != expectedHealthInterval {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.HealthInterval, expected <%v> but actual is <%v>", testutils.Failed, expectedHealthInterval, execConfig.HealthInterval)
+			}
+
+			expectedTimeout, _ := time.ParseDuration(orDefault(example.timeout, "30s"))
+			if execConfig.Timeout != expectedTimeout {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Timeout, expected <%v> but actual is <%v>", testutils.Failed, expectedTimeout, execConfig.Timeout)
+			}
+
+			expectedRetries, _ := strconv.Atoi(orDefault(example.retries, "3"))
+			if execConfig.Retries != expectedRetries {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Retries, expected <%d> but actual is <%d>", testutils.Failed, expectedRetries, execConfig.Retries)
+			}
+
+			expectedRetryInterval, _ := time.ParseDuration(orDefault(example.retryInterval, "2s"))
+			if execConfig.RetryInterval != expectedRetryInterval {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.RetryInterval, expected <%v> but actual is <%v>", testutils.Failed, expectedRetryInterval, execConfig.RetryInterval)
+			}
+		})
+	}
+}
+
+func TestExecConfig_panics_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run(example.description, func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			execConfig := exec.NewConfigLoader()
+			execConfig.Load(context.TODO())
+		})
+	}
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+var variables = []string{
+	"NOMINEE_CLUSTER_NAME", "NOMINEE_DOMAIN_NAME",
+	"NOMINEE_EXEC_NODE_NAME", "NOMINEE_EXEC_NODE_ADDRESS", "NOMINEE_EXEC_NODE_PORT", "NOMINEE_EXEC_DAEMON_NAME",
+	"NOMINEE_EXEC_LEAD_CMD", "NOMINEE_EXEC_FOLLOW_CMD", "NOMINEE_EXEC_STONITH_CMD",
+	"NOMINEE_EXEC_HEALTH_CMD", "NOMINEE_EXEC_HEALTH_INTERVAL",
+	"NOMINEE_EXEC_TIMEOUT", "NOMINEE_EXEC_RETRIES", "NOMINEE_EXEC_RETRY_INTERVAL",
+}
+
+func declareConfigurationExample(example configurationExamples) {
+	values := []string{
+		example.cluster, example.domain,
+		example.nodeName, example.nodeAddress, example.nodePort, example.daemonName,
+		example.leadCmd, example.followCmd, example.stonithCmd,
+		example.healthCmd, example.healthInterval,
+		example.timeout, example.retries, example.retryInterval,
+	}
+	for i, variable := range variables {
+		_ = os.Setenv(variable, values[i])
+	}
+}
+
+func tearsDown() {
+	for _, variable := range variables {
+		_ = os.Unsetenv(variable)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package exec
+
+import (
+	osexec "os/exec"
+	"syscall"
+)
+
+// setUpProcessGroup runs the command in its own process group, so its children can be killed along with it.
+func setUpProcessGroup(command *osexec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func killProcessGroup(command *osexec.Cmd) {
+	_ = syscall.Kill(-command.Process.Pid, syscall.SIGKILL)
+}
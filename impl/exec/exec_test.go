@@ -0,0 +1,205 @@
+package exec_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/exec"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var leader = node.Spec{ElectionKey: "nominee/domain/domain-001/cluster/cluster-001/001", Name: "daemon-01", Address: "node01.daemon.priv", Port: 9001}
+
+// newExec returns a node whose commands are written by configure. The commands can use the dir to leave traces.
+func newExec(t *testing.T, configure func(spec *exec.ConfigSpec, dir string)) (*exec.Exec, string) {
+	dir := t.TempDir()
+	spec := &exec.ConfigSpec{
+		BasicConfig:    &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		NodeSpec:       node.Spec{Name: "daemon-02", Address: "node02.daemon.priv", Port: 9002},
+		DaemonName:     "daemon",
+		LeadCmd:        "true",
+		FollowCmd:      "true",
+		StonithCmd:     "true",
+		HealthInterval: 20 * time.Millisecond,
+		Timeout:        time.Second,
+		RetryInterval:  time.Millisecond,
+	}
+	configure(spec, dir)
+	return exec.NewExec(&mock.ExecConfigSpec{ConfigSpec: spec}), dir
+}
+
+func read(t *testing.T, path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("\t\t%s FATAL: failed to read %s: %v", testutils.Failed, path, err)
+	}
+	return string(content)
+}
+
+func TestExec_when_follow_then_pass_the_leader_to_the_command(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.FollowCmd = fmt.Sprintf(`echo "$NOMINEE_LEADER_NAME $NOMINEE_LEADER_ADDRESS $NOMINEE_LEADER_PORT $NOMINEE_LEADER_ELECTION_KEY" > %s/follow`, dir)
+	})
+
+	if err := e.Follow(context.TODO(), leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Exec.Follow, %v", testutils.Failed, err)
+	}
+
+	expected := fmt.Sprintf("%s %s %d %s\n", leader.Name, leader.Address, leader.Port, leader.ElectionKey)
+	if actual := read(t, filepath.Join(dir, "follow")); actual != expected {
+		t.Fatalf("\t\t%s FAIL: Exec.Follow, expected <%s> but actual is <%s>", testutils.Failed, expected, actual)
+	}
+}
+
+func TestExec_when_lead_then_pass_itself_as_the_leader(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.LeadCmd = fmt.Sprintf(`echo "$NOMINEE_LEADER_NAME $NOMINEE_NODE_NAME $NOMINEE_CLUSTER_NAME" > %s/lead`, dir)
+	})
+
+	if err := e.Lead(context.TODO(), *e.Spec); err != nil {
+		t.Fatalf("\t\t%s FATAL: Exec.Lead, %v", testutils.Failed, err)
+	}
+
+	expected := "daemon-02 daemon-02 cluster-001\n"
+	if actual := read(t, filepath.Join(dir, "lead")); actual != expected {
+		t.Fatalf("\t\t%s FAIL: Exec.Lead, expected <%s> but actual is <%s>", testutils.Failed, expected, actual)
+	}
+}
+
+func TestExec_when_the_command_fails_then_retry_it(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.Retries = 2
+		spec.LeadCmd = fmt.Sprintf(`echo attempt >> %s/attempts; exit 1`, dir)
+	})
+
+	if err := e.Lead(context.TODO(), *e.Spec); err == nil {
+		t.Fatalf("\t\t%s FAIL: Exec.Lead, expected an error. Actually not.", testutils.Failed)
+	}
+
+	if attempts := strings.Count(read(t, filepath.Join(dir, "attempts")), "attempt"); attempts != 3 {
+		t.Fatalf("\t\t%s FAIL: Exec.Lead, expected 3 attempts but actual is <%d>", testutils.Failed, attempts)
+	}
+}
+
+func TestExec_when_the_command_recovers_then_succeed(t *testing.T) {
+	e, _ := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.Retries = 3
+		spec.FollowCmd = fmt.Sprintf(`echo attempt >> %s/attempts; test $(wc -l < %s/attempts) -ge 2`, dir, dir)
+	})
+
+	if err := e.Follow(context.TODO(), leader); err != nil {
+		t.Fatalf("\t\t%s FAIL: Exec.Follow, %v", testutils.Failed, err)
+	}
+}
+
+func TestExec_when_the_command_times_out_then_fail(t *testing.T) {
+	e, _ := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.Timeout = 50 * time.Millisecond
+		spec.LeadCmd = "sleep 5"
+	})
+
+	start := time.Now()
+	if err := e.Lead(context.TODO(), *e.Spec); err == nil {
+		t.Fatalf("\t\t%s FAIL: Exec.Lead, expected an error. Actually not.", testutils.Failed)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("\t\t%s FAIL: Exec.Lead, expected to give up after the timeout but it took <%v>", testutils.Failed, elapsed)
+	}
+}
+
+func TestExec_when_stonith_then_run_the_command(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.StonithCmd = fmt.Sprintf(`touch %s/stonith`, dir)
+	})
+
+	e.Stonith(context.TODO())
+
+	_ = read(t, filepath.Join(dir, "stonith"))
+}
+
+func TestExec_when_the_health_check_fails_then_done(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.HealthCmd = fmt.Sprintf(`test ! -f %s/unhealthy`, dir)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := e.Follow(ctx, leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Exec.Follow, %v", testutils.Failed, err)
+	}
+	testutils.AsyncAssertion.ItMustKeepRunning(t, e.Done())
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "unhealthy"), nil, 0600); err != nil {
+		t.Fatalf("\t\t%s FATAL: %v", testutils.Failed, err)
+	}
+
+	testutils.AsyncAssertion.ItMustBeStopped(t, e.Done())
+}
+
+func TestExec_when_follow_during_the_health_check_then_pass_the_new_leader_to_it(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.HealthCmd = fmt.Sprintf(`echo "$NOMINEE_LEADER_NAME" > %s/health`, dir)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := e.Follow(ctx, leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Exec.Follow, %v", testutils.Failed, err)
+	}
+	newLeader := node.Spec{Name: "daemon-03", Address: "node03.daemon.priv", Port: 9003}
+	for i := 0; i < 5; i++ {
+		time.Sleep(10 * time.Millisecond)
+		if err := e.Follow(ctx, newLeader); err != nil {
+			t.Fatalf("\t\t%s FATAL: Exec.Follow, %v", testutils.Failed, err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		content, _ := ioutil.ReadFile(filepath.Join(dir, "health"))
+		if string(content) == newLeader.Name+"\n" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("\t\t%s FAIL: Exec health, expected <%s> but actual is <%s>", testutils.Failed, newLeader.Name, content)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExec_when_the_role_context_is_done_then_check_the_health_on_the_next_role(t *testing.T) {
+	e, dir := newExec(t, func(spec *exec.ConfigSpec, dir string) {
+		spec.HealthCmd = fmt.Sprintf(`test ! -f %s/unhealthy`, dir)
+	})
+	first, cancelFirst := context.WithCancel(context.Background())
+	if err := e.Lead(first, leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Exec.Lead, %v", testutils.Failed, err)
+	}
+	cancelFirst()
+
+	second, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+	if err := e.Lead(second, leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Exec.Lead, %v", testutils.Failed, err)
+	}
+	testutils.AsyncAssertion.ItMustKeepRunning(t, e.Done())
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "unhealthy"), nil, 0600); err != nil {
+		t.Fatalf("\t\t%s FATAL: %v", testutils.Failed, err)
+	}
+
+	testutils.AsyncAssertion.ItMustBeStopped(t, e.Done())
+}
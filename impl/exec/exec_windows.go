@@ -0,0 +1,11 @@
+package exec
+
+import (
+	osexec "os/exec"
+)
+
+func setUpProcessGroup(*osexec.Cmd) {}
+
+func killProcessGroup(command *osexec.Cmd) {
+	_ = command.Process.Kill()
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"github.com/haproxytech/client-native/v2/configuration"
+	"github.com/haproxytech/client-native/v2/runtime"
 	"github.com/haproxytech/models/v2"
 	"github/mlyahmed.io/nominee/pkg/logger"
 	"github/mlyahmed.io/nominee/pkg/node"
 	"github/mlyahmed.io/nominee/pkg/proxy"
+	"net"
 	"os"
 	"os/exec"
+	"strconv"
 	"sync"
 )
 
@@ -17,17 +20,28 @@ import (
 type HAProxy struct {
 	*proxy.BasicProxy
 	*configuration.Client
-	currentTx *models.Transaction
-	version   int64
-	mutex     *sync.Mutex
-	wg        *sync.WaitGroup
-	status    proxy.Status
+	runtimeAPI *runtime.Client
+	currentTx  *models.Transaction
+	version    int64
+	mutex      *sync.Mutex
+	wg         *sync.WaitGroup
+	status     proxy.Status
+	pid        int
 }
 
+// Publish switches the servers through the Runtime API. It reloads HAProxy only when the topology cannot be expressed at runtime.
 func (p *HAProxy) Publish(leader *node.Spec, followers ...*node.Spec) error {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
+	if p.status == proxy.Started {
+		err := p.switchServers(leader, followers)
+		if err == nil {
+			return nil
+		}
+		logger.G(p.Ctx).Warnf("cannot switch the servers at runtime, reloading: %v", err)
+	}
+
 	p.startTx()
 	p.removeAllServers()
 
@@ -56,6 +70,7 @@ func NewHAProxy(cl ConfigLoader) *HAProxy {
 	haProxy := HAProxy{
 		BasicProxy: proxy.NewBasicProxy(),
 		Client:     &configuration.Client{},
+		runtimeAPI: &runtime.Client{},
 		mutex:      &sync.Mutex{},
 		wg:         &sync.WaitGroup{},
 		status:     proxy.Stopped,
@@ -73,6 +88,10 @@ func NewHAProxy(cl ConfigLoader) *HAProxy {
 		panic(err)
 	}
 
+	if err := haProxy.runtimeAPI.InitWithSockets(map[int]string{1: config.RuntimeSocket}); err != nil {
+		panic(err)
+	}
+
 	version, err := haProxy.GetVersion("")
 	if err != nil {
 		panic(err)
@@ -90,35 +109,154 @@ func NewHAProxy(cl ConfigLoader) *HAProxy {
 }
 
 func (p *HAProxy) start() {
+	log := logger.G(p.Ctx)
+
+	args := []string{"-db", "-f", p.ConfigurationFile}
+	if p.status == proxy.Started {
+		args = append(args, "-sf", strconv.Itoa(p.pid))
+	}
+	cmd := exec.CommandContext(p.Ctx, p.Haproxy, args...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		log.Errorf("Failed to start %v", err)
+		return
+	}
+	pid := cmd.Process.Pid
+	if p.status == proxy.Started {
+		log.Infof("Restarted with pid %d", pid)
+	} else {
+		log.Infof("Started with pid %d", pid)
+	}
+	p.status, p.pid = proxy.Started, pid
+
 	p.wg.Add(1)
 	go func() {
 		defer p.wg.Done()
-		log := logger.G(p.Ctx)
+		if err := cmd.Wait(); err != nil {
+			log.Debugf("Stopped with pid %d because %v", pid, err)
+			return
+		}
+		log.Infof("Stopped with pid %d", pid)
+	}()
+}
+
+// switchServers moves the leader to be_primary and the followers to be_standby through the servers already declared
+// in the backends, typically by server-template.
+func (p *HAProxy) switchServers(leader *node.Spec, followers []*node.Spec) error {
+	if err := p.switchBackend(primaryBackend, []*node.Spec{leader}); err != nil {
+		return err
+	}
+	return p.switchBackend(standbyBackend, followers)
+}
+
+type target struct {
+	name string
+	ip   string
+	port int64
+}
 
-		cmdStr := fmt.Sprintf("%s -db -f %s", p.Haproxy, p.ConfigurationFile)
-		if p.status == proxy.Started {
-			cmdStr = fmt.Sprintf("%s -db -f %s -sf $(cat /run/haproxy.pid)", p.Haproxy, p.ConfigurationFile)
+func (p *HAProxy) switchBackend(backend string, nodes []*node.Spec) error {
+	servers, err := p.runtimeAPI.GetServersState(backend)
+	if err != nil {
+		return err
+	}
+	if servers == nil {
+		return fmt.Errorf("no runtime state for the backend %s", backend)
+	}
+
+	var targets []target
+	for _, nod := range nodes {
+		if nod == nil || nod.Address == "" {
+			continue
 		}
-		cmd := exec.CommandContext(p.Ctx, "bash", "-c", cmdStr)
-		cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+		ip, err := resolve(nod.Address)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, target{name: nod.Name, ip: ip, port: nod.Port})
+	}
 
-		if err := cmd.Start(); err != nil {
-			log.Errorf("Failed to start %v", err)
-			return
+	// A server already on a node keeps it, the other nodes go to the remaining servers in order.
+	var kept []*models.RuntimeServer
+	var moved []target
+	remaining := make([]*models.RuntimeServer, 0, len(servers))
+	for _, server := range servers {
+		if server != nil {
+			remaining = append(remaining, server)
 		}
-		pid := cmd.Process.Pid
-		if p.status == proxy.Started {
-			log.Infof("Restarted with pid %d", pid)
+	}
+	if len(targets) > len(remaining) {
+		return fmt.Errorf("%d servers in the backend %s for %d nodes", len(remaining), backend, len(targets))
+	}
+
+	for _, t := range targets {
+		if i := indexOf(remaining, t); i >= 0 {
+			kept = append(kept, remaining[i])
+			remaining = append(remaining[:i], remaining[i+1:]...)
 		} else {
-			log.Infof("Started with pid %d", pid)
+			moved = append(moved, t)
 		}
-		p.status = proxy.Started
-		if err := cmd.Wait(); err != nil {
-			log.Debugf("Stopped with pid %d because %v", pid, err)
-			return
+	}
+
+	// Drain the released servers first, so the backend never routes to the old and the new node at once.
+	for _, server := range remaining[len(moved):] {
+		if err := p.setServerState(backend, server, "maint"); err != nil {
+			return err
 		}
-		log.Infof("Stopped with pid %d", pid)
-	}()
+	}
+
+	for _, server := range kept {
+		if err := p.setServerState(backend, server, "ready"); err != nil {
+			return err
+		}
+	}
+
+	for i, t := range moved {
+		server := remaining[i]
+		if err := p.setServerState(backend, server, "maint"); err != nil {
+			return err
+		}
+		if err := p.runtimeAPI.SetServerAddr(backend, server.Name, t.ip, int(t.port)); err != nil {
+			return err
+		}
+		if err := p.runtimeAPI.SetServerState(backend, server.Name, "ready"); err != nil {
+			return err
+		}
+		logger.G(p.Ctx).Infof("server %s/%s switched to %s (%s:%d)", backend, server.Name, t.name, t.ip, t.port)
+	}
+	return nil
+}
+
+func (p *HAProxy) setServerState(backend string, server *models.RuntimeServer, state string) error {
+	if server.AdminState == state {
+		return nil
+	}
+	return p.runtimeAPI.SetServerState(backend, server.Name, state)
+}
+
+func indexOf(servers []*models.RuntimeServer, t target) int {
+	for i, server := range servers {
+		if server.Address == t.ip && server.Port != nil && *server.Port == t.port {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolve returns the IP of the address, the Runtime API does not take host names.
+func resolve(address string) (string, error) {
+	if ip := net.ParseIP(address); ip != nil {
+		return ip.String(), nil
+	}
+	ips, err := net.LookupIP(address)
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("no IP for %s", address)
+	}
+	return ips[0].String(), nil
 }
 
 func (p *HAProxy) removeAllServers() {
@@ -14,9 +14,10 @@ type ConfigLoader interface {
 // ConfigSpec ...
 type ConfigSpec struct {
 	*config.BasicConfig
-	ConfigFile string
-	ExecFile   string
-	TxDir      string
+	ConfigFile    string
+	ExecFile      string
+	TxDir         string
+	RuntimeSocket string
 }
 
 // NewConfigLoader ...
@@ -31,10 +32,12 @@ func (conf *ConfigSpec) Load(ctx context.Context) {
 	config.SetDefault("NOMINEE_HAPROXY_CONFIG_FILE", "/usr/local/etc/haproxy/haproxy.cfg")
 	config.SetDefault("NOMINEE_HAPROXY_EXEC_FILE", "/usr/local/sbin/haproxy")
 	config.SetDefault("NOMINEE_HAPROXY_TX_DIR", "/tmp/haproxy")
+	config.SetDefault("NOMINEE_HAPROXY_RUNTIME_SOCKET", "/var/run/haproxy.sock")
 
 	conf.ConfigFile = config.GetString("NOMINEE_HAPROXY_CONFIG_FILE")
 	conf.ExecFile = config.GetString("NOMINEE_HAPROXY_EXEC_FILE")
 	conf.TxDir = config.GetString("NOMINEE_HAPROXY_TX_DIR")
+	conf.RuntimeSocket = config.GetString("NOMINEE_HAPROXY_RUNTIME_SOCKET")
 }
 
 func (conf *ConfigSpec) GetSpec() *ConfigSpec {
@@ -1,12 +1,13 @@
 package haproxy_test
 
 type configurationExample struct {
-	description string
-	cluster     string
-	domain      string
-	configFile  string
-	execFile    string
-	txDir       string
+	description   string
+	cluster       string
+	domain        string
+	configFile    string
+	execFile      string
+	txDir         string
+	runtimeSocket string
 }
 
 var validExamples = []configurationExample{
@@ -16,12 +17,13 @@ var validExamples = []configurationExample{
 		domain:      "domain-002",
 	},
 	{
-		description: "full configuration #1",
-		cluster:     "cluster-111",
-		domain:      "domain-542",
-		configFile:  "/etc/haproxy/haproxy.cfg",
-		execFile:    "/bin/haproxy",
-		txDir:       "/usr/local/tmp/haproxy",
+		description:   "full configuration #1",
+		cluster:       "cluster-111",
+		domain:        "domain-542",
+		configFile:    "/etc/haproxy/haproxy.cfg",
+		execFile:      "/bin/haproxy",
+		txDir:         "/usr/local/tmp/haproxy",
+		runtimeSocket: "/run/haproxy/admin.sock",
 	},
 	{
 		description: "full configuration #2",
@@ -68,6 +68,18 @@ func TestHAProxyConfig_loads_configurations(t *testing.T) {
 						}
 					}
 					t.Logf("\t\t%s Then the ConfigSpec.TxDir should be loaded.", testutils.Succeed)
+
+					if example.runtimeSocket == "" {
+						const defaultRuntimeSocket = "/var/run/haproxy.sock"
+						if config.RuntimeSocket != defaultRuntimeSocket {
+							t.Fatalf("\t\t%s FAIL: ConfigSpec.RuntimeSocket, expected the default <%s> but actual is <%s>", testutils.Failed, defaultRuntimeSocket, config.RuntimeSocket)
+						}
+					} else {
+						if config.RuntimeSocket != example.runtimeSocket {
+							t.Fatalf("\t\t%s FAIL: ConfigSpec.RuntimeSocket, expected <%s> but actual is <%s>", testutils.Failed, example.runtimeSocket, config.RuntimeSocket)
+						}
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.RuntimeSocket should be loaded.", testutils.Succeed)
 				}
 			})
 		}
@@ -107,6 +119,7 @@ func declareConfigurationExample(example configurationExample) {
 	_ = os.Setenv("NOMINEE_HAPROXY_CONFIG_FILE", example.configFile)
 	_ = os.Setenv("NOMINEE_HAPROXY_EXEC_FILE", example.execFile)
 	_ = os.Setenv("NOMINEE_HAPROXY_TX_DIR", example.txDir)
+	_ = os.Setenv("NOMINEE_HAPROXY_RUNTIME_SOCKET", example.runtimeSocket)
 }
 
 func tearsDown() {
@@ -115,4 +128,5 @@ func tearsDown() {
 	_ = os.Unsetenv("NOMINEE_HAPROXY_CONFIG_FILE")
 	_ = os.Unsetenv("NOMINEE_HAPROXY_EXEC_FILE")
 	_ = os.Unsetenv("NOMINEE_HAPROXY_TX_DIR")
+	_ = os.Unsetenv("NOMINEE_HAPROXY_RUNTIME_SOCKET")
 }
@@ -0,0 +1,263 @@
+package haproxy_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/haproxy"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+const haproxyCfg = `global
+  master-worker
+
+defaults
+  mode tcp
+
+backend be_primary
+  mode tcp
+  server-template primary 1 127.0.0.1:5432 check disabled
+
+backend be_standby
+  mode tcp
+  server-template standby 1-2 127.0.0.1:5432 check disabled
+`
+
+// fakeHAProxy passes the configuration checks and records the other runs before it waits to be killed.
+const fakeHAProxy = `#!/bin/sh
+for arg in "$@"; do
+  [ "$arg" = "-c" ] && exit 0
+done
+echo "$@" >> %s
+exec sleep 600
+`
+
+var (
+	leader    = node.Spec{Name: "postgres-01", Address: "10.0.0.1", Port: 5432}
+	follower1 = node.Spec{Name: "postgres-02", Address: "10.0.0.2", Port: 5432}
+	follower2 = node.Spec{Name: "postgres-03", Address: "10.0.0.3", Port: 5433}
+	follower3 = node.Spec{Name: "postgres-04", Address: "10.0.0.4", Port: 5432}
+)
+
+type fixture struct {
+	proxy  *haproxy.HAProxy
+	socket *mock.HAProxySocket
+	cfg    string
+	runs   string
+}
+
+func newHAProxy(t *testing.T) *fixture {
+	dir := t.TempDir()
+	f := &fixture{cfg: filepath.Join(dir, "haproxy.cfg"), runs: filepath.Join(dir, "haproxy.runs")}
+	exe := filepath.Join(dir, "haproxy")
+	if err := ioutil.WriteFile(f.cfg, []byte(haproxyCfg), 0644); err != nil {
+		t.Fatalf("\t\t%s FAIL: WriteFile, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	if err := ioutil.WriteFile(exe, []byte(fmt.Sprintf(fakeHAProxy, f.runs)), 0755); err != nil {
+		t.Fatalf("\t\t%s FAIL: WriteFile, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+
+	f.socket = mock.NewHAProxySocket(t)
+	t.Cleanup(func() { _ = f.socket.Close() })
+	f.socket.AddServer("be_primary", mock.HAProxyServer{Name: "primary1", Address: "127.0.0.1", Port: 5432})
+	f.socket.AddServer("be_standby", mock.HAProxyServer{Name: "standby1", Address: "127.0.0.1", Port: 5432})
+	f.socket.AddServer("be_standby", mock.HAProxyServer{Name: "standby2", Address: "127.0.0.1", Port: 5432})
+
+	f.proxy = haproxy.NewHAProxy(&mock.HAProxyConfigSpec{ConfigSpec: &haproxy.ConfigSpec{
+		BasicConfig:   &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		ConfigFile:    f.cfg,
+		ExecFile:      exe,
+		TxDir:         filepath.Join(dir, "tx"),
+		RuntimeSocket: f.socket.Path,
+	}})
+	t.Cleanup(func() { f.proxy.Stonith(context.Background()) })
+	f.waitForRuns(t, 1)
+	return f
+}
+
+// waitForRuns waits for the expected number of HAProxy runs, then makes sure there is no more.
+func (f *fixture) waitForRuns(t *testing.T, expected int) []string {
+	var runs []string
+	deadline := time.Now().Add(5 * time.Second)
+	for len(runs) < expected && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		content, _ := ioutil.ReadFile(f.runs)
+		runs = strings.Split(strings.TrimSpace(string(content)), "\n")
+	}
+	time.Sleep(100 * time.Millisecond)
+	content, _ := ioutil.ReadFile(f.runs)
+	runs = strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(runs) != expected {
+		t.Fatalf("\t\t%s FAIL: HAProxy runs, expected <%d> but actual is <%d>: %v", testutils.Failed, expected, len(runs), runs)
+	}
+	return runs
+}
+
+func (f *fixture) commandsSince(fn func()) []string {
+	before := len(f.socket.Commands())
+	fn()
+	return f.socket.Commands()[before:]
+}
+
+func TestHAProxy_switches_the_servers_at_runtime(t *testing.T) {
+	t.Logf("Given a started HAProxy with server templates")
+	{
+		f := newHAProxy(t)
+		t.Logf("\tWhen it publishes a leader and two followers")
+		{
+			commands := f.commandsSince(func() {
+				if err := f.proxy.Publish(&leader, &follower1, &follower2); err != nil {
+					t.Fatalf("\t\t%s FAIL: Publish, expected no error but actual is <%v>", testutils.Failed, err)
+				}
+			})
+
+			expected := []string{
+				"set server be_primary/primary1 addr 10.0.0.1 port 5432",
+				"set server be_primary/primary1 state ready",
+				"set server be_standby/standby1 addr 10.0.0.2 port 5432",
+				"set server be_standby/standby1 state ready",
+				"set server be_standby/standby2 addr 10.0.0.3 port 5433",
+				"set server be_standby/standby2 state ready",
+			}
+			if !reflect.DeepEqual(commands, expected) {
+				t.Fatalf("\t\t%s FAIL: runtime commands, expected <%v> but actual is <%v>", testutils.Failed, expected, commands)
+			}
+			t.Logf("\t\t%s Then the servers must be addressed and set ready through the Runtime API.", testutils.Succeed)
+
+			f.waitForRuns(t, 1)
+			t.Logf("\t\t%s Then HAProxy must not be reloaded.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHAProxy_drains_the_old_leader_before_it_readies_the_new_one(t *testing.T) {
+	t.Logf("Given a started HAProxy routing to a leader and a follower")
+	{
+		f := newHAProxy(t)
+		_ = f.proxy.Publish(&leader, &follower1)
+
+		t.Logf("\tWhen the follower becomes the leader")
+		{
+			commands := f.commandsSince(func() {
+				_ = f.proxy.Publish(&follower1, &leader)
+			})
+
+			expected := []string{
+				"set server be_primary/primary1 state maint",
+				"set server be_primary/primary1 addr 10.0.0.2 port 5432",
+				"set server be_primary/primary1 state ready",
+				"set server be_standby/standby1 state maint",
+				"set server be_standby/standby1 addr 10.0.0.1 port 5432",
+				"set server be_standby/standby1 state ready",
+			}
+			if !reflect.DeepEqual(commands, expected) {
+				t.Fatalf("\t\t%s FAIL: runtime commands, expected <%v> but actual is <%v>", testutils.Failed, expected, commands)
+			}
+			t.Logf("\t\t%s Then each server must be in maintenance before it is readdressed.", testutils.Succeed)
+
+			f.waitForRuns(t, 1)
+			t.Logf("\t\t%s Then HAProxy must not be reloaded.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHAProxy_keeps_the_servers_already_on_the_nodes(t *testing.T) {
+	t.Logf("Given a started HAProxy routing to a leader and two followers")
+	{
+		f := newHAProxy(t)
+		_ = f.proxy.Publish(&leader, &follower1, &follower2)
+
+		t.Logf("\tWhen it publishes the same topology")
+		{
+			commands := f.commandsSince(func() {
+				_ = f.proxy.Publish(&leader, &follower2, &follower1)
+			})
+			if len(commands) != 0 {
+				t.Fatalf("\t\t%s FAIL: runtime commands, expected none but actual is <%v>", testutils.Failed, commands)
+			}
+			t.Logf("\t\t%s Then no server must be touched.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen a follower leaves")
+		{
+			commands := f.commandsSince(func() {
+				_ = f.proxy.Publish(&leader, &follower2)
+			})
+			expected := []string{"set server be_standby/standby1 state maint"}
+			if !reflect.DeepEqual(commands, expected) {
+				t.Fatalf("\t\t%s FAIL: runtime commands, expected <%v> but actual is <%v>", testutils.Failed, expected, commands)
+			}
+			t.Logf("\t\t%s Then only its server must go in maintenance.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the leader is lost")
+		{
+			commands := f.commandsSince(func() {
+				_ = f.proxy.Publish(nil, &follower2)
+			})
+			expected := []string{"set server be_primary/primary1 state maint"}
+			if !reflect.DeepEqual(commands, expected) {
+				t.Fatalf("\t\t%s FAIL: runtime commands, expected <%v> but actual is <%v>", testutils.Failed, expected, commands)
+			}
+			t.Logf("\t\t%s Then the primary server must go in maintenance.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHAProxy_reloads_when_there_are_not_enough_servers(t *testing.T) {
+	t.Logf("Given a started HAProxy with two standby servers")
+	{
+		f := newHAProxy(t)
+		t.Logf("\tWhen it publishes three followers")
+		{
+			_ = f.proxy.Publish(&leader, &follower1, &follower2, &follower3)
+
+			runs := f.waitForRuns(t, 2)
+			if !strings.Contains(runs[1], "-sf ") {
+				t.Fatalf("\t\t%s FAIL: HAProxy run, expected to replace the previous process but actual is <%s>", testutils.Failed, runs[1])
+			}
+			t.Logf("\t\t%s Then HAProxy must be reloaded.", testutils.Succeed)
+
+			content, _ := ioutil.ReadFile(f.cfg)
+			for _, expected := range []string{"server postgres-01 10.0.0.1:5432", "server postgres-04 10.0.0.4:5432", "server-template standby 1-2"} {
+				if !strings.Contains(string(content), expected) {
+					t.Fatalf("\t\t%s FAIL: configuration, expected to contain <%s> but actual is <%s>", testutils.Failed, expected, content)
+				}
+			}
+			t.Logf("\t\t%s Then the configuration must declare the nodes and keep the templates.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHAProxy_reloads_when_the_runtime_api_fails(t *testing.T) {
+	t.Logf("Given a started HAProxy whose Runtime API rejects the changes")
+	{
+		f := newHAProxy(t)
+		f.socket.Reply(func(command string) string {
+			if strings.HasPrefix(command, "set server ") {
+				return "[3]: Permission denied."
+			}
+			return f.socket.Answer(command)
+		})
+
+		t.Logf("\tWhen it publishes a leader")
+		{
+			_ = f.proxy.Publish(&leader)
+			f.waitForRuns(t, 2)
+			t.Logf("\t\t%s Then HAProxy must be reloaded.", testutils.Succeed)
+		}
+	}
+}
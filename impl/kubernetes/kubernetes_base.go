@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/metrics"
+	"github/mlyahmed.io/nominee/pkg/node"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// SpecAnnotation holds the node.Spec advertised by the pod
+	SpecAnnotation = "nominee.io/spec"
+
+	// DomainLabel ...
+	DomainLabel = "nominee.io/domain"
+
+	// ClusterLabel ...
+	ClusterLabel = "nominee.io/cluster"
+)
+
+// Kubernetes ...
+type Kubernetes struct {
+	*ConfigSpec
+	Connector  Connector
+	client     kubernetes.Interface
+	ctx        context.Context
+	cancel     func()
+	session    chan struct{}
+	failBackFn func() error
+}
+
+var (
+	log *logrus.Entry
+)
+
+// NewKubernetes ...
+func NewKubernetes(cl ConfigLoader) *Kubernetes {
+	return &Kubernetes{
+		Connector:  NewDefaultConnector(),
+		ConfigSpec: cl.GetSpec(),
+		cancel:     func() {},
+		failBackFn: func() error { return nil },
+	}
+}
+
+// Cleanup ...
+func (kube *Kubernetes) Cleanup() {
+	kube.cancel()
+	kube.Connector.Cleanup()
+}
+
+func (kube *Kubernetes) connect(ctx context.Context) error {
+	var err error
+	if kube.client, err = kube.Connector.Connect(ctx, kube.ConfigSpec); err != nil {
+		return err
+	}
+	kube.ctx, kube.cancel = context.WithCancel(ctx)
+	kube.session = make(chan struct{})
+	return nil
+}
+
+// closeSession notifies the session has been lost, unless it has been canceled on purpose.
+func (kube *Kubernetes) closeSession(ctx context.Context, session chan struct{}) {
+	if ctx.Err() != nil {
+		return
+	}
+	select {
+	case <-session:
+	default:
+		close(session)
+	}
+}
+
+func (kube *Kubernetes) listenToTheSession() {
+	go func() {
+		for { //TODO: retries limit
+			<-kube.session
+			log.Infof("session closed. Try to reconnect...")
+			metrics.SessionReconnected("kubernetes")
+			_ = kube.failBackFn()
+		}
+	}()
+}
+
+func (kube *Kubernetes) lookupNodeSpec(ctx context.Context, podName string) (node.Spec, error) {
+	pod, err := kube.client.CoreV1().Pods(kube.Namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return node.Spec{}, err
+	}
+
+	spec, ok := kube.toNodeSpec(pod)
+	if !ok {
+		return spec, fmt.Errorf("the pod %s does not advertise any node spec", podName)
+	}
+	return spec, nil
+}
+
+// leaseHolder reads the pod holding the Lease, empty when it is released.
+func (kube *Kubernetes) leaseHolder(ctx context.Context) (string, error) {
+	lease, err := kube.client.CoordinationV1().Leases(kube.Namespace).Get(ctx, kube.leaseName(), metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if lease.Spec.HolderIdentity == nil {
+		return "", nil
+	}
+	return *lease.Spec.HolderIdentity, nil
+}
+
+func (kube *Kubernetes) toNodeSpec(pod *corev1.Pod) (node.Spec, bool) {
+	var value node.Spec
+	data, ok := pod.Annotations[SpecAnnotation]
+	if !ok {
+		return value, false
+	}
+	value, _ = node.Unmarshal([]byte(data))
+	value.ElectionKey = fmt.Sprintf("%s/%s", kube.electionKey(), pod.Name)
+	return value, true
+}
+
+func (kube *Kubernetes) selector() string {
+	return labels.Set{DomainLabel: kube.Domain, ClusterLabel: kube.Cluster}.AsSelector().String()
+}
+
+// leaseName the name of the Lease is the election key made DNS compliant.
+func (kube *Kubernetes) leaseName() string {
+	return fmt.Sprintf("nominee-%s-%s", kube.Domain, kube.Cluster)
+}
+
+func (kube *Kubernetes) electionKey() string {
+	return fmt.Sprintf("nominee/domain/%s/cluster/%s", kube.Domain, kube.Cluster)
+}
@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	Namespace     string
+	PodName       string
+	KubeConfig    string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+	Loaded        bool
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{BasicConfig: config.NewBasicConfig()}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	hostname, _ := os.Hostname()
+	config.SetDefault("NOMINEE_KUBERNETES_NAMESPACE", currentNamespace())
+	config.SetDefault("NOMINEE_KUBERNETES_POD_NAME", hostname)
+	config.SetDefault("NOMINEE_KUBERNETES_LEASE_DURATION", "15s")
+	config.SetDefault("NOMINEE_KUBERNETES_RENEW_DEADLINE", "10s")
+	config.SetDefault("NOMINEE_KUBERNETES_RETRY_PERIOD", "2s")
+
+	conf.Namespace = config.GetStringOrPanic("NOMINEE_KUBERNETES_NAMESPACE")
+	conf.PodName = config.GetStringOrPanic("NOMINEE_KUBERNETES_POD_NAME")
+	conf.KubeConfig = config.GetString("NOMINEE_KUBERNETES_KUBECONFIG")
+	conf.LeaseDuration = getDurationOrPanic("NOMINEE_KUBERNETES_LEASE_DURATION")
+	conf.RenewDeadline = getDurationOrPanic("NOMINEE_KUBERNETES_RENEW_DEADLINE")
+	conf.RetryPeriod = getDurationOrPanic("NOMINEE_KUBERNETES_RETRY_PERIOD")
+	conf.Loaded = true
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	if !conf.Loaded {
+		panic("config not loaded.")
+	}
+	return conf
+}
+
+func getDurationOrPanic(key string) time.Duration {
+	duration, err := time.ParseDuration(config.GetStringOrPanic(key))
+	if err != nil {
+		panic(err)
+	}
+	return duration
+}
+
+func currentNamespace() string {
+	if namespace, err := ioutil.ReadFile(serviceAccountNamespace); err == nil {
+		return strings.TrimSpace(string(namespace))
+	}
+	return "default"
+}
@@ -0,0 +1,65 @@
+package kubernetes_test
+
+type configurationExample struct {
+	description   string
+	cluster       string
+	domain        string
+	namespace     string
+	podName       string
+	kubeConfig    string
+	leaseDuration string
+	renewDeadline string
+	retryPeriod   string
+}
+
+var validExamples = []configurationExample{
+	{
+		description: "minimum configuration",
+		cluster:     "nominee",
+		domain:      "postgres",
+	},
+	{
+		description:   "full configuration",
+		cluster:       "cluster-002",
+		domain:        "foo",
+		namespace:     "databases",
+		podName:       "postgres-0",
+		kubeConfig:    "/home/nominee/.kube/config",
+		leaseDuration: "30s",
+		renewDeadline: "20s",
+		retryPeriod:   "5s",
+	},
+	{
+		description:   "full configuration",
+		cluster:       "cluster-003",
+		domain:        "domain-007",
+		namespace:     "postgres",
+		podName:       "postgres-2",
+		leaseDuration: "1m",
+		renewDeadline: "45s",
+		retryPeriod:   "500ms",
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "the cluster name is missing",
+		domain:      "postgres",
+	},
+	{
+		description: "the domain name is missing",
+		cluster:     "nominee",
+	},
+	{
+		description:   "the lease duration is not a duration",
+		cluster:       "nominee",
+		domain:        "postgres",
+		leaseDuration: "fifteen",
+	},
+	{
+		description: "the retry period is not a duration",
+		cluster:     "nominee",
+		domain:      "postgres",
+		retryPeriod: "2",
+	},
+}
@@ -0,0 +1,99 @@
+package kubernetes_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/kubernetes"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestKubernetesConfig_it_must_load_all_configurations(t *testing.T) {
+	hostname, _ := os.Hostname()
+	for _, example := range validExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := kubernetes.NewConfigLoader()
+			loader.Load(context.TODO())
+			kubeConfig := loader.GetSpec()
+			if kubeConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, kubeConfig.Cluster)
+			}
+
+			if kubeConfig.Domain != example.domain {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, kubeConfig.Domain)
+			}
+
+			if expected := valueOrDefault(example.namespace, "default"); kubeConfig.Namespace != expected {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Namespace, expected <%s> but actual is <%s>", testutils.Failed, expected, kubeConfig.Namespace)
+			}
+
+			if expected := valueOrDefault(example.podName, hostname); kubeConfig.PodName != expected {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.PodName, expected <%s> but actual is <%s>", testutils.Failed, expected, kubeConfig.PodName)
+			}
+
+			if kubeConfig.KubeConfig != example.kubeConfig {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.KubeConfig, expected <%s> but actual is <%s>", testutils.Failed, example.kubeConfig, kubeConfig.KubeConfig)
+			}
+
+			if expected, _ := time.ParseDuration(valueOrDefault(example.leaseDuration, "15s")); kubeConfig.LeaseDuration != expected {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.LeaseDuration, expected <%s> but actual is <%s>", testutils.Failed, expected, kubeConfig.LeaseDuration)
+			}
+
+			if expected, _ := time.ParseDuration(valueOrDefault(example.renewDeadline, "10s")); kubeConfig.RenewDeadline != expected {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.RenewDeadline, expected <%s> but actual is <%s>", testutils.Failed, expected, kubeConfig.RenewDeadline)
+			}
+
+			if expected, _ := time.ParseDuration(valueOrDefault(example.retryPeriod, "2s")); kubeConfig.RetryPeriod != expected {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.RetryPeriod, expected <%s> but actual is <%s>", testutils.Failed, expected, kubeConfig.RetryPeriod)
+			}
+		})
+	}
+}
+
+func TestKubernetesConfig_it_must_panic_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			kubeConfig := kubernetes.NewConfigLoader()
+			kubeConfig.Load(context.TODO())
+		})
+	}
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func declareConfigurationExample(example configurationExample) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_KUBERNETES_NAMESPACE", example.namespace)
+	_ = os.Setenv("NOMINEE_KUBERNETES_POD_NAME", example.podName)
+	_ = os.Setenv("NOMINEE_KUBERNETES_KUBECONFIG", example.kubeConfig)
+	_ = os.Setenv("NOMINEE_KUBERNETES_LEASE_DURATION", example.leaseDuration)
+	_ = os.Setenv("NOMINEE_KUBERNETES_RENEW_DEADLINE", example.renewDeadline)
+	_ = os.Setenv("NOMINEE_KUBERNETES_RETRY_PERIOD", example.retryPeriod)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_KUBERNETES_NAMESPACE")
+	_ = os.Unsetenv("NOMINEE_KUBERNETES_POD_NAME")
+	_ = os.Unsetenv("NOMINEE_KUBERNETES_KUBECONFIG")
+	_ = os.Unsetenv("NOMINEE_KUBERNETES_LEASE_DURATION")
+	_ = os.Unsetenv("NOMINEE_KUBERNETES_RENEW_DEADLINE")
+	_ = os.Unsetenv("NOMINEE_KUBERNETES_RETRY_PERIOD")
+}
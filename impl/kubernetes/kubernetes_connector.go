@@ -0,0 +1,55 @@
+package kubernetes
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Connector ...
+type Connector interface {
+	base.Cleaner
+	Connect(ctx context.Context, config *ConfigSpec) (kubernetes.Interface, error)
+}
+
+// DefaultConnector ...
+type DefaultConnector struct {
+	client kubernetes.Interface
+}
+
+// NewDefaultConnector ...
+func NewDefaultConnector() *DefaultConnector {
+	return &DefaultConnector{}
+}
+
+// Connect uses the in-cluster configuration unless a kubeconfig file is specified.
+func (server *DefaultConnector) Connect(_ context.Context, config *ConfigSpec) (kubernetes.Interface, error) {
+	if server.client != nil {
+		return server.client, nil
+	}
+
+	var err error
+	var cfg *rest.Config
+	if config.KubeConfig != "" {
+		log.Infof("connect to the API server. KubeConfig %s", config.KubeConfig)
+		cfg, err = clientcmd.BuildConfigFromFlags("", config.KubeConfig)
+	} else {
+		log.Infof("connect to the API server from inside the cluster.")
+		cfg, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if server.client, err = kubernetes.NewForConfig(cfg); err != nil {
+		return nil, err
+	}
+	return server.client, nil
+}
+
+// Cleanup ...
+func (server *DefaultConnector) Cleanup() {
+	server.client = nil
+}
@@ -0,0 +1,169 @@
+package kubernetes
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/node"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/retry"
+)
+
+// Elector ...
+type Elector struct {
+	*Kubernetes
+	*election.DefaultElector
+}
+
+// NewElector ...
+func NewElector(cl ConfigLoader) *Elector {
+	cl.Load(context.Background())
+	spec := cl.GetSpec()
+	log = logger.G(context.Background()).WithFields(logrus.Fields{"elector": "kubernetes", "domain": spec.Domain, "cluster": spec.Cluster})
+	elector := Elector{Kubernetes: NewKubernetes(cl)}
+	elector.failBackFn = func() error { return elector.connect(true) }
+	return &elector
+}
+
+// Run ...
+func (e *Elector) Run(n node.Node) error {
+	log = log.WithFields(logrus.Fields{"daemon": n.GetDaemonName(), "node": n.GetName(), "pod": e.PodName})
+	log.Infof("starting...")
+	e.DefaultElector = election.NewElector(n)
+
+	if err := e.connect(false); err != nil {
+		return err
+	}
+	e.listenToTheSession()
+	log.Infof("started.")
+	return nil
+}
+
+func (e *Elector) connect(reconnect bool) error {
+	if reconnect {
+		e.Reset()
+	}
+
+	if err := e.Kubernetes.connect(e.Ctx); err != nil {
+		return err
+	}
+
+	if err := e.advertise(); err != nil {
+		return err
+	}
+
+	log.Infof("new election...")
+	leaders := make(chan string)
+	elector, err := e.newLeaderElector(leaders)
+	if err != nil {
+		return err
+	}
+
+	e.campaign(elector)
+	e.observe(leaders)
+	log.Infof("session created.")
+	return nil
+}
+
+// advertise labels the pod as a member of the cluster and annotates it with the node spec.
+func (e *Elector) advertise() error {
+	pods := e.client.CoreV1().Pods(e.Namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := pods.Get(e.ctx, e.PodName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		pod.Labels[DomainLabel] = e.Domain
+		pod.Labels[ClusterLabel] = e.Cluster
+
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[SpecAnnotation] = e.Managed.GetSpec().Marshal()
+
+		_, err = pods.Update(e.ctx, pod, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func (e *Elector) newLeaderElector(leaders chan<- string) (*leaderelection.LeaderElector, error) {
+	ctx, session := e.ctx, e.session
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: e.Namespace, Name: e.leaseName()},
+		Client:     e.client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: e.PodName},
+	}
+
+	return leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		Name:            e.leaseName(),
+		LeaseDuration:   e.LeaseDuration,
+		RenewDeadline:   e.RenewDeadline,
+		RetryPeriod:     e.RetryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {},
+			OnStoppedLeading: func() {
+				e.closeSession(ctx, session)
+			},
+			OnNewLeader: func(identity string) {
+				if identity == "" { // The lease has been released
+					return
+				}
+				select {
+				case leaders <- identity:
+				case <-ctx.Done():
+				}
+			},
+		},
+	})
+}
+
+func (e *Elector) campaign(elector *leaderelection.LeaderElector) {
+	ctx := e.ctx
+	go func() {
+		log.Infof("campaign as %v...", e.Managed.GetName())
+		elector.Run(ctx)
+	}()
+}
+
+// observe serializes the leader changes reported by the leaderelection callbacks. Each callback runs in its own
+// goroutine, so the reports may come out of order: the holder of the Lease is read again rather than trusted.
+func (e *Elector) observe(leaders <-chan string) {
+	ctx := e.ctx
+	go func() {
+		current := ""
+		for {
+			select {
+			case reported := <-leaders:
+				identity, err := e.leaseHolder(ctx)
+				if err != nil {
+					log.Warnf("failed to read the holder of the lease: %v", err)
+					continue
+				}
+				if identity == "" || identity == current {
+					continue
+				}
+				if identity != reported {
+					log.Infof("%s reported as the leader but %s holds the lease.", reported, identity)
+				}
+				spec, err := e.lookupNodeSpec(ctx, identity)
+				if err != nil {
+					log.Warnf("failed to lookup the leader %s: %v", identity, err)
+					continue
+				}
+				current = identity
+				_ = e.UpdateLeader(&spec)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
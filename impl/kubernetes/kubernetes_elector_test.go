@@ -0,0 +1,218 @@
+package kubernetes_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/kubernetes"
+	kubemock "github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func TestKubernetesElector_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			election.TestElector(t, func() election.Elector {
+				connector := kubemock.NewKubernetesConnector(t, fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0")))
+				connector.StallLeases()
+				elector := kubernetes.NewElector(example.config("pod-0"))
+				elector.Connector = connector
+				return elector
+			})
+		})
+	}
+}
+
+func TestKubernetesElector_when_run_then_advertise_the_node_on_its_pod(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0"))
+			connector := kubemock.NewKubernetesConnector(t, clientset)
+			connector.StallLeases()
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = connector
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+
+			pod, _ := clientset.CoreV1().Pods(example.namespace).Get(context.TODO(), "pod-0", metav1.GetOptions{})
+			if pod.Annotations[kubernetes.SpecAnnotation] != example.nodeSpec.Marshal() {
+				t.Fatalf("\t\t%s FAIL: KubernetesElector, expected the pod annotated with <%s> but actual is <%s>", testutils.Failed, example.nodeSpec.Marshal(), pod.Annotations[kubernetes.SpecAnnotation])
+			}
+
+			if pod.Labels[kubernetes.DomainLabel] != example.domain || pod.Labels[kubernetes.ClusterLabel] != example.cluster {
+				t.Fatalf("\t\t%s FAIL: KubernetesElector, expected the pod labeled with the domain and the cluster. Actual <%v>", testutils.Failed, pod.Labels)
+			}
+		})
+	}
+}
+
+func TestKubernetesElector_when_the_pod_does_not_exist_then_fail(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = kubemock.NewKubernetesConnector(t, fake.NewSimpleClientset())
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err == nil {
+				t.Fatalf("\t\t%s FAIL: KubernetesElector, expected to fail. But actually not.", testutils.Failed)
+			}
+		})
+	}
+}
+
+func TestKubernetesElector_when_alone_then_hold_the_lease_and_lead(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0"))
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return nod.LeadHits == 1 && nod.Leader.Name == example.nodeSpec.Name
+			})
+
+			lease, err := clientset.CoordinationV1().Leases(example.namespace).Get(context.TODO(), "nominee-"+example.domain+"-"+example.cluster, metav1.GetOptions{})
+			if err != nil || *lease.Spec.HolderIdentity != "pod-0" {
+				t.Fatalf("\t\t%s FAIL: KubernetesElector, expected to hold the lease. But actually not (%v).", testutils.Failed, err)
+			}
+		})
+	}
+}
+
+func TestKubernetesElector_when_another_pod_holds_the_lease_then_follow_it(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0"), kubemock.NewPod(example.namespace, "pod-1"))
+			leader := mock.NewNode(t, &node.Spec{Name: "the-leader", Address: "10.0.0.1", Port: 5432})
+			leader.LeadFn = func(context.Context, node.Spec) error { return nil }
+			first := kubernetes.NewElector(example.config("pod-0"))
+			first.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer first.Cleanup()
+			if err := first.Run(leader); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return leader.LeadHits == 1 })
+
+			follower := mock.NewNode(t, example.nodeSpec)
+			follower.FollowFn = func(context.Context, node.Spec) error { return nil }
+			second := kubernetes.NewElector(example.config("pod-1"))
+			second.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer second.Cleanup()
+			if err := second.Run(follower); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return follower.FollowHits == 1 && follower.Leader.Name == "the-leader"
+			})
+		})
+	}
+}
+
+func TestKubernetesElector_when_the_lease_is_lost_then_campaign_again(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0"))
+			connector := kubemock.NewKubernetesConnector(t, clientset)
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = connector
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return nod.LeadHits == 1 })
+
+			// Another pod steals the lease
+			leases := clientset.CoordinationV1().Leases(example.namespace)
+			lease, _ := leases.Get(context.TODO(), "nominee-"+example.domain+"-"+example.cluster, metav1.GetOptions{})
+			thief, duration, now := "pod-9", int32(60), metav1.NewMicroTime(time.Now())
+			lease.Spec.HolderIdentity, lease.Spec.LeaseDurationSeconds, lease.Spec.RenewTime = &thief, &duration, &now
+			if _, err := leases.Update(context.TODO(), lease, metav1.UpdateOptions{}); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return connector.ConnectHits == 2 })
+		})
+	}
+}
+
+func TestKubernetesElector_when_a_stale_leader_is_reported_then_follow_the_holder_of_the_lease(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			leader, stale := node.Spec{Name: "the-leader", Address: "10.0.0.1", Port: 5432}, node.Spec{Name: "the-stale", Address: "10.0.0.2", Port: 5432}
+			pods := []*corev1.Pod{kubemock.NewPod(example.namespace, "pod-0"), kubemock.NewPod(example.namespace, "pod-1"), kubemock.NewPod(example.namespace, "pod-2")}
+			pods[1].Annotations = map[string]string{kubernetes.SpecAnnotation: leader.Marshal()}
+			pods[2].Annotations = map[string]string{kubernetes.SpecAnnotation: stale.Marshal()}
+			holder, duration, now := "pod-1", int32(60), metav1.NewMicroTime(time.Now())
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{Namespace: example.namespace, Name: "nominee-" + example.domain + "-" + example.cluster},
+				Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder, LeaseDurationSeconds: &duration, AcquireTime: &now, RenewTime: &now},
+			}
+			clientset := fake.NewSimpleClientset(pods[0], pods[1], pods[2], lease)
+
+			// The first read of the Lease is stale, as if its holder had changed in between
+			first := true
+			clientset.PrependReactor("get", "leases", func(k8stesting.Action) (bool, runtime.Object, error) {
+				if !first {
+					return false, nil, nil
+				}
+				first = false
+				staled, holder := lease.DeepCopy(), "pod-2"
+				staled.Spec.HolderIdentity = &holder
+				return true, staled, nil
+			})
+
+			followed := make(chan string, 10)
+			nod := mock.NewNode(t, example.nodeSpec)
+			// Nobody renews the Lease, so the node takes it over eventually
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+			nod.FollowFn = func(_ context.Context, spec node.Spec) error {
+				followed <- spec.Name
+				return nil
+			}
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer elector.Cleanup()
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+
+			select {
+			case name := <-followed:
+				if name != leader.Name {
+					t.Fatalf("\t\t%s FAIL: Follow, expected <%s> but actual is <%s>", testutils.Failed, leader.Name, name)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("\t\t%s FAIL: Follow, expected <%s> but actual is nothing", testutils.Failed, leader.Name)
+			}
+		})
+	}
+}
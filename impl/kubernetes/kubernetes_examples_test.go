@@ -0,0 +1,54 @@
+package kubernetes_test
+
+import (
+	"github/mlyahmed.io/nominee/impl/kubernetes"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"time"
+)
+
+type exampleSpec struct {
+	description string
+	namespace   string
+	cluster     string
+	domain      string
+	nodeSpec    *node.Spec
+}
+
+func (example exampleSpec) config(podName string) *mock.KubernetesConfigSpec {
+	return &mock.KubernetesConfigSpec{
+		ConfigSpec: &kubernetes.ConfigSpec{
+			Namespace:     example.namespace,
+			PodName:       podName,
+			LeaseDuration: 150 * time.Millisecond,
+			RenewDeadline: 100 * time.Millisecond,
+			RetryPeriod:   20 * time.Millisecond,
+			BasicConfig:   &config.BasicConfig{Cluster: example.cluster, Domain: example.domain},
+		},
+	}
+}
+
+var examples = []exampleSpec{
+	{
+		description: "one node cluster",
+		namespace:   "default",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeSpec:    &node.Spec{Name: "nominee-1", Address: "nominee-1", Port: 1245},
+	},
+	{
+		description: "three nodes cluster",
+		namespace:   "databases",
+		cluster:     "cluster-501",
+		domain:      "domain-981",
+		nodeSpec:    &node.Spec{Name: "nominee-2", Address: "nominee-2", Port: 3254},
+	},
+	{
+		description: "another domain",
+		namespace:   "postgres",
+		cluster:     "cluster-777",
+		domain:      "domain-113",
+		nodeSpec:    &node.Spec{Name: "nominee-3", Address: "nominee-3", Port: 9778},
+	},
+}
@@ -0,0 +1,197 @@
+package kubernetes
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/proxy"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Observer ...
+type Observer struct {
+	*Kubernetes
+	*election.BasicObserver
+	holder string
+}
+
+// NewObserver ...
+func NewObserver(cl ConfigLoader) *Observer {
+	cl.Load(context.Background())
+	log = logrus.WithFields(logrus.Fields{"observer": "kubernetes"})
+	o := Observer{Kubernetes: NewKubernetes(cl)}
+	o.failBackFn = o.failBack
+	return &o
+}
+
+// Observe ...
+func (observer *Observer) Observe(proxy proxy.Proxy) error {
+	observer.BasicObserver = election.NewBasicObserver(proxy)
+
+	if err := observer.subscribe(); err != nil {
+		return err
+	}
+
+	observer.listenToTheSession()
+	return observer.observe()
+}
+
+func (observer *Observer) observe() error {
+	version, err := observer.pushCurrentNodes()
+	if err != nil {
+		return err
+	}
+
+	nodes, err := observer.client.CoreV1().Pods(observer.Namespace).Watch(observer.ctx, metav1.ListOptions{LabelSelector: observer.selector(), ResourceVersion: version})
+	if err != nil {
+		return err
+	}
+
+	leases, err := observer.client.CoordinationV1().Leases(observer.Namespace).Watch(observer.ctx, metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", observer.leaseName()).String()})
+	if err != nil {
+		return err
+	}
+
+	observer.pushCurrentLeader()
+	observer.observeLeader(leases)
+	observer.observeNodes(nodes)
+	return nil
+}
+
+func (observer *Observer) observeNodes(watcher watch.Interface) {
+	ctx, session := observer.ctx, observer.session
+	go func() {
+		defer watcher.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.ResultChan():
+				if !ok { // The API server closes the watches from time to time
+					observer.closeSession(ctx, session)
+					return
+				}
+				observer.updateNode(event)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (observer *Observer) updateNode(event watch.Event) {
+	pod, ok := event.Object.(*corev1.Pod)
+	if !ok {
+		return
+	}
+
+	decoded, advertised := observer.toNodeSpec(pod)
+	if !advertised {
+		return
+	}
+
+	switch event.Type {
+	case watch.Deleted:
+		if err := observer.RemoveNodes(&decoded); err != nil {
+			panic(err)
+		}
+		log.Infof("Node deleted : %s", decoded.Marshal())
+	case watch.Added, watch.Modified:
+		if err := observer.UpdateNodes(&decoded); err != nil {
+			panic(err)
+		}
+		log.Infof("node updated : %s", decoded.Marshal())
+	}
+}
+
+// observeLeader follows the holder of the Lease. A released or expired Lease keeps the last leader
+// published until another node takes it over or the leader pod is deleted.
+func (observer *Observer) observeLeader(watcher watch.Interface) {
+	ctx, session := observer.ctx, observer.session
+	go func() {
+		defer watcher.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					observer.closeSession(ctx, session)
+					return
+				}
+				if lease, ok := event.Object.(*coordinationv1.Lease); ok && event.Type != watch.Deleted {
+					observer.updateLeader(lease)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (observer *Observer) pushCurrentLeader() {
+	lease, err := observer.client.CoordinationV1().Leases(observer.Namespace).Get(observer.ctx, observer.leaseName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		panic(err)
+	}
+	observer.updateLeader(lease)
+}
+
+func (observer *Observer) updateLeader(lease *coordinationv1.Lease) {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" || *lease.Spec.HolderIdentity == observer.holder {
+		return // Released, or just renewed
+	}
+
+	decoded, err := observer.lookupNodeSpec(observer.ctx, *lease.Spec.HolderIdentity)
+	if err != nil {
+		log.Warnf("failed to lookup the leader %s: %v", *lease.Spec.HolderIdentity, err)
+		return
+	}
+
+	observer.holder = *lease.Spec.HolderIdentity
+	if err := observer.UpdateLeader(&decoded); err != nil {
+		panic(err)
+	}
+	log.Infof("Leader updated : %s", decoded.Marshal())
+}
+
+func (observer *Observer) pushCurrentNodes() (string, error) {
+	pods, err := observer.client.CoreV1().Pods(observer.Namespace).List(observer.ctx, metav1.ListOptions{LabelSelector: observer.selector()})
+	if err != nil {
+		return "", err
+	}
+
+	nodes := make([]*node.Spec, 0, len(pods.Items))
+	for i := range pods.Items {
+		if decoded, advertised := observer.toNodeSpec(&pods.Items[i]); advertised {
+			nodes = append(nodes, &decoded)
+		}
+	}
+
+	if err := observer.UpdateNodes(nodes...); err != nil {
+		panic(err)
+	}
+	log.Infof("Current nodes updated: %v", nodes)
+	return pods.ResourceVersion, nil
+}
+
+func (observer *Observer) subscribe() error {
+	log.Infof("Subscribe to the election %s...", observer.electionKey())
+	if err := observer.connect(observer.Ctx); err != nil {
+		return err
+	}
+	log.Infof("subscribed to the Kubernetes API server.")
+	return nil
+}
+
+func (observer *Observer) failBack() error {
+	observer.Reset()
+	if err := observer.subscribe(); err != nil {
+		return err
+	}
+	return observer.observe()
+}
@@ -0,0 +1,87 @@
+package kubernetes_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/kubernetes"
+	kubemock "github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"testing"
+)
+
+func TestKubernetesObserver_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			election.TestObserver(t, func() election.Observer {
+				observer := kubernetes.NewObserver(example.config("observer-0"))
+				observer.Connector = kubemock.NewKubernetesConnector(t, fake.NewSimpleClientset())
+				return observer
+			})
+		})
+	}
+}
+
+func TestKubernetesObserver_when_a_pod_is_elected_then_publish_it_as_leader(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0"))
+			observer := kubernetes.NewObserver(example.config("observer-0"))
+			observer.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesObserver, %v", testutils.Failed, err)
+			}
+
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer elector.Cleanup()
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == example.nodeSpec.Name
+			})
+		})
+	}
+}
+
+func TestKubernetesObserver_when_the_leader_pod_is_deleted_then_remove_it(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset(kubemock.NewPod(example.namespace, "pod-0"))
+			observer := kubernetes.NewObserver(example.config("observer-0"))
+			observer.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesObserver, %v", testutils.Failed, err)
+			}
+
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+			elector := kubernetes.NewElector(example.config("pod-0"))
+			elector.Connector = kubemock.NewKubernetesConnector(t, clientset)
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return proxy.Leader != nil })
+
+			elector.Cleanup()
+			if err := clientset.CoreV1().Pods(example.namespace).Delete(context.TODO(), "pod-0", metav1.DeleteOptions{}); err != nil {
+				t.Fatalf("\t\t%s FATAL: KubernetesObserver, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader == nil && len(proxy.Followers) == 0
+			})
+		})
+	}
+}
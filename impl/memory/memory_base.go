@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/metrics"
+)
+
+// Memory ...
+type Memory struct {
+	*ConfigSpec
+	Hub        *Hub
+	session    *Session
+	failBackFn func() error
+}
+
+var (
+	log *logrus.Entry
+)
+
+// NewMemory ...
+func NewMemory(cl ConfigLoader, hub *Hub) *Memory {
+	return &Memory{
+		Hub:        hub,
+		ConfigSpec: cl.GetSpec(),
+		failBackFn: func() error { return nil },
+	}
+}
+
+// Cleanup ...
+func (memory *Memory) Cleanup() {
+	if memory.session != nil {
+		memory.Hub.Close(memory.session)
+	}
+}
+
+// Session returns the current session of the hub.
+func (memory *Memory) Session() *Session {
+	return memory.session
+}
+
+func (memory *Memory) open() {
+	memory.session = memory.Hub.Open()
+}
+
+func (memory *Memory) listenToTheSession() {
+	go func() {
+		for { //TODO: retries limit
+			select {
+			case <-memory.session.Expired():
+				log.Infof("session expired. Try to reconnect...")
+				metrics.SessionReconnected("memory")
+				_ = memory.failBackFn()
+			case <-memory.session.Closed():
+				return
+			}
+		}
+	}()
+}
+
+func (memory *Memory) electionKey() string {
+	return fmt.Sprintf("nominee/domain/%s/cluster/%s", memory.Domain, memory.Cluster)
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/config"
+)
+
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	Loaded bool
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{BasicConfig: config.NewBasicConfig()}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	conf.Loaded = true
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	if !conf.Loaded {
+		panic("config not loaded.")
+	}
+	return conf
+}
@@ -0,0 +1,31 @@
+package memory_test
+
+type configurationExample struct {
+	description string
+	cluster     string
+	domain      string
+}
+
+var validExamples = []configurationExample{
+	{
+		description: "default configuration",
+		cluster:     "nominee",
+		domain:      "postgres",
+	},
+	{
+		description: "another configuration",
+		cluster:     "cluster-002",
+		domain:      "foo",
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "the cluster name is missing",
+		domain:      "postgres",
+	},
+	{
+		description: "the domain name is missing",
+		cluster:     "nominee",
+	},
+}
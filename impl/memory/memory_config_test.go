@@ -0,0 +1,54 @@
+package memory_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/memory"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"testing"
+)
+
+func TestMemoryConfig_it_must_load_all_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := memory.NewConfigLoader()
+			loader.Load(context.TODO())
+			memoryConfig := loader.GetSpec()
+			if memoryConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, memoryConfig.Cluster)
+			}
+
+			if memoryConfig.Domain != example.domain {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, memoryConfig.Domain)
+			}
+		})
+	}
+}
+
+func TestMemoryConfig_it_must_panic_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			memoryConfig := memory.NewConfigLoader()
+			memoryConfig.Load(context.TODO())
+		})
+	}
+}
+
+func declareConfigurationExample(example configurationExample) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+}
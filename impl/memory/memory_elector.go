@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/node"
+)
+
+// Elector ...
+type Elector struct {
+	*Memory
+	*election.DefaultElector
+}
+
+// NewElector ...
+func NewElector(cl ConfigLoader, hub *Hub) *Elector {
+	cl.Load(context.Background())
+	spec := cl.GetSpec()
+	log = logger.G(context.Background()).WithFields(logrus.Fields{"elector": "memory", "domain": spec.Domain, "cluster": spec.Cluster})
+	elector := Elector{Memory: NewMemory(cl, hub)}
+	elector.failBackFn = func() error { return elector.connect(true) }
+	return &elector
+}
+
+// Run ...
+func (e *Elector) Run(n node.Node) error {
+	log = log.WithFields(logrus.Fields{"daemon": n.GetDaemonName(), "node": n.GetName()})
+	log.Infof("starting...")
+	e.DefaultElector = election.NewElector(n)
+
+	if err := e.connect(false); err != nil {
+		return err
+	}
+	e.listenToTheSession()
+	log.Infof("started.")
+	return nil
+}
+
+// Resign gives up the leadership, or the candidacy, of the node. The next candidate takes the lead.
+func (e *Elector) Resign() {
+	e.Hub.Resign(e.session, e.electionKey())
+}
+
+func (e *Elector) connect(reconnect bool) error {
+	if reconnect {
+		e.Reset()
+	}
+
+	e.open()
+	e.campaign()
+	e.observe()
+	log.Infof("session created.")
+	return nil
+}
+
+func (e *Elector) campaign() {
+	ctx, session, spec := e.Ctx, e.session, *e.Managed.GetSpec()
+	go func() {
+		log.Infof("campaign as %v...", e.Managed.GetName())
+		if err := e.Hub.Campaign(ctx, session, e.electionKey(), spec); err != nil && ctx.Err() == nil {
+			log.Infof("campaign stopped: %v", err)
+		}
+	}()
+}
+
+func (e *Elector) observe() {
+	ctx := e.Ctx
+	go func() {
+		var current *node.Spec
+		for state := range e.Hub.Watch(ctx, e.electionKey()) {
+			if state.Leader == nil || (current != nil && *current == *state.Leader) {
+				continue
+			}
+			current = state.Leader
+			_ = e.UpdateLeader(current)
+		}
+	}()
+}
@@ -0,0 +1,193 @@
+package memory_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/memory"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func TestMemoryElector_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			hub.Stall()
+			election.TestElector(t, func() election.Elector {
+				return memory.NewElector(example.config(), hub)
+			})
+		})
+	}
+}
+
+func TestMemoryElector_when_run_then_open_a_session(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			hub.Stall()
+			elector := memory.NewElector(example.config(), hub)
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+
+			if len(hub.Sessions()) != 1 {
+				t.Fatalf("\t\t%s FAIL: MemoryElector, expected to open a session. But actually not.", testutils.Failed)
+			}
+		})
+	}
+}
+
+func TestMemoryElector_when_alone_then_promote_the_node(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			elector := memory.NewElector(example.config(), hub)
+			defer elector.Cleanup()
+			nod := newNode(t, example.nodeSpec)
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return nod.leader().Name == example.nodeSpec.Name
+			})
+
+			if !strings.HasPrefix(nod.leader().ElectionKey, example.electionKey()+"/") {
+				t.Fatalf("\t\t%s FAIL: MemoryElector, expected a key under <%s> but actual is <%s>", testutils.Failed, example.electionKey(), nod.leader().ElectionKey)
+			}
+		})
+	}
+}
+
+func TestMemoryElector_when_another_node_campaigned_first_then_follow_it(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			first := memory.NewElector(example.config(), hub)
+			defer first.Cleanup()
+			firstNode := newNode(t, &node.Spec{Name: "first"})
+			if err := first.Run(firstNode); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return firstNode.leader().Name == "first" })
+
+			second := memory.NewElector(example.config(), hub)
+			defer second.Cleanup()
+			secondNode := newNode(t, example.nodeSpec)
+			if err := second.Run(secondNode); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return secondNode.followed().Name == "first"
+			})
+		})
+	}
+}
+
+func TestMemoryElector_when_the_leader_resigns_then_the_next_candidate_leads(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			first := memory.NewElector(example.config(), hub)
+			defer first.Cleanup()
+			firstNode := newNode(t, &node.Spec{Name: "first"})
+			if err := first.Run(firstNode); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return firstNode.leader().Name == "first" })
+
+			second := memory.NewElector(example.config(), hub)
+			defer second.Cleanup()
+			secondNode := newNode(t, example.nodeSpec)
+			if err := second.Run(secondNode); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return secondNode.followed().Name == "first" })
+
+			first.Resign()
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return secondNode.leader().Name == example.nodeSpec.Name
+			})
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return firstNode.followed().Name == example.nodeSpec.Name
+			})
+			testutils.AsyncAssertion.ItMustKeepRunning(t, first.Done())
+		})
+	}
+}
+
+func TestMemoryElector_when_the_session_expires_then_reconnect(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			hub.Stall()
+			elector := memory.NewElector(example.config(), hub)
+			defer elector.Cleanup()
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+			expired := hub.Sessions()[0]
+
+			hub.Expire(expired)
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				sessions := hub.Sessions()
+				return len(sessions) == 1 && sessions[0] != expired
+			})
+		})
+	}
+}
+
+// recordingNode records the leader it is asked to lead or to follow.
+type recordingNode struct {
+	*mock.Node
+	mutex  *sync.Mutex
+	leads  node.Spec
+	follow node.Spec
+}
+
+func newNode(t *testing.T, spec *node.Spec) *recordingNode {
+	nod := &recordingNode{Node: mock.NewNode(t, spec), mutex: &sync.Mutex{}}
+	nod.LeadFn = func(_ context.Context, leader node.Spec) error {
+		nod.mutex.Lock()
+		defer nod.mutex.Unlock()
+		nod.leads = leader
+		return nil
+	}
+	nod.FollowFn = func(_ context.Context, leader node.Spec) error {
+		nod.mutex.Lock()
+		defer nod.mutex.Unlock()
+		nod.follow = leader
+		return nil
+	}
+	nod.DemoteFn = func(context.Context) error { return nil }
+	nod.StonithFn = func(context.Context) {}
+	return nod
+}
+
+func (nod *recordingNode) leader() node.Spec {
+	nod.mutex.Lock()
+	defer nod.mutex.Unlock()
+	return nod.leads
+}
+
+func (nod *recordingNode) followed() node.Spec {
+	nod.mutex.Lock()
+	defer nod.mutex.Unlock()
+	return nod.follow
+}
@@ -0,0 +1,49 @@
+package memory_test
+
+import (
+	"fmt"
+	"github/mlyahmed.io/nominee/impl/memory"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+)
+
+type exampleSpec struct {
+	description string
+	cluster     string
+	domain      string
+	nodeSpec    *node.Spec
+}
+
+func (example exampleSpec) config() *mock.MemoryConfigSpec {
+	return &mock.MemoryConfigSpec{
+		ConfigSpec: &memory.ConfigSpec{
+			BasicConfig: &config.BasicConfig{Cluster: example.cluster, Domain: example.domain},
+		},
+	}
+}
+
+func (example exampleSpec) electionKey() string {
+	return fmt.Sprintf("nominee/domain/%s/cluster/%s", example.domain, example.cluster)
+}
+
+var examples = []exampleSpec{
+	{
+		description: "one node cluster",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeSpec:    &node.Spec{Name: "nominee-1", Address: "nominee-1", Port: 1245},
+	},
+	{
+		description: "three nodes cluster",
+		cluster:     "cluster-501",
+		domain:      "domain-981",
+		nodeSpec:    &node.Spec{Name: "nominee-2", Address: "nominee-2", Port: 3254},
+	},
+	{
+		description: "another domain",
+		cluster:     "cluster-777",
+		domain:      "domain-113",
+		nodeSpec:    &node.Spec{Name: "nominee-3", Address: "nominee-3", Port: 9778},
+	},
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Hub is an in-process election server shared by the electors and the observers of the same process.
+// It follows the etcd semantics: each elector opens a session and campaigns by putting its own key
+// under the election key, the oldest key of a living session is the leader.
+type Hub struct {
+	mutex    *sync.Mutex
+	revision int64
+	stalled  bool
+	changed  chan struct{}
+	sessions map[string]*Session
+	keys     map[string]*campaignKey
+}
+
+// Session ...
+type Session struct {
+	ID      string
+	expired chan struct{}
+	closed  chan struct{}
+}
+
+// State is the view of an election at a given revision.
+type State struct {
+	Revision int64
+	Leader   *node.Spec
+	Nodes    map[string]node.Spec
+}
+
+type campaignKey struct {
+	session  string
+	revision int64
+	spec     node.Spec
+}
+
+// DefaultHub is the hub of the electors and the observers that are not given one.
+var DefaultHub = NewHub()
+
+// NewHub ...
+func NewHub() *Hub {
+	return &Hub{
+		mutex:    &sync.Mutex{},
+		changed:  make(chan struct{}),
+		sessions: make(map[string]*Session),
+		keys:     make(map[string]*campaignKey),
+	}
+}
+
+// Stall stops electing leaders, the campaigns are recorded but none of them wins
+// until Unstall. So the leadership can be driven by hand.
+func (hub *Hub) Stall() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.stalled = true
+	hub.revision++
+	hub.notify()
+}
+
+// Unstall ...
+func (hub *Hub) Unstall() {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	hub.stalled = false
+	hub.revision++
+	hub.notify()
+}
+
+// Sessions returns the IDs of the living sessions.
+func (hub *Hub) Sessions() []string {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	sessions := make([]string, 0, len(hub.sessions))
+	for id := range hub.sessions {
+		sessions = append(sessions, id)
+	}
+	sort.Strings(sessions)
+	return sessions
+}
+
+// Expire invalidates the session as if its TTL was reached. Its keys are deleted and its owner is notified.
+func (hub *Hub) Expire(id string) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if session, ok := hub.sessions[id]; ok {
+		close(session.expired)
+		hub.remove(session)
+	}
+}
+
+// Open ...
+func (hub *Hub) Open() *Session {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	session := &Session{ID: uuid.New().String(), expired: make(chan struct{}), closed: make(chan struct{})}
+	hub.sessions[session.ID] = session
+	return session
+}
+
+// Close releases the session on purpose, its owner is not notified.
+func (hub *Hub) Close(session *Session) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if _, ok := hub.sessions[session.ID]; ok {
+		close(session.closed)
+		hub.remove(session)
+	}
+}
+
+// Campaign puts the spec as eligible under the election key and blocks until it is elected.
+func (hub *Hub) Campaign(ctx context.Context, session *Session, electionKey string, spec node.Spec) error {
+	key := fmt.Sprintf("%s/%s", electionKey, session.ID)
+
+	hub.mutex.Lock()
+	if _, ok := hub.sessions[session.ID]; !ok {
+		hub.mutex.Unlock()
+		return errors.Errorf("invalid session %s", session.ID)
+	}
+	hub.revision++
+	spec.ElectionKey = key
+	hub.keys[key] = &campaignKey{session: session.ID, revision: hub.revision, spec: spec}
+	hub.notify()
+	hub.mutex.Unlock()
+
+	for {
+		state, changed := hub.State(electionKey)
+		if state.Leader != nil && state.Leader.ElectionKey == key {
+			return nil
+		}
+		if _, ok := state.Nodes[key]; !ok {
+			return errors.Errorf("the key %s has been deleted", key)
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Resign deletes the key of the session under the election key, the next candidate becomes the leader.
+func (hub *Hub) Resign(session *Session, electionKey string) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	key := fmt.Sprintf("%s/%s", electionKey, session.ID)
+	if _, ok := hub.keys[key]; ok {
+		delete(hub.keys, key)
+		hub.revision++
+		hub.notify()
+	}
+}
+
+// State returns the current state of the election and a channel closed on the next change of the hub.
+func (hub *Hub) State(electionKey string) (State, <-chan struct{}) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+
+	state := State{Revision: hub.revision, Nodes: make(map[string]node.Spec)}
+	var leader *campaignKey
+	for key, value := range hub.keys {
+		if !strings.HasPrefix(key, electionKey+"/") {
+			continue
+		}
+		state.Nodes[key] = value.spec
+		if leader == nil || value.revision < leader.revision {
+			leader = value
+		}
+	}
+
+	if leader != nil && !hub.stalled {
+		spec := leader.spec
+		state.Leader = &spec
+	}
+	return state, hub.changed
+}
+
+// Watch sends the state of the election each time it changes, until the context is done.
+func (hub *Hub) Watch(ctx context.Context, electionKey string) <-chan State {
+	watch := make(chan State)
+	go func() {
+		defer close(watch)
+		var revision int64 = -1
+		for {
+			state, changed := hub.State(electionKey)
+			if state.Revision != revision {
+				revision = state.Revision
+				select {
+				case watch <- state:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return watch
+}
+
+// Expired ...
+func (session *Session) Expired() <-chan struct{} {
+	return session.expired
+}
+
+// Closed ...
+func (session *Session) Closed() <-chan struct{} {
+	return session.closed
+}
+
+func (hub *Hub) remove(session *Session) {
+	delete(hub.sessions, session.ID)
+	for key, value := range hub.keys {
+		if value.session == session.ID {
+			delete(hub.keys, key)
+		}
+	}
+	hub.revision++
+	hub.notify()
+}
+
+func (hub *Hub) notify() {
+	close(hub.changed)
+	hub.changed = make(chan struct{})
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/proxy"
+)
+
+// Observer ...
+type Observer struct {
+	*Memory
+	*election.BasicObserver
+}
+
+// NewObserver ...
+func NewObserver(cl ConfigLoader, hub *Hub) *Observer {
+	cl.Load(context.Background())
+	log = logrus.WithFields(logrus.Fields{"observer": "memory"})
+	o := Observer{Memory: NewMemory(cl, hub)}
+	o.failBackFn = o.failBack
+	return &o
+}
+
+// Observe ...
+func (observer *Observer) Observe(proxy proxy.Proxy) error {
+	observer.BasicObserver = election.NewBasicObserver(proxy)
+	observer.open()
+	observer.listenToTheSession()
+	observer.observe()
+	return nil
+}
+
+// observe turns the successive states of the election into UpdateLeader/UpdateNodes/RemoveNodes calls.
+func (observer *Observer) observe() {
+	ctx := observer.Ctx
+	go func() {
+		var leader *node.Spec
+		known := make(map[string]node.Spec)
+		for state := range observer.Hub.Watch(ctx, observer.electionKey()) {
+			for key, spec := range known {
+				if _, ok := state.Nodes[key]; !ok {
+					spec := spec
+					if err := observer.RemoveNodes(&spec); err != nil {
+						panic(err)
+					}
+					log.Infof("Node deleted : %s", spec.Marshal())
+				}
+			}
+
+			for key, spec := range state.Nodes {
+				if previous, ok := known[key]; ok && previous == spec {
+					continue
+				}
+				if state.Leader != nil && state.Leader.ElectionKey == key {
+					continue
+				}
+				spec := spec
+				if err := observer.UpdateNodes(&spec); err != nil {
+					panic(err)
+				}
+				log.Infof("node updated : %s", spec.Marshal())
+			}
+			known = state.Nodes
+
+			if state.Leader != nil && (leader == nil || *leader != *state.Leader) {
+				leader = state.Leader
+				if err := observer.UpdateLeader(leader); err != nil {
+					panic(err)
+				}
+				log.Infof("Leader updated : %s", leader.Marshal())
+			}
+		}
+	}()
+}
+
+func (observer *Observer) failBack() error {
+	observer.Reset()
+	observer.open()
+	observer.observe()
+	return nil
+}
@@ -0,0 +1,119 @@
+package memory_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/memory"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/runner"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"testing"
+)
+
+func TestMemoryObserver_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			election.TestObserver(t, func() election.Observer {
+				return memory.NewObserver(example.config(), hub)
+			})
+		})
+	}
+}
+
+func TestMemoryObserver_when_a_node_is_elected_then_publish_it_as_leader(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			observer := memory.NewObserver(example.config(), hub)
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryObserver, %v", testutils.Failed, err)
+			}
+
+			elector := memory.NewElector(example.config(), hub)
+			defer elector.Cleanup()
+			if err := elector.Run(newNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == example.nodeSpec.Name
+			})
+		})
+	}
+}
+
+func TestMemoryObserver_when_the_leader_leaves_then_remove_it(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			observer := memory.NewObserver(example.config(), hub)
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryObserver, %v", testutils.Failed, err)
+			}
+
+			elector := memory.NewElector(example.config(), hub)
+			if err := elector.Run(newNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+			}
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return proxy.Leader != nil })
+
+			elector.Cleanup()
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool { return proxy.Leader == nil })
+		})
+	}
+}
+
+func TestMemoryObserver_must_publish_the_other_candidates_as_followers(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			hub := memory.NewHub()
+			observer := memory.NewObserver(example.config(), hub)
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: MemoryObserver, %v", testutils.Failed, err)
+			}
+
+			for _, name := range []string{"first", "second", "third"} {
+				elector := memory.NewElector(example.config(), hub)
+				defer elector.Cleanup()
+				if err := elector.Run(newNode(t, &node.Spec{Name: name})); err != nil {
+					t.Fatalf("\t\t%s FATAL: MemoryElector, %v", testutils.Failed, err)
+				}
+			}
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == "first" && len(proxy.Followers) == 2
+			})
+		})
+	}
+}
+
+func TestMemoryObserver_must_run_along_the_elector_in_the_same_process(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			hub := memory.NewHub()
+			proxy := mock.NewProxy()
+
+			go func() {
+				_ = runner.NewElectorRunner().Run(ctx, memory.NewElector(example.config(), hub), newNode(t, example.nodeSpec))
+			}()
+			go func() {
+				_ = runner.NewObserverRunner().Run(ctx, memory.NewObserver(example.config(), hub), proxy)
+			}()
+
+			testutils.AsyncAssertion.ItMustBeTrue(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == example.nodeSpec.Name
+			})
+		})
+	}
+}
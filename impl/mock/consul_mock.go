@@ -0,0 +1,269 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/hashicorp/consul/api"
+	"github/mlyahmed.io/nominee/impl/consul"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ConsulConfigSpec mock the consul.ConfigSpec.Load function
+type ConsulConfigSpec struct {
+	*consul.ConfigSpec
+}
+
+// ConsulServer is an in-process fake of the Consul HTTP API.
+// It serves only the session and KV endpoints, blocking queries included.
+type ConsulServer struct {
+	*httptest.Server
+	mutex    *sync.Mutex
+	index    uint64
+	changed  chan struct{}
+	closed   chan struct{}
+	stalled  bool
+	sessions map[string]*api.SessionEntry
+	pairs    map[string]*api.KVPair
+}
+
+// NewConsulServer ...
+func NewConsulServer(_ *testing.T) *ConsulServer {
+	server := &ConsulServer{
+		mutex:    &sync.Mutex{},
+		index:    1,
+		changed:  make(chan struct{}),
+		closed:   make(chan struct{}),
+		sessions: make(map[string]*api.SessionEntry),
+		pairs:    make(map[string]*api.KVPair),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/session/create", server.createSession)
+	mux.HandleFunc("/v1/session/renew/", server.renewSession)
+	mux.HandleFunc("/v1/session/destroy/", server.destroySession)
+	mux.HandleFunc("/v1/kv/", server.kv)
+	server.Server = httptest.NewServer(mux)
+	return server
+}
+
+// Load ...
+func (conf *ConsulConfigSpec) Load(_ context.Context) {
+	conf.Loaded = true
+}
+
+// Close releases the pending blocking queries before shutting down the server.
+func (server *ConsulServer) Close() {
+	close(server.closed)
+	server.Server.Close()
+}
+
+// Stall makes every KV read hang until the client gives up. So the election never settles by itself
+// and the leadership can be driven by hand.
+func (server *ConsulServer) Stall() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.stalled = true
+}
+
+// Sessions returns the IDs of the living sessions.
+func (server *ConsulServer) Sessions() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	sessions := make([]string, 0, len(server.sessions))
+	for id := range server.sessions {
+		sessions = append(sessions, id)
+	}
+	return sessions
+}
+
+// ExpireSession invalidates the session as if its TTL was reached.
+func (server *ConsulServer) ExpireSession(id string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.invalidate(id)
+}
+
+// Pairs returns the pairs under the prefix ordered by creation.
+func (server *ConsulServer) Pairs(prefix string) []*api.KVPair {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.list(prefix, true)
+}
+
+func (server *ConsulServer) createSession(w http.ResponseWriter, r *http.Request) {
+	entry := &api.SessionEntry{}
+	if err := json.NewDecoder(r.Body).Decode(entry); err != nil && r.ContentLength > 0 {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	entry.ID = uuid.New().String()
+	server.sessions[entry.ID] = entry
+	server.reply(w, map[string]string{"ID": entry.ID})
+}
+
+func (server *ConsulServer) renewSession(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	entry, ok := server.sessions[strings.TrimPrefix(r.URL.Path, "/v1/session/renew/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	server.reply(w, []*api.SessionEntry{entry})
+}
+
+func (server *ConsulServer) destroySession(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.invalidate(strings.TrimPrefix(r.URL.Path, "/v1/session/destroy/"))
+	server.reply(w, true)
+}
+
+func (server *ConsulServer) kv(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	switch r.Method {
+	case http.MethodGet:
+		server.get(w, r, key)
+	case http.MethodPut:
+		server.put(w, r, key)
+	case http.MethodDelete:
+		server.delete(w, r, key)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (server *ConsulServer) get(w http.ResponseWriter, r *http.Request, key string) {
+	index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+
+	server.mutex.Lock()
+	for server.stalled || (index > 0 && index >= server.index) {
+		changed := server.changed
+		server.mutex.Unlock()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		case <-server.closed:
+			http.Error(w, "server closed", http.StatusServiceUnavailable)
+			return
+		}
+		server.mutex.Lock()
+	}
+	defer server.mutex.Unlock()
+
+	_, recurse := r.URL.Query()["recurse"]
+	pairs := server.list(key, recurse)
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(server.index, 10))
+	w.Header().Set("X-Consul-LastContact", "0")
+	w.Header().Set("X-Consul-KnownLeader", "true")
+	if len(pairs) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	server.reply(w, pairs)
+}
+
+func (server *ConsulServer) put(w http.ResponseWriter, r *http.Request, key string) {
+	value, _ := ioutil.ReadAll(r.Body)
+	session := r.URL.Query().Get("acquire")
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	pair, exists := server.pairs[key]
+	if session != "" {
+		if _, ok := server.sessions[session]; !ok {
+			http.Error(w, fmt.Sprintf("invalid session %q", session), http.StatusInternalServerError)
+			return
+		}
+		if exists && pair.Session != "" && pair.Session != session {
+			server.reply(w, false)
+			return
+		}
+	}
+
+	server.index++
+	if !exists {
+		pair = &api.KVPair{Key: key, CreateIndex: server.index}
+		server.pairs[key] = pair
+	}
+	pair.Value = value
+	pair.ModifyIndex = server.index
+	if session != "" && pair.Session != session {
+		pair.Session = session
+		pair.LockIndex++
+	}
+	server.notify()
+	server.reply(w, true)
+}
+
+func (server *ConsulServer) delete(w http.ResponseWriter, r *http.Request, key string) {
+	_, recurse := r.URL.Query()["recurse"]
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for _, pair := range server.list(key, recurse) {
+		delete(server.pairs, pair.Key)
+	}
+	server.index++
+	server.notify()
+	server.reply(w, true)
+}
+
+func (server *ConsulServer) invalidate(id string) {
+	entry, ok := server.sessions[id]
+	if !ok {
+		return
+	}
+	delete(server.sessions, id)
+
+	server.index++
+	for key, pair := range server.pairs {
+		if pair.Session != id {
+			continue
+		}
+		if entry.Behavior == api.SessionBehaviorDelete {
+			delete(server.pairs, key)
+		} else {
+			pair.Session = ""
+			pair.ModifyIndex = server.index
+		}
+	}
+	server.notify()
+}
+
+func (server *ConsulServer) list(key string, recurse bool) []*api.KVPair {
+	pairs := make([]*api.KVPair, 0)
+	for k, pair := range server.pairs {
+		if k == key || (recurse && strings.HasPrefix(k, key)) {
+			copied := *pair
+			pairs = append(pairs, &copied)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].CreateIndex < pairs[j].CreateIndex
+	})
+	return pairs
+}
+
+func (server *ConsulServer) notify() {
+	close(server.changed)
+	server.changed = make(chan struct{})
+}
+
+func (server *ConsulServer) reply(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,15 @@
+package mock
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/envoy"
+)
+
+// EnvoyConfigSpec mock the envoy.ConfigSpec.Load function
+type EnvoyConfigSpec struct {
+	*envoy.ConfigSpec
+}
+
+// Load ...
+func (conf *EnvoyConfigSpec) Load(_ context.Context) {
+}
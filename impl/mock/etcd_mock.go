@@ -34,6 +34,8 @@ type Connector struct {
 	ConnectFn        func(context.Context, *etcd.ConfigSpec) (etcd.Client, error)
 	NewElectionFn    func(context.Context, string) (etcd.Election, error)
 	ResumeElectionFn func(context.Context, string, clientv3.GetResponse) (etcd.Election, error)
+	LeaseFn          func() clientv3.LeaseID
+	LastKeepAliveFn  func() (clientv3.LeaseID, time.Time)
 	StopFn           func() base.DoneChan
 	CleanupFn        func()
 }
@@ -43,11 +45,24 @@ type Client struct {
 	WatchCan clientv3.WatchChan
 	WatchFn  func(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
 	GetFn    func(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	TTLFn    func(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error)
+	PutFn    func(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error)
+	DeleteFn func(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error)
+	TxnFn    func(ctx context.Context) clientv3.Txn
+}
+
+// Txn records the comparisons and the operations of a transaction, CommitFn tells how it ends.
+type Txn struct {
+	Cmps     []clientv3.Cmp
+	Success  []clientv3.Op
+	Failure  []clientv3.Op
+	CommitFn func(txn *Txn) (*clientv3.TxnResponse, error)
 }
 
 type ElectionRecord struct {
 	CampaignHits  int
 	ObserveHits   int
+	ResignHits    int
 	ElectionKey   string
 	CampaignValue string
 	Leader        clientv3.GetResponse
@@ -59,6 +74,7 @@ type Election struct {
 	leaderChan chan clientv3.GetResponse
 	CampaignFn func(ctx context.Context, val string) error
 	ObserveFn  func(ctx context.Context) <-chan clientv3.GetResponse
+	ResignFn   func(ctx context.Context) error
 }
 
 // NewConnector ...
@@ -77,6 +93,14 @@ func NewConnector(_ *testing.T) *Connector {
 		return mock.Election, nil
 	}
 
+	mock.LeaseFn = func() clientv3.LeaseID {
+		return 1
+	}
+
+	mock.LastKeepAliveFn = func() (clientv3.LeaseID, time.Time) {
+		return mock.LeaseFn(), time.Now()
+	}
+
 	mock.StopFn = func() base.DoneChan {
 		return mock.stopChan
 	}
@@ -97,6 +121,20 @@ func NewClient() *Client {
 		GetFn: func(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
 			return &clientv3.GetResponse{}, nil
 		},
+		TTLFn: func(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+			return &clientv3.LeaseTimeToLiveResponse{ID: id, TTL: -1}, nil
+		},
+		PutFn: func(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+			return &clientv3.PutResponse{}, nil
+		},
+		DeleteFn: func(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+			return &clientv3.DeleteResponse{}, nil
+		},
+		TxnFn: func(ctx context.Context) clientv3.Txn {
+			return &Txn{CommitFn: func(*Txn) (*clientv3.TxnResponse, error) {
+				return &clientv3.TxnResponse{Succeeded: true}, nil
+			}}
+		},
 	}
 	client.WatchCan = watch
 	return &client
@@ -115,6 +153,9 @@ func NewElection() *Election {
 		ObserveFn: func(ctx context.Context) <-chan clientv3.GetResponse {
 			return leaderChan
 		},
+		ResignFn: func(ctx context.Context) error {
+			return nil
+		},
 	}
 }
 
@@ -148,6 +189,16 @@ func (mock *Connector) ResumeElection(ctx context.Context, electionKey string, l
 	return mock.ResumeElectionFn(ctx, electionKey, leader)
 }
 
+// Lease ...
+func (mock *Connector) Lease() clientv3.LeaseID {
+	return mock.LeaseFn()
+}
+
+// LastKeepAlive ...
+func (mock *Connector) LastKeepAlive() (clientv3.LeaseID, time.Time) {
+	return mock.LastKeepAliveFn()
+}
+
 // Stop ...
 func (mock *Connector) Stop() base.DoneChan {
 	mock.StopHits++
@@ -176,6 +227,49 @@ func (mock *Client) Get(ctx context.Context, key string, opts ...clientv3.OpOpti
 	return mock.GetFn(ctx, key, opts...)
 }
 
+// TimeToLive ...
+func (mock *Client) TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+	return mock.TTLFn(ctx, id, opts...)
+}
+
+// Put ...
+func (mock *Client) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	return mock.PutFn(ctx, key, val, opts...)
+}
+
+// Delete ...
+func (mock *Client) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	return mock.DeleteFn(ctx, key, opts...)
+}
+
+// Txn ...
+func (mock *Client) Txn(ctx context.Context) clientv3.Txn {
+	return mock.TxnFn(ctx)
+}
+
+// If ...
+func (txn *Txn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	txn.Cmps = append(txn.Cmps, cs...)
+	return txn
+}
+
+// Then ...
+func (txn *Txn) Then(ops ...clientv3.Op) clientv3.Txn {
+	txn.Success = append(txn.Success, ops...)
+	return txn
+}
+
+// Else ...
+func (txn *Txn) Else(ops ...clientv3.Op) clientv3.Txn {
+	txn.Failure = append(txn.Failure, ops...)
+	return txn
+}
+
+// Commit ...
+func (txn *Txn) Commit() (*clientv3.TxnResponse, error) {
+	return txn.CommitFn(txn)
+}
+
 // Campaign ...
 func (mock *Election) Campaign(ctx context.Context, val string) error {
 	mock.CampaignHits++
@@ -193,6 +287,12 @@ func (mock *Election) Observe(ctx context.Context) <-chan clientv3.GetResponse {
 	return mock.ObserveFn(ctx)
 }
 
+// Resign ...
+func (mock *Election) Resign(ctx context.Context) error {
+	mock.ResignHits++
+	return mock.ResignFn(ctx)
+}
+
 func (mock *Election) PushLeader(leader clientv3.GetResponse) {
 	mock.leaderChan <- leader
 	time.Sleep(10 * time.Millisecond)
@@ -0,0 +1,15 @@
+package mock
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/exec"
+)
+
+// ExecConfigSpec mock the exec.ConfigSpec.Load function
+type ExecConfigSpec struct {
+	*exec.ConfigSpec
+}
+
+// Load ...
+func (conf *ExecConfigSpec) Load(_ context.Context) {
+}
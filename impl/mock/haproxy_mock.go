@@ -0,0 +1,192 @@
+package mock
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/impl/haproxy"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// HAProxyConfigSpec mock the haproxy.ConfigSpec.Load function
+type HAProxyConfigSpec struct {
+	*haproxy.ConfigSpec
+}
+
+// HAProxyServer is a server as seen by the Runtime API.
+type HAProxyServer struct {
+	Name    string
+	Address string
+	Port    int64
+	Ready   bool
+}
+
+// HAProxySocket is a tiny in-process stats socket. It keeps the servers of the backends, answers the
+// `show servers state` and `set server` commands from them and records the commands with the reply function.
+type HAProxySocket struct {
+	net.Listener
+	Path     string
+	mutex    *sync.Mutex
+	commands []string
+	servers  map[string][]*HAProxyServer
+	replyFn  func(command string) string
+}
+
+// Load ...
+func (conf *HAProxyConfigSpec) Load(_ context.Context) {
+}
+
+// NewHAProxySocket ...
+func NewHAProxySocket(t *testing.T) *HAProxySocket {
+	path := filepath.Join(t.TempDir(), "haproxy.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	socket := &HAProxySocket{Listener: listener, Path: path, mutex: &sync.Mutex{}, servers: make(map[string][]*HAProxyServer)}
+	socket.replyFn = socket.Answer
+	go socket.serve()
+	return socket
+}
+
+// AddServer declares a server in the backend, the way a server-template slot would be.
+func (socket *HAProxySocket) AddServer(backend string, server HAProxyServer) {
+	socket.mutex.Lock()
+	defer socket.mutex.Unlock()
+	socket.servers[backend] = append(socket.servers[backend], &server)
+}
+
+// Servers returns a copy of the servers of the backend.
+func (socket *HAProxySocket) Servers(backend string) []HAProxyServer {
+	socket.mutex.Lock()
+	defer socket.mutex.Unlock()
+	servers := make([]HAProxyServer, 0, len(socket.servers[backend]))
+	for _, server := range socket.servers[backend] {
+		servers = append(servers, *server)
+	}
+	return servers
+}
+
+// Reply replaces the function that answers the commands, it must return the raw output of the command.
+func (socket *HAProxySocket) Reply(fn func(command string) string) {
+	socket.mutex.Lock()
+	defer socket.mutex.Unlock()
+	socket.replyFn = fn
+}
+
+// Commands returns the commands received so far, the `show` ones excluded.
+func (socket *HAProxySocket) Commands() []string {
+	socket.mutex.Lock()
+	defer socket.mutex.Unlock()
+	commands := make([]string, 0, len(socket.commands))
+	for _, command := range socket.commands {
+		if !strings.HasPrefix(command, "show ") {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// Answer is the default reply function, it serves and updates the servers of the backends.
+func (socket *HAProxySocket) Answer(command string) string {
+	socket.mutex.Lock()
+	defer socket.mutex.Unlock()
+
+	fields := strings.Fields(command)
+	switch {
+	case strings.HasPrefix(command, "show servers state "):
+		return socket.showServersState(fields[3])
+	case strings.HasPrefix(command, "set server ") && len(fields) >= 5:
+		server := socket.lookup(fields[2])
+		if server == nil {
+			return "[3]: No such server.\n"
+		}
+		switch fields[3] {
+		case "addr":
+			server.Address = fields[4]
+			if len(fields) == 7 && fields[5] == "port" {
+				server.Port, _ = strconv.ParseInt(fields[6], 10, 64)
+			}
+		case "state":
+			server.Ready = fields[4] == "ready"
+		}
+		return ""
+	case strings.HasPrefix(command, "show "):
+		return ""
+	}
+	return "[3]: Unknown command.\n"
+}
+
+func (socket *HAProxySocket) lookup(path string) *HAProxyServer {
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+	for _, server := range socket.servers[parts[0]] {
+		if server.Name == parts[1] {
+			return server
+		}
+	}
+	return nil
+}
+
+func (socket *HAProxySocket) showServersState(backend string) string {
+	servers, ok := socket.servers[backend]
+	if !ok {
+		return "[3]: Can't find backend.\n"
+	}
+
+	var state strings.Builder
+	state.WriteString("1\n# be_id be_name srv_id srv_name srv_addr srv_op_state srv_admin_state srv_uweight srv_iweight srv_time_since_last_change srv_check_status srv_check_result srv_check_health srv_check_state srv_agent_state bk_f_forced_id srv_f_forced_id srv_fqdn srv_port srvrecord\n")
+	for i, server := range servers {
+		adminState, opState := "1", "0"
+		if server.Ready {
+			adminState, opState = "0", "2"
+		}
+		state.WriteString(fmt.Sprintf("1 %s %d %s %s %s %s 1 1 0 1 0 0 0 0 0 0 - %d -\n", backend, i+1, server.Name, server.Address, opState, adminState, server.Port))
+	}
+	return state.String()
+}
+
+func (socket *HAProxySocket) serve() {
+	for {
+		conn, err := socket.Accept()
+		if err != nil {
+			return
+		}
+		go socket.handle(conn)
+	}
+}
+
+// handle answers one line of `;` separated commands, then closes the connection as HAProxy does in non-interactive mode.
+func (socket *HAProxySocket) handle(conn net.Conn) {
+	defer conn.Close()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	for _, command := range strings.Split(strings.TrimSpace(line), ";") {
+		command = strings.TrimSpace(command)
+		if command == "" || command == "set severity-output number" {
+			_, _ = io.WriteString(conn, "\n")
+			continue
+		}
+
+		socket.mutex.Lock()
+		socket.commands = append(socket.commands, command)
+		reply := socket.replyFn
+		socket.mutex.Unlock()
+
+		if _, err := io.WriteString(conn, reply(command)+"\n"); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github/mlyahmed.io/nominee/impl/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"testing"
+)
+
+// KubernetesConfigSpec mock the kubernetes.ConfigSpec.Load function
+type KubernetesConfigSpec struct {
+	*kubernetes.ConfigSpec
+}
+
+// KubernetesConnectorRecord ...
+type KubernetesConnectorRecord struct {
+	ConnectHits int
+	CleanupHits int
+}
+
+// KubernetesConnector connects to a fake clientset.
+type KubernetesConnector struct {
+	*KubernetesConnectorRecord
+	Clientset *fake.Clientset
+}
+
+// NewKubernetesConnector ...
+func NewKubernetesConnector(_ *testing.T, clientset *fake.Clientset) *KubernetesConnector {
+	return &KubernetesConnector{
+		KubernetesConnectorRecord: &KubernetesConnectorRecord{},
+		Clientset:                 clientset,
+	}
+}
+
+// NewPod ...
+func NewPod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+// Load ...
+func (conf *KubernetesConfigSpec) Load(_ context.Context) {
+	conf.Loaded = true
+}
+
+// Connect ...
+func (mock *KubernetesConnector) Connect(context.Context, *kubernetes.ConfigSpec) (k8s.Interface, error) {
+	mock.ConnectHits++
+	return mock.Clientset, nil
+}
+
+// Cleanup ...
+func (mock *KubernetesConnector) Cleanup() {
+	mock.CleanupHits++
+}
+
+// StallLeases makes the Lease API unavailable. So the election never settles by itself
+// and the leadership can be driven by hand.
+func (mock *KubernetesConnector) StallLeases() {
+	mock.Clientset.PrependReactor("*", "leases", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("the server is currently unable to handle the request")
+	})
+}
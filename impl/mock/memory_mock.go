@@ -0,0 +1,16 @@
+package mock
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/memory"
+)
+
+// MemoryConfigSpec mock the memory.ConfigSpec.Load function
+type MemoryConfigSpec struct {
+	*memory.ConfigSpec
+}
+
+// Load ...
+func (conf *MemoryConfigSpec) Load(_ context.Context) {
+	conf.Loaded = true
+}
@@ -0,0 +1,93 @@
+package mock
+
+import (
+	"context"
+	"database/sql"
+	"github/mlyahmed.io/nominee/impl/mysql"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"sync"
+)
+
+// MySQLConfigSpec mock the mysql.ConfigSpec.Load function
+type MySQLConfigSpec struct {
+	*mysql.ConfigSpec
+}
+
+// MySQLDB records the statements instead of sending them to a server.
+type MySQLDB struct {
+	mutex      *sync.Mutex
+	Statements []string
+	PingHits   int
+	ExecFn     func(ctx context.Context, query string) error
+	PingFn     func(ctx context.Context) error
+}
+
+// MySQLDaemonRecord ...
+type MySQLDaemonRecord struct {
+	StartHits int
+	StopHits  int
+}
+
+// MySQLDaemon ...
+type MySQLDaemon struct {
+	*MySQLDaemonRecord
+	StartFn  func(ctx context.Context) error
+	StopFn   func(ctx context.Context) error
+	DoneChan chan struct{}
+}
+
+// Load ...
+func (conf *MySQLConfigSpec) Load(_ context.Context) {
+}
+
+// NewMySQLDB ...
+func NewMySQLDB() *MySQLDB {
+	return &MySQLDB{
+		mutex:  &sync.Mutex{},
+		ExecFn: func(context.Context, string) error { return nil },
+		PingFn: func(context.Context) error { return nil },
+	}
+}
+
+// ExecContext ...
+func (db *MySQLDB) ExecContext(ctx context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	db.mutex.Lock()
+	db.Statements = append(db.Statements, query)
+	db.mutex.Unlock()
+	return nil, db.ExecFn(ctx, query)
+}
+
+// PingContext ...
+func (db *MySQLDB) PingContext(ctx context.Context) error {
+	db.mutex.Lock()
+	db.PingHits++
+	db.mutex.Unlock()
+	return db.PingFn(ctx)
+}
+
+// NewMySQLDaemon ...
+func NewMySQLDaemon() *MySQLDaemon {
+	return &MySQLDaemon{
+		MySQLDaemonRecord: &MySQLDaemonRecord{},
+		StartFn:           func(context.Context) error { return nil },
+		StopFn:            func(context.Context) error { return nil },
+		DoneChan:          make(chan struct{}),
+	}
+}
+
+// Start ...
+func (d *MySQLDaemon) Start(ctx context.Context) error {
+	d.StartHits++
+	return d.StartFn(ctx)
+}
+
+// Stop ...
+func (d *MySQLDaemon) Stop(ctx context.Context) error {
+	d.StopHits++
+	return d.StopFn(ctx)
+}
+
+// Done ...
+func (d *MySQLDaemon) Done() base.DoneChan {
+	return d.DoneChan
+}
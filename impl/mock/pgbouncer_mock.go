@@ -0,0 +1,181 @@
+package mock
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"github/mlyahmed.io/nominee/impl/pgbouncer"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	sslRequestCode = 80877103
+)
+
+// PgBouncerConfigSpec mock the pgbouncer.ConfigSpec.Load function
+type PgBouncerConfigSpec struct {
+	*pgbouncer.ConfigSpec
+}
+
+// PgBouncerConsole is a tiny in-process admin console. It speaks just enough of the Postgres protocol to
+// accept a trusted login and answer simple queries, it records the commands and answers them with the reply function.
+type PgBouncerConsole struct {
+	net.Listener
+	mutex    *sync.Mutex
+	commands []string
+	replyFn  func(command string) error
+}
+
+// PgBouncerDaemonRecord ...
+type PgBouncerDaemonRecord struct {
+	StartHits int
+}
+
+// PgBouncerDaemon ...
+type PgBouncerDaemon struct {
+	*PgBouncerDaemonRecord
+	StartFn  func(ctx context.Context) error
+	DoneChan chan struct{}
+}
+
+// Load ...
+func (conf *PgBouncerConfigSpec) Load(_ context.Context) {
+}
+
+// NewPgBouncerConsole ...
+func NewPgBouncerConsole(t *testing.T) *PgBouncerConsole {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	console := &PgBouncerConsole{Listener: listener, mutex: &sync.Mutex{}, replyFn: func(string) error { return nil }}
+	go console.serve()
+	return console
+}
+
+// Reply replaces the function that answers the commands, a non nil error is sent back as an ErrorResponse.
+func (console *PgBouncerConsole) Reply(fn func(command string) error) {
+	console.mutex.Lock()
+	defer console.mutex.Unlock()
+	console.replyFn = fn
+}
+
+// Port ...
+func (console *PgBouncerConsole) Port() int64 {
+	return int64(console.Addr().(*net.TCPAddr).Port)
+}
+
+// Commands returns the commands received so far.
+func (console *PgBouncerConsole) Commands() []string {
+	console.mutex.Lock()
+	defer console.mutex.Unlock()
+	return append([]string{}, console.commands...)
+}
+
+func (console *PgBouncerConsole) serve() {
+	for {
+		conn, err := console.Accept()
+		if err != nil {
+			return
+		}
+		go console.handle(conn)
+	}
+}
+
+func (console *PgBouncerConsole) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	if err := acceptStartup(reader, conn); err != nil {
+		return
+	}
+
+	for {
+		kind, payload, err := readPgMessage(reader)
+		if err != nil || kind == 'X' {
+			return
+		}
+		if kind != 'Q' {
+			continue
+		}
+
+		command := strings.TrimSuffix(strings.TrimSpace(strings.TrimRight(string(payload), "\x00")), ";")
+		console.mutex.Lock()
+		console.commands = append(console.commands, command)
+		reply := console.replyFn
+		console.mutex.Unlock()
+
+		if err := reply(command); err != nil {
+			writePgMessage(conn, 'E', []byte("SERROR\x00C08P01\x00M"+err.Error()+"\x00\x00"))
+		} else {
+			writePgMessage(conn, 'C', []byte(strings.Fields(command + " ")[0]+"\x00"))
+		}
+		writePgMessage(conn, 'Z', []byte{'I'})
+	}
+}
+
+func acceptStartup(reader *bufio.Reader, conn net.Conn) error {
+	for {
+		header := make([]byte, 8)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return err
+		}
+		length := binary.BigEndian.Uint32(header[:4])
+		if _, err := io.CopyN(ioutil.Discard, reader, int64(length)-8); err != nil {
+			return err
+		}
+		if binary.BigEndian.Uint32(header[4:]) == sslRequestCode {
+			_, _ = conn.Write([]byte{'N'})
+			continue
+		}
+		writePgMessage(conn, 'R', []byte{0, 0, 0, 0})
+		writePgMessage(conn, 'Z', []byte{'I'})
+		return nil
+	}
+}
+
+func readPgMessage(reader *bufio.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:])-4)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func writePgMessage(conn net.Conn, kind byte, payload []byte) {
+	message := make([]byte, 5, 5+len(payload))
+	message[0] = kind
+	binary.BigEndian.PutUint32(message[1:], uint32(4+len(payload)))
+	_, _ = conn.Write(append(message, payload...))
+}
+
+// NewPgBouncerDaemon ...
+func NewPgBouncerDaemon() *PgBouncerDaemon {
+	return &PgBouncerDaemon{
+		PgBouncerDaemonRecord: &PgBouncerDaemonRecord{},
+		StartFn:               func(context.Context) error { return nil },
+		DoneChan:              make(chan struct{}),
+	}
+}
+
+// Start ...
+func (d *PgBouncerDaemon) Start(ctx context.Context) error {
+	d.StartHits++
+	return d.StartFn(ctx)
+}
+
+// Done ...
+func (d *PgBouncerDaemon) Done() base.DoneChan {
+	return d.DoneChan
+}
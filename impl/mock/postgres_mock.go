@@ -0,0 +1,138 @@
+package mock
+
+import (
+	"context"
+	gopg "github.com/go-pg/pg/v10"
+	"github/mlyahmed.io/nominee/impl/postgres"
+	"sync"
+)
+
+// PostgresConfigSpec mock the postgres.ConfigSpec.Load function
+type PostgresConfigSpec struct {
+	*postgres.ConfigSpec
+}
+
+// PostgresDB records the statements instead of sending them to a server.
+type PostgresDB struct {
+	mutex      *sync.Mutex
+	Statements []string
+	ExecFn     func(ctx context.Context, query string, params ...interface{}) error
+	QueryFn    func(ctx context.Context, model interface{}, query string, params ...interface{}) error
+	PingFn     func(ctx context.Context) error
+}
+
+// PostgresDaemon runs until it is stopped.
+type PostgresDaemon struct {
+	mutex   *sync.Mutex
+	RunHits int
+	RunFn   func(ctx context.Context) error
+}
+
+// PostgresExecutor records the OS commands instead of running them.
+type PostgresExecutor struct {
+	mutex    *sync.Mutex
+	Commands []string
+	ExecFn   func(ctx context.Context, cmd string) error
+	OutputFn func(ctx context.Context, cmd string) ([]byte, error)
+}
+
+// Load ...
+func (conf *PostgresConfigSpec) Load(_ context.Context) {
+}
+
+// NewPostgresDB ...
+func NewPostgresDB() *PostgresDB {
+	return &PostgresDB{
+		mutex:   &sync.Mutex{},
+		ExecFn:  func(context.Context, string, ...interface{}) error { return nil },
+		QueryFn: func(context.Context, interface{}, string, ...interface{}) error { return nil },
+		PingFn:  func(context.Context) error { return nil },
+	}
+}
+
+// ExecContext ...
+func (db *PostgresDB) ExecContext(ctx context.Context, query interface{}, params ...interface{}) (gopg.Result, error) {
+	statement := query.(string)
+	db.mutex.Lock()
+	db.Statements = append(db.Statements, statement)
+	db.mutex.Unlock()
+	return nil, db.ExecFn(ctx, statement, params...)
+}
+
+// QueryContext ...
+func (db *PostgresDB) QueryContext(ctx context.Context, model, query interface{}, params ...interface{}) (gopg.Result, error) {
+	return nil, db.QueryFn(ctx, model, query.(string), params...)
+}
+
+// QueryOneContext ...
+func (db *PostgresDB) QueryOneContext(ctx context.Context, model, query interface{}, params ...interface{}) (gopg.Result, error) {
+	return nil, db.QueryFn(ctx, model, query.(string), params...)
+}
+
+// Ping ...
+func (db *PostgresDB) Ping(ctx context.Context) error {
+	return db.PingFn(ctx)
+}
+
+// Close ...
+func (db *PostgresDB) Close() error {
+	return nil
+}
+
+// GetStatements ...
+func (db *PostgresDB) GetStatements() []string {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	return append([]string(nil), db.Statements...)
+}
+
+// NewPostgresDaemon returns a daemon which runs until the context is done.
+func NewPostgresDaemon() *PostgresDaemon {
+	return &PostgresDaemon{
+		mutex: &sync.Mutex{},
+		RunFn: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+}
+
+// Run ...
+func (d *PostgresDaemon) Run(ctx context.Context) error {
+	d.mutex.Lock()
+	d.RunHits++
+	d.mutex.Unlock()
+	return d.RunFn(ctx)
+}
+
+// NewPostgresExecutor ...
+func NewPostgresExecutor() *PostgresExecutor {
+	return &PostgresExecutor{
+		mutex:    &sync.Mutex{},
+		ExecFn:   func(context.Context, string) error { return nil },
+		OutputFn: func(context.Context, string) ([]byte, error) { return nil, nil },
+	}
+}
+
+// Exec ...
+func (e *PostgresExecutor) Exec(ctx context.Context, cmd string, _ int) error {
+	e.mutex.Lock()
+	e.Commands = append(e.Commands, cmd)
+	e.mutex.Unlock()
+	return e.ExecFn(ctx, cmd)
+}
+
+// Output ...
+func (e *PostgresExecutor) Output(ctx context.Context, cmd string) ([]byte, error) {
+	e.mutex.Lock()
+	e.Commands = append(e.Commands, cmd)
+	e.mutex.Unlock()
+	return e.OutputFn(ctx, cmd)
+}
+
+// GetCommands ...
+func (e *PostgresExecutor) GetCommands() []string {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return append([]string(nil), e.Commands...)
+}
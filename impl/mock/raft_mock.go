@@ -0,0 +1,28 @@
+package mock
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/raft"
+	"net"
+	"testing"
+)
+
+// RaftConfigSpec mock the raft.ConfigSpec.Load function
+type RaftConfigSpec struct {
+	*raft.ConfigSpec
+}
+
+// Load ...
+func (conf *RaftConfigSpec) Load(_ context.Context) {
+	conf.Loaded = true
+}
+
+// FreeAddress returns a loopback address nobody listens on.
+func FreeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("no free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
@@ -0,0 +1,180 @@
+package mock
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/impl/redis"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RedisConfigSpec mock the redis.ConfigSpec.Load function
+type RedisConfigSpec struct {
+	*redis.ConfigSpec
+}
+
+// RedisServer is a tiny in-process RESP server. It records the commands and answers them with the reply function.
+type RedisServer struct {
+	net.Listener
+	mutex    *sync.Mutex
+	commands [][]string
+	replyFn  func(command []string) string
+}
+
+// RedisDaemonRecord ...
+type RedisDaemonRecord struct {
+	StartHits int
+	StopHits  int
+}
+
+// RedisDaemon ...
+type RedisDaemon struct {
+	*RedisDaemonRecord
+	StartFn  func(ctx context.Context) error
+	StopFn   func(ctx context.Context) error
+	DoneChan chan struct{}
+}
+
+// Load ...
+func (conf *RedisConfigSpec) Load(_ context.Context) {
+}
+
+// NewRedisServer ...
+func NewRedisServer(t *testing.T) *RedisServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := &RedisServer{Listener: listener, mutex: &sync.Mutex{}, replyFn: RedisOK}
+	go server.serve()
+	return server
+}
+
+// RedisOK answers PONG to PING and OK to everything else.
+func RedisOK(command []string) string {
+	if strings.EqualFold(command[0], "PING") {
+		return "+PONG\r\n"
+	}
+	return "+OK\r\n"
+}
+
+// Reply replaces the function that answers the commands, it must return a raw RESP reply.
+func (server *RedisServer) Reply(fn func(command []string) string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.replyFn = fn
+}
+
+// Port ...
+func (server *RedisServer) Port() int64 {
+	return int64(server.Addr().(*net.TCPAddr).Port)
+}
+
+// Commands returns the commands received so far, PING excluded.
+func (server *RedisServer) Commands() [][]string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	commands := make([][]string, 0, len(server.commands))
+	for _, command := range server.commands {
+		if !strings.EqualFold(command[0], "PING") {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+func (server *RedisServer) serve() {
+	for {
+		conn, err := server.Accept()
+		if err != nil {
+			return
+		}
+		go server.handle(conn)
+	}
+}
+
+func (server *RedisServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		command, err := readRESPArray(reader)
+		if err != nil {
+			return
+		}
+
+		server.mutex.Lock()
+		server.commands = append(server.commands, command)
+		reply := server.replyFn
+		server.mutex.Unlock()
+
+		if _, err := io.WriteString(conn, reply(command)); err != nil {
+			return
+		}
+	}
+}
+
+func readRESPArray(reader *bufio.Reader) ([]string, error) {
+	header, err := readRESPLine(reader, '*')
+	if err != nil {
+		return nil, err
+	}
+
+	command := make([]string, header)
+	for i := range command {
+		length, err := readRESPLine(reader, '$')
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		command[i] = string(data[:length])
+	}
+	return command, nil
+}
+
+func readRESPLine(reader *bufio.Reader, prefix byte) (int, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if len(line) < 3 || line[0] != prefix {
+		return 0, fmt.Errorf("unexpected RESP line %q", line)
+	}
+	return strconv.Atoi(strings.TrimSpace(line[1:]))
+}
+
+// NewRedisDaemon ...
+func NewRedisDaemon() *RedisDaemon {
+	return &RedisDaemon{
+		RedisDaemonRecord: &RedisDaemonRecord{},
+		StartFn:           func(context.Context) error { return nil },
+		StopFn:            func(context.Context) error { return nil },
+		DoneChan:          make(chan struct{}),
+	}
+}
+
+// Start ...
+func (d *RedisDaemon) Start(ctx context.Context) error {
+	d.StartHits++
+	return d.StartFn(ctx)
+}
+
+// Stop ...
+func (d *RedisDaemon) Stop(ctx context.Context) error {
+	d.StopHits++
+	return d.StopFn(ctx)
+}
+
+// Done ...
+func (d *RedisDaemon) Done() base.DoneChan {
+	return d.DoneChan
+}
@@ -0,0 +1,174 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"strings"
+	"time"
+)
+
+type status int
+
+const (
+	started status = iota
+	stopped
+)
+
+const (
+	defaultWaitRetry = time.Second * 2
+	defaultRetries   = 3
+	root             = "root"
+)
+
+var (
+	log = logrus.WithFields(logrus.Fields{"daemon": "mysql"})
+)
+
+// DBUser ...
+type DBUser struct {
+	Username string
+	Password string
+}
+
+// DB is the SQL layer of the node. *sql.DB satisfies it.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PingContext(ctx context.Context) error
+}
+
+// MySQL ...
+type MySQL struct {
+	*node.Spec
+	DB          DB
+	Daemon      Daemon
+	cluster     string
+	domain      string
+	dialect     dialect
+	replicaUser DBUser
+	status      status
+	leader      node.Spec
+}
+
+// NewMySQL ...
+func NewMySQL(cl ConfigLoader) *MySQL {
+	cl.Load(context.Background())
+	config := cl.GetSpec()
+	db, _ := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(127.0.0.1:%d)/", config.Root.Username, config.Root.Password, config.NodeSpec.Port))
+	m := &MySQL{
+		Spec:        &config.NodeSpec,
+		DB:          db,
+		Daemon:      NewEntrypointDaemon(config.Root),
+		cluster:     config.Cluster,
+		domain:      config.Domain,
+		dialect:     statements[config.Flavor],
+		replicaUser: config.Replicator,
+		status:      stopped,
+	}
+
+	log = logrus.WithFields(logrus.Fields{"daemon": m.GetDaemonName(), "node": m.GetName()})
+	return m
+}
+
+// GetDaemonName ...
+func (m *MySQL) GetDaemonName() string {
+	return "mysql"
+}
+
+// Lead stops the replication, forgets about the former source and opens the writes.
+func (m *MySQL) Lead(ctx context.Context, myself node.Spec) error {
+	log.Infof("mysql: promote to primary as %v ...\n", myself.Name)
+	m.leader = myself
+
+	if err := m.start(ctx); err != nil {
+		return err
+	}
+
+	return m.execDBCmds(ctx,
+		m.dialect.stopReplica,
+		m.dialect.resetReplica,
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s@'%%' IDENTIFIED BY %s", quote(m.replicaUser.Username), quote(m.replicaUser.Password)),
+		fmt.Sprintf("GRANT REPLICATION SLAVE ON *.* TO %s@'%%'", quote(m.replicaUser.Username)),
+		m.dialect.readWrite,
+	)
+}
+
+// Follow closes the writes and replicates from the leader, positioned by GTID.
+func (m *MySQL) Follow(ctx context.Context, leader node.Spec) error {
+	log.Infof("mysql: following the new leader: %v \n", leader.Name)
+	m.leader = leader
+
+	if err := m.start(ctx); err != nil {
+		return err
+	}
+
+	return m.execDBCmds(ctx,
+		m.dialect.readOnly,
+		m.dialect.stopReplica,
+		fmt.Sprintf(m.dialect.changeSource, quote(leader.Address), leader.Port, quote(m.replicaUser.Username), quote(m.replicaUser.Password)),
+		m.dialect.startReplica,
+	)
+}
+
+// Stonith ...
+func (m *MySQL) Stonith(ctx context.Context) {
+	log.Infof("mysql: stonithing... \n")
+	_ = m.Daemon.Stop(ctx)
+	m.status = stopped
+}
+
+// Done ...
+func (m *MySQL) Done() base.DoneChan {
+	return m.Daemon.Done()
+}
+
+func (m *MySQL) start(ctx context.Context) error {
+	if m.status == started {
+		return nil
+	}
+
+	if err := m.DB.PingContext(ctx); err != nil {
+		if err := m.Daemon.Start(ctx); err != nil {
+			return err
+		}
+		if err := m.warmUp(ctx, defaultRetries); err != nil {
+			return err
+		}
+	}
+
+	m.status = started
+	return nil
+}
+
+func (m *MySQL) execDBCmds(ctx context.Context, cmds ...string) error {
+	for _, cmd := range cmds {
+		if _, err := m.DB.ExecContext(ctx, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MySQL) warmUp(ctx context.Context, retries int) error {
+	for i := retries; ; i-- {
+		if err := m.DB.PingContext(ctx); err != nil {
+			if i >= 0 {
+				time.Sleep(defaultWaitRetry)
+				continue
+			} else {
+				return err
+			}
+		}
+		break
+	}
+	return nil
+}
+
+// quote makes a MySQL string literal of the value.
+func quote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
@@ -0,0 +1,63 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"os"
+)
+
+// ConfigLoader ...
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	NodeSpec   node.Spec
+	Flavor     Flavor
+	Root       DBUser
+	Replicator DBUser
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{
+		BasicConfig: config.NewBasicConfig(),
+		NodeSpec:    node.Spec{},
+		Root:        DBUser{},
+		Replicator:  DBUser{},
+	}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	config.SetDefault("NOMINEE_MYSQL_NODE_PORT", 3306)
+	config.SetDefault("NOMINEE_MYSQL_FLAVOR", string(FlavorMySQL))
+
+	conf.NodeSpec.Name = config.GetStringOrPanic("NOMINEE_MYSQL_NODE_NAME")
+	conf.NodeSpec.Address = config.GetStringOrPanic("NOMINEE_MYSQL_NODE_ADDRESS")
+	conf.NodeSpec.Port = int64(config.GetIntOrPanic("NOMINEE_MYSQL_NODE_PORT"))
+	conf.NodeSpec.Tags = node.LoadTags("NOMINEE_MYSQL_NODE")
+	conf.Flavor = Flavor(config.GetStringOrPanic("NOMINEE_MYSQL_FLAVOR"))
+	conf.Root.Username = root
+	conf.Root.Password = config.GetStringOrPanic("NOMINEE_MYSQL_ROOT_PASSWORD")
+	conf.Replicator.Username = config.GetStringOrPanic("NOMINEE_MYSQL_REP_USERNAME")
+	conf.Replicator.Password = config.GetStringOrPanic("NOMINEE_MYSQL_REP_PASSWORD")
+
+	if _, ok := statements[conf.Flavor]; !ok {
+		panic(fmt.Sprintf("unknown MySQL flavor <%s>.", conf.Flavor))
+	}
+
+	if err := os.Setenv("MYSQL_ROOT_PASSWORD", conf.Root.Password); err != nil {
+		panic(err)
+	}
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	return conf
+}
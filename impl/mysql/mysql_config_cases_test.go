@@ -0,0 +1,128 @@
+package mysql_test
+
+type configurationExamples struct {
+	description        string
+	cluster            string
+	domain             string
+	nodeName           string
+	nodeAddress        string
+	nodePort           string
+	flavor             string
+	rootPassword       string
+	replicatorUsername string
+	replicatorPassword string
+}
+
+var validExamples = []configurationExamples{
+	{
+		description:        "minimum configuration",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "mysql-01",
+		nodeAddress:        "node01.mysql.priv",
+		rootPassword:       "my$ql",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+	},
+	{
+		description:        "full configuration #1",
+		cluster:            "cluster-009",
+		domain:             "domain-012",
+		nodeName:           "mysql-99",
+		nodeAddress:        "node99.mysql.priv",
+		nodePort:           "3307",
+		flavor:             "mysql",
+		rootPassword:       "my$$$$$",
+		replicatorUsername: "repl",
+		replicatorPassword: "@$ecret",
+	},
+	{
+		description:        "full configuration #2",
+		cluster:            "cluster-209",
+		domain:             "domain-713",
+		nodeName:           "mariadb-77",
+		nodeAddress:        "node77.mariadb.priv",
+		nodePort:           "3000",
+		flavor:             "mariadb",
+		rootPassword:       "()_+++==MIN$%^&)",
+		replicatorUsername: "repl",
+		replicatorPassword: "SHUT$$$",
+	},
+}
+
+var invalidExamples = []configurationExamples{
+	{
+		description:        "cluster name is missing",
+		domain:             "domain-111",
+		nodeName:           "node-11",
+		nodeAddress:        "node11.mysql.priv",
+		rootPassword:       "my$ql",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+	},
+	{
+		description:        "domain name is missing",
+		cluster:            "cluster-991",
+		nodeName:           "mysql-71",
+		nodeAddress:        "mysql71.my.db",
+		rootPassword:       "_-=+&^%$",
+		replicatorUsername: "copier",
+		replicatorPassword: "^%%$%$7687878",
+	},
+	{
+		description:        "node name is missing",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeAddress:        "node01.mysql.priv",
+		rootPassword:       "my$ql",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+	},
+	{
+		description:        "node address is missing",
+		cluster:            "cluster-009",
+		domain:             "domain-012",
+		nodeName:           "mysql-99",
+		rootPassword:       "my$$$$$",
+		replicatorUsername: "repl",
+		replicatorPassword: "@$ecret",
+	},
+	{
+		description:        "root password is missing",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "mysql-01",
+		nodeAddress:        "node01.mysql.priv",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+	},
+	{
+		description:        "replicator username is missing",
+		cluster:            "cluster-209",
+		domain:             "domain-713",
+		nodeName:           "mysql-77",
+		nodeAddress:        "node77.mysql.priv",
+		rootPassword:       "()_+++==MIN$%^&)",
+		replicatorPassword: "SHUT$$$",
+	},
+	{
+		description:        "replicator password is missing",
+		cluster:            "cluster-009",
+		domain:             "domain-012",
+		nodeName:           "mysql-99",
+		nodeAddress:        "node99.mysql.priv",
+		rootPassword:       "my$$$$$",
+		replicatorUsername: "repl",
+	},
+	{
+		description:        "unknown flavor",
+		cluster:            "cluster-009",
+		domain:             "domain-012",
+		nodeName:           "mysql-99",
+		nodeAddress:        "node99.mysql.priv",
+		flavor:             "percona-5.6",
+		rootPassword:       "my$$$$$",
+		replicatorUsername: "repl",
+		replicatorPassword: "@$ecret",
+	},
+}
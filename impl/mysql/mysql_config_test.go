@@ -0,0 +1,109 @@
+package mysql_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/mysql"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestMySQLConfig_loads_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run(example.description, func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := mysql.NewConfigLoader()
+			loader.Load(context.TODO())
+			myConfig := loader.GetSpec()
+			if myConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, myConfig.Cluster)
+			}
+
+			if myConfig.Domain != example.domain {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, myConfig.Domain)
+			}
+
+			if myConfig.NodeSpec.Name != example.nodeName {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Name, expected <%s> but actual is <%s>", testutils.Failed, example.nodeName, myConfig.NodeSpec.Name)
+			}
+
+			if myConfig.NodeSpec.Address != example.nodeAddress {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Address, expected <%s> but actual is <%s>", testutils.Failed, example.nodeAddress, myConfig.NodeSpec.Address)
+			}
+
+			expectedPort := example.nodePort
+			if expectedPort == "" {
+				expectedPort = "3306"
+			}
+			if strconv.Itoa(int(myConfig.NodeSpec.Port)) != expectedPort {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Port, expected <%s> but actual is <%d>", testutils.Failed, expectedPort, myConfig.NodeSpec.Port)
+			}
+
+			expectedFlavor := mysql.Flavor(example.flavor)
+			if expectedFlavor == "" {
+				expectedFlavor = mysql.FlavorMySQL
+			}
+			if myConfig.Flavor != expectedFlavor {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Flavor, expected <%s> but actual is <%s>", testutils.Failed, expectedFlavor, myConfig.Flavor)
+			}
+
+			if myConfig.Root.Password != example.rootPassword {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Root.Password, expected <%s> but actual is <%s>", testutils.Failed, example.rootPassword, myConfig.Root.Password)
+			}
+
+			if envPassword := os.Getenv("MYSQL_ROOT_PASSWORD"); envPassword != example.rootPassword {
+				t.Fatalf("\t\t%s FAIL: Getenv('MYSQL_ROOT_PASSWORD'), expected <%s> but actual is <%s>", testutils.Failed, example.rootPassword, envPassword)
+			}
+
+			if myConfig.Replicator.Username != example.replicatorUsername {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Replicator.Username, expected <%s> but actual is <%s>", testutils.Failed, example.replicatorUsername, myConfig.Replicator.Username)
+			}
+
+			if myConfig.Replicator.Password != example.replicatorPassword {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Replicator.Password, expected <%s> but actual is <%s>", testutils.Failed, example.replicatorPassword, myConfig.Replicator.Password)
+			}
+		})
+	}
+}
+
+func TestMySQLConfig_panics_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run(example.description, func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			myConfig := mysql.NewConfigLoader()
+			myConfig.Load(context.TODO())
+		})
+	}
+}
+
+func declareConfigurationExample(example configurationExamples) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_MYSQL_NODE_NAME", example.nodeName)
+	_ = os.Setenv("NOMINEE_MYSQL_NODE_ADDRESS", example.nodeAddress)
+	_ = os.Setenv("NOMINEE_MYSQL_NODE_PORT", example.nodePort)
+	_ = os.Setenv("NOMINEE_MYSQL_FLAVOR", example.flavor)
+	_ = os.Setenv("NOMINEE_MYSQL_ROOT_PASSWORD", example.rootPassword)
+	_ = os.Setenv("NOMINEE_MYSQL_REP_USERNAME", example.replicatorUsername)
+	_ = os.Setenv("NOMINEE_MYSQL_REP_PASSWORD", example.replicatorPassword)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_MYSQL_NODE_NAME")
+	_ = os.Unsetenv("NOMINEE_MYSQL_NODE_ADDRESS")
+	_ = os.Unsetenv("NOMINEE_MYSQL_NODE_PORT")
+	_ = os.Unsetenv("NOMINEE_MYSQL_FLAVOR")
+	_ = os.Unsetenv("NOMINEE_MYSQL_ROOT_PASSWORD")
+	_ = os.Unsetenv("NOMINEE_MYSQL_REP_USERNAME")
+	_ = os.Unsetenv("NOMINEE_MYSQL_REP_PASSWORD")
+}
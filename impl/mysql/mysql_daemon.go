@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"os"
+	"os/exec"
+)
+
+// Daemon runs the mysqld process.
+type Daemon interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Done() base.DoneChan
+}
+
+// EntrypointDaemon runs mysqld through the docker entrypoint, which initializes the data directory on the first start.
+type EntrypointDaemon struct {
+	root   DBUser
+	doneCh chan struct{}
+}
+
+// NewEntrypointDaemon ...
+func NewEntrypointDaemon(root DBUser) *EntrypointDaemon {
+	return &EntrypointDaemon{root: root, doneCh: make(chan struct{})}
+}
+
+// Start ...
+func (d *EntrypointDaemon) Start(ctx context.Context) error {
+	start := exec.CommandContext(ctx, "docker-entrypoint.sh", "mysqld")
+	start.Stdout, start.Stderr = log.Writer(), log.Writer()
+	if err := start.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		_ = start.Wait() //FIXME:
+		log.Warnf("Run command has returned.")
+		d.doneCh <- struct{}{}
+	}()
+	return nil
+}
+
+// Stop ...
+func (d *EntrypointDaemon) Stop(ctx context.Context) error {
+	stop := exec.CommandContext(ctx, "mysqladmin", "--user="+d.root.Username, "shutdown")
+	stop.Env = append(os.Environ(), "MYSQL_PWD="+d.root.Password)
+	stop.Stdout, stop.Stderr = log.Writer(), log.Writer()
+	return stop.Run()
+}
+
+// Done ...
+func (d *EntrypointDaemon) Done() base.DoneChan {
+	return d.doneCh
+}
@@ -0,0 +1,40 @@
+package mysql
+
+// Flavor is the replication dialect of the server.
+type Flavor string
+
+const (
+	// FlavorMySQL is MySQL 8.0.22 or later.
+	FlavorMySQL Flavor = "mysql"
+
+	// FlavorMariaDB is MariaDB 10.x.
+	FlavorMariaDB Flavor = "mariadb"
+)
+
+type dialect struct {
+	stopReplica  string
+	resetReplica string
+	startReplica string
+	readOnly     string
+	readWrite    string
+	changeSource string
+}
+
+var statements = map[Flavor]dialect{
+	FlavorMySQL: {
+		stopReplica:  "STOP REPLICA",
+		resetReplica: "RESET REPLICA ALL",
+		startReplica: "START REPLICA",
+		readOnly:     "SET GLOBAL super_read_only = ON",
+		readWrite:    "SET GLOBAL read_only = OFF",
+		changeSource: "CHANGE REPLICATION SOURCE TO SOURCE_HOST = %s, SOURCE_PORT = %d, SOURCE_USER = %s, SOURCE_PASSWORD = %s, SOURCE_AUTO_POSITION = 1",
+	},
+	FlavorMariaDB: {
+		stopReplica:  "STOP SLAVE",
+		resetReplica: "RESET SLAVE ALL",
+		startReplica: "START SLAVE",
+		readOnly:     "SET GLOBAL read_only = ON",
+		readWrite:    "SET GLOBAL read_only = OFF",
+		changeSource: "CHANGE MASTER TO MASTER_HOST = %s, MASTER_PORT = %d, MASTER_USER = %s, MASTER_PASSWORD = %s, MASTER_USE_GTID = slave_pos",
+	},
+}
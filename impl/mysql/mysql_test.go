@@ -0,0 +1,162 @@
+package mysql_test
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/impl/mysql"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var leader = node.Spec{Name: "mysql-01", Address: "node01.mysql.priv", Port: 3307}
+
+var flavorExamples = []struct {
+	flavor mysql.Flavor
+	lead   []string
+	follow []string
+}{
+	{
+		flavor: mysql.FlavorMySQL,
+		lead: []string{
+			"STOP REPLICA",
+			"RESET REPLICA ALL",
+			"CREATE USER IF NOT EXISTS 'repl'@'%' IDENTIFIED BY 'it\\'s $ecret'",
+			"GRANT REPLICATION SLAVE ON *.* TO 'repl'@'%'",
+			"SET GLOBAL read_only = OFF",
+		},
+		follow: []string{
+			"SET GLOBAL super_read_only = ON",
+			"STOP REPLICA",
+			"CHANGE REPLICATION SOURCE TO SOURCE_HOST = 'node01.mysql.priv', SOURCE_PORT = 3307, SOURCE_USER = 'repl', SOURCE_PASSWORD = 'it\\'s $ecret', SOURCE_AUTO_POSITION = 1",
+			"START REPLICA",
+		},
+	},
+	{
+		flavor: mysql.FlavorMariaDB,
+		lead: []string{
+			"STOP SLAVE",
+			"RESET SLAVE ALL",
+			"CREATE USER IF NOT EXISTS 'repl'@'%' IDENTIFIED BY 'it\\'s $ecret'",
+			"GRANT REPLICATION SLAVE ON *.* TO 'repl'@'%'",
+			"SET GLOBAL read_only = OFF",
+		},
+		follow: []string{
+			"SET GLOBAL read_only = ON",
+			"STOP SLAVE",
+			"CHANGE MASTER TO MASTER_HOST = 'node01.mysql.priv', MASTER_PORT = 3307, MASTER_USER = 'repl', MASTER_PASSWORD = 'it\\'s $ecret', MASTER_USE_GTID = slave_pos",
+			"START SLAVE",
+		},
+	},
+}
+
+func newMySQL(flavor mysql.Flavor) (*mysql.MySQL, *mock.MySQLDB, *mock.MySQLDaemon) {
+	m := mysql.NewMySQL(&mock.MySQLConfigSpec{ConfigSpec: &mysql.ConfigSpec{
+		BasicConfig: &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		NodeSpec:    node.Spec{Name: "mysql-02", Address: "node02.mysql.priv", Port: 3306},
+		Flavor:      flavor,
+		Root:        mysql.DBUser{Username: "root", Password: "root"},
+		Replicator:  mysql.DBUser{Username: "repl", Password: "it's $ecret"},
+	}})
+	db, daemon := mock.NewMySQLDB(), mock.NewMySQLDaemon()
+	m.DB, m.Daemon = db, daemon
+	return m, db, daemon
+}
+
+func TestMySQL_when_lead_then_stop_the_replication_and_open_the_writes(t *testing.T) {
+	for _, example := range flavorExamples {
+		t.Run(string(example.flavor), func(t *testing.T) {
+			m, db, _ := newMySQL(example.flavor)
+
+			if err := m.Lead(context.TODO(), *m.Spec); err != nil {
+				t.Fatalf("\t\t%s FATAL: MySQL.Lead, %v", testutils.Failed, err)
+			}
+
+			if !reflect.DeepEqual(db.Statements, example.lead) {
+				t.Fatalf("\t\t%s FAIL: MySQL.Lead, expected <%v> but actual is <%v>", testutils.Failed, example.lead, db.Statements)
+			}
+		})
+	}
+}
+
+func TestMySQL_when_follow_then_replicate_from_the_leader(t *testing.T) {
+	for _, example := range flavorExamples {
+		t.Run(string(example.flavor), func(t *testing.T) {
+			m, db, _ := newMySQL(example.flavor)
+
+			if err := m.Follow(context.TODO(), leader); err != nil {
+				t.Fatalf("\t\t%s FATAL: MySQL.Follow, %v", testutils.Failed, err)
+			}
+
+			if !reflect.DeepEqual(db.Statements, example.follow) {
+				t.Fatalf("\t\t%s FAIL: MySQL.Follow, expected <%v> but actual is <%v>", testutils.Failed, example.follow, db.Statements)
+			}
+		})
+	}
+}
+
+func TestMySQL_when_the_server_is_down_then_start_it(t *testing.T) {
+	m, db, daemon := newMySQL(mysql.FlavorMySQL)
+	db.PingFn = func(context.Context) error {
+		if daemon.StartHits == 0 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+
+	if err := m.Follow(context.TODO(), leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: MySQL.Follow, %v", testutils.Failed, err)
+	}
+
+	if daemon.StartHits != 1 {
+		t.Fatalf("\t\t%s FAIL: MySQL.Follow, expected to start the server once but actual is <%d>", testutils.Failed, daemon.StartHits)
+	}
+}
+
+func TestMySQL_when_the_server_is_up_then_do_not_start_it(t *testing.T) {
+	m, _, daemon := newMySQL(mysql.FlavorMySQL)
+
+	if err := m.Lead(context.TODO(), *m.Spec); err != nil {
+		t.Fatalf("\t\t%s FATAL: MySQL.Lead, %v", testutils.Failed, err)
+	}
+
+	if daemon.StartHits != 0 {
+		t.Fatalf("\t\t%s FAIL: MySQL.Lead, expected not to start the server but actual is <%d>", testutils.Failed, daemon.StartHits)
+	}
+}
+
+func TestMySQL_when_a_statement_fails_then_return_the_error(t *testing.T) {
+	for _, example := range flavorExamples {
+		t.Run(string(example.flavor), func(t *testing.T) {
+			m, db, _ := newMySQL(example.flavor)
+			db.ExecFn = func(context.Context, string) error { return errors.New("access denied") }
+
+			if err := m.Lead(context.TODO(), *m.Spec); err == nil {
+				t.Fatalf("\t\t%s FAIL: MySQL.Lead, expected an error. Actually not.", testutils.Failed)
+			}
+
+			if err := m.Follow(context.TODO(), leader); err == nil {
+				t.Fatalf("\t\t%s FAIL: MySQL.Follow, expected an error. Actually not.", testutils.Failed)
+			}
+		})
+	}
+}
+
+func TestMySQL_when_stonith_then_stop_the_server(t *testing.T) {
+	m, _, daemon := newMySQL(mysql.FlavorMySQL)
+
+	m.Stonith(context.TODO())
+
+	if daemon.StopHits != 1 {
+		t.Fatalf("\t\t%s FAIL: MySQL.Stonith, expected to stop the server. Actually not.", testutils.Failed)
+	}
+}
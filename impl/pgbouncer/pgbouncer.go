@@ -0,0 +1,254 @@
+package pgbouncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gopg "github.com/go-pg/pg/v10"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/proxy"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWaitRetry = time.Second * 2
+	defaultRetries   = 3
+	databasesSection = "[databases]"
+)
+
+var (
+	log = logrus.WithFields(logrus.Fields{"proxy": "pgbouncer"})
+
+	errTimeout = errors.New("timed out")
+)
+
+// PgBouncer points the PgBouncer databases to the leader. It holds the clients with PAUSE while it switches over.
+type PgBouncer struct {
+	*proxy.BasicProxy
+	Daemon  Daemon
+	config  *ConfigSpec
+	console *gopg.DB
+	leader  node.Spec
+	started bool
+	paused  bool
+	mutex   *sync.Mutex
+}
+
+// NewPgBouncer ...
+func NewPgBouncer(cl ConfigLoader) *PgBouncer {
+	cl.Load(context.Background())
+	config := cl.GetSpec()
+	return &PgBouncer{
+		BasicProxy: proxy.NewBasicProxy(),
+		Daemon:     NewExecDaemon(config.ExecFile, config.ConfigFile),
+		config:     config,
+		console: gopg.Connect(&gopg.Options{
+			Addr:     fmt.Sprintf("%s:%d", config.Admin.Address, config.Admin.Port),
+			User:     config.Admin.Username,
+			Password: config.Admin.Password,
+			Database: "pgbouncer",
+			PoolSize: 1,
+		}),
+		mutex: &sync.Mutex{},
+	}
+}
+
+// Publish rewrites the [databases] section to the leader. The followers are not routed.
+func (p *PgBouncer) Publish(leader *node.Spec, _ ...*node.Spec) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.started {
+		return p.start(leader)
+	}
+
+	if leader == nil || leader.Address == "" {
+		return p.pause()
+	}
+
+	if !p.paused && p.leader.Address == leader.Address && p.leader.Port == leader.Port {
+		return nil
+	}
+
+	if err := p.pause(); err != nil {
+		return err
+	}
+
+	if err := p.writeDatabases(leader); err != nil {
+		return err
+	}
+
+	if err := p.admin("RELOAD"); err != nil {
+		return err
+	}
+
+	if err := p.admin("RESUME"); err != nil {
+		return err
+	}
+
+	p.paused = false
+	p.leader = *leader
+	log.Infof("databases switched to %s (%s:%d)", leader.Name, leader.Address, leader.Port)
+	return nil
+}
+
+func (p *PgBouncer) start(leader *node.Spec) error {
+	if leader != nil && leader.Address == "" {
+		leader = nil
+	}
+
+	if err := p.writeDatabases(leader); err != nil {
+		return err
+	}
+
+	if err := p.Daemon.Start(p.Ctx); err != nil {
+		return err
+	}
+	p.started = true
+
+	go func() {
+		select {
+		case <-p.Daemon.Done():
+			p.Stonith(p.Ctx)
+		case <-p.Ctx.Done():
+		}
+		_ = p.console.Close()
+	}()
+
+	if leader == nil {
+		return nil
+	}
+	p.leader = *leader
+	log.Infof("started with the databases on %s (%s:%d)", leader.Name, leader.Address, leader.Port)
+	return nil
+}
+
+// pause holds the clients. PAUSE waits for them to release their server connections, which they may never do from a
+// dead leader under session pooling, so that they are killed when it times out. The killed databases hold the new
+// clients until RESUME, as paused ones.
+func (p *PgBouncer) pause() error {
+	if p.paused {
+		return nil
+	}
+	if err := p.admin("PAUSE"); err != nil {
+		if !errors.Is(err, errTimeout) {
+			return err
+		}
+		log.Warnf("%v, killing the clients", err)
+		if err := p.kill(); err != nil {
+			return err
+		}
+	}
+	p.paused = true
+	log.Infof("clients paused")
+	return nil
+}
+
+// kill drops the clients and the server connections of the databases, all of them when the wildcard is routed.
+func (p *PgBouncer) kill() error {
+	for _, database := range p.config.Databases {
+		if database == "*" {
+			return p.admin("KILL")
+		}
+	}
+	for _, database := range p.config.Databases {
+		if err := p.admin("KILL " + database); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// admin runs the command on the console within NOMINEE_PGBOUNCER_ADMIN_TIMEOUT. It is retried while the console is
+// not ready, but neither when PgBouncer refuses it nor when it times out.
+func (p *PgBouncer) admin(command string) error {
+	var err error
+	for i := 0; i < defaultRetries; i++ {
+		ctx, cancel := context.WithTimeout(p.Ctx, p.config.Admin.Timeout)
+		_, err = p.console.ExecContext(ctx, command)
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if timedOut {
+			return fmt.Errorf("%s: %w after %v", command, errTimeout, p.config.Admin.Timeout)
+		}
+		if _, refused := err.(gopg.Error); refused {
+			break
+		}
+		log.Warnf("%s: console not ready, retry in %v: %v", command, defaultWaitRetry, err)
+		time.Sleep(defaultWaitRetry)
+	}
+	return fmt.Errorf("%s: %v", command, err)
+}
+
+func (p *PgBouncer) writeDatabases(leader *node.Spec) error {
+	info, err := os.Stat(p.config.ConfigFile)
+	if err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(p.config.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	var databases []string
+	if leader != nil {
+		for _, database := range p.config.Databases {
+			databases = append(databases, fmt.Sprintf("%s = host=%s port=%d", database, leader.Address, leader.Port))
+		}
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(p.config.ConfigFile), ".pgbouncer-*.ini")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(rewriteDatabases(string(content), databases)); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p.config.ConfigFile)
+}
+
+// rewriteDatabases replaces the [databases] section of the ini content and keeps the other sections as they are.
+func rewriteDatabases(content string, databases []string) string {
+	section := append([]string{databasesSection}, databases...)
+	section = append(section, "")
+
+	var lines []string
+	inDatabases, rewritten := false, false
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			inDatabases = strings.EqualFold(trimmed, databasesSection)
+			if inDatabases {
+				lines = append(lines, section...)
+				rewritten = true
+				continue
+			}
+		}
+		if !inDatabases {
+			lines = append(lines, line)
+		}
+	}
+
+	if !rewritten {
+		lines = append(section, lines...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
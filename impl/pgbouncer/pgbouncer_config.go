@@ -0,0 +1,86 @@
+package pgbouncer
+
+import (
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"strings"
+	"time"
+)
+
+// ConfigLoader ...
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	ConfigFile string
+	ExecFile   string
+	Databases  []string
+	Admin      Admin
+}
+
+// Admin is where and as whom to reach the PgBouncer admin console.
+type Admin struct {
+	Address  string
+	Port     int64
+	Username string
+	Password string
+	// Timeout bounds each admin command, PAUSE waits for the clients to release their server connections.
+	Timeout time.Duration
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{BasicConfig: config.NewBasicConfig()}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+
+	config.SetDefault("NOMINEE_PGBOUNCER_CONFIG_FILE", "/etc/pgbouncer/pgbouncer.ini")
+	config.SetDefault("NOMINEE_PGBOUNCER_EXEC_FILE", "/usr/bin/pgbouncer")
+	config.SetDefault("NOMINEE_PGBOUNCER_DATABASES", "*")
+	config.SetDefault("NOMINEE_PGBOUNCER_ADMIN_ADDRESS", "127.0.0.1")
+	config.SetDefault("NOMINEE_PGBOUNCER_ADMIN_PORT", 6432)
+	config.SetDefault("NOMINEE_PGBOUNCER_ADMIN_USERNAME", "pgbouncer")
+	config.SetDefault("NOMINEE_PGBOUNCER_ADMIN_TIMEOUT", "5s")
+
+	conf.ConfigFile = config.GetStringOrPanic("NOMINEE_PGBOUNCER_CONFIG_FILE")
+	conf.ExecFile = config.GetStringOrPanic("NOMINEE_PGBOUNCER_EXEC_FILE")
+	conf.Databases = parseDatabases(config.GetStringOrPanic("NOMINEE_PGBOUNCER_DATABASES"))
+	conf.Admin.Address = config.GetStringOrPanic("NOMINEE_PGBOUNCER_ADMIN_ADDRESS")
+	conf.Admin.Port = int64(config.GetIntOrPanic("NOMINEE_PGBOUNCER_ADMIN_PORT"))
+	conf.Admin.Username = config.GetStringOrPanic("NOMINEE_PGBOUNCER_ADMIN_USERNAME")
+	conf.Admin.Password = config.GetString("NOMINEE_PGBOUNCER_ADMIN_PASSWORD")
+	conf.Admin.Timeout = getPositiveDurationOrPanic("NOMINEE_PGBOUNCER_ADMIN_TIMEOUT")
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	return conf
+}
+
+func parseDatabases(value string) []string {
+	var databases []string
+	for _, database := range strings.Split(value, ",") {
+		if database = strings.TrimSpace(database); database != "" {
+			databases = append(databases, database)
+		}
+	}
+	if len(databases) == 0 {
+		panic("You must specify the env var NOMINEE_PGBOUNCER_DATABASES to at least one database.")
+	}
+	return databases
+}
+
+func getPositiveDurationOrPanic(key string) time.Duration {
+	duration, err := time.ParseDuration(config.GetStringOrPanic(key))
+	if err != nil || duration <= 0 {
+		panic(fmt.Sprintf("You must specify the env var %s to a positive duration.", key))
+	}
+	return duration
+}
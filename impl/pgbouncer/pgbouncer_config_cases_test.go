@@ -0,0 +1,74 @@
+package pgbouncer_test
+
+type configurationExample struct {
+	description   string
+	cluster       string
+	domain        string
+	configFile    string
+	execFile      string
+	databases     string
+	adminAddress  string
+	adminPort     string
+	adminUsername string
+	adminPassword string
+	adminTimeout  string
+}
+
+var validExamples = []configurationExample{
+	{
+		description: "minimum configuration",
+		cluster:     "cluster-001",
+		domain:      "domain-002",
+	},
+	{
+		description:   "full configuration #1",
+		cluster:       "cluster-111",
+		domain:        "domain-542",
+		configFile:    "/usr/local/etc/pgbouncer.ini",
+		execFile:      "/usr/local/bin/pgbouncer",
+		databases:     "app",
+		adminAddress:  "10.0.0.12",
+		adminPort:     "6433",
+		adminUsername: "admin",
+		adminPassword: "$ecret",
+		adminTimeout:  "30s",
+	},
+	{
+		description:   "full configuration #2",
+		cluster:       "cluster-65888",
+		domain:        "domain/6565659",
+		configFile:    "/etc/pgbouncer.ini",
+		databases:     "orders, billing,*",
+		adminAddress:  "pgbouncer.priv",
+		adminUsername: "stats",
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "cluster name is missing",
+		domain:      "domain-002",
+	},
+	{
+		description: "domain name is missing",
+		cluster:     "cluster",
+	},
+	{
+		description: "databases are only separators",
+		cluster:     "cluster-001",
+		domain:      "domain-002",
+		databases:   " , ,",
+	},
+	{
+		description:  "the admin timeout is zero",
+		cluster:      "cluster-001",
+		domain:       "domain-002",
+		adminTimeout: "0s",
+	},
+	{
+		description:  "the admin timeout is not a duration",
+		cluster:      "cluster-001",
+		domain:       "domain-002",
+		adminTimeout: "5",
+	},
+}
@@ -0,0 +1,151 @@
+package pgbouncer_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/pgbouncer"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPgBouncerConfig_loads_configurations(t *testing.T) {
+	t.Logf("Given a valid PgBouncer configuration")
+	{
+		for i, example := range validExamples {
+			t.Run("", func(t *testing.T) {
+				defer tearsDown()
+				declareConfigurationExample(example)
+
+				t.Logf("\tTest %d: When load configuration and %s.", i, example.description)
+				{
+					loader := pgbouncer.NewConfigLoader()
+					loader.Load(context.TODO())
+					config := loader.GetSpec()
+
+					if config.Cluster != example.cluster {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, config.Cluster)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Cluster should be loaded.", testutils.Succeed)
+
+					if config.Domain != example.domain {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, config.Domain)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Domain should be loaded.", testutils.Succeed)
+
+					expected := orDefault(example.configFile, "/etc/pgbouncer/pgbouncer.ini")
+					if config.ConfigFile != expected {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.ConfigFile, expected <%s> but actual is <%s>", testutils.Failed, expected, config.ConfigFile)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.ConfigFile should be loaded.", testutils.Succeed)
+
+					expected = orDefault(example.execFile, "/usr/bin/pgbouncer")
+					if config.ExecFile != expected {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.ExecFile, expected <%s> but actual is <%s>", testutils.Failed, expected, config.ExecFile)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.ExecFile should be loaded.", testutils.Succeed)
+
+					var databases []string
+					for _, database := range strings.Split(orDefault(example.databases, "*"), ",") {
+						databases = append(databases, strings.TrimSpace(database))
+					}
+					if !reflect.DeepEqual(config.Databases, databases) {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Databases, expected <%v> but actual is <%v>", testutils.Failed, databases, config.Databases)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Databases should be loaded.", testutils.Succeed)
+
+					expected = orDefault(example.adminAddress, "127.0.0.1")
+					if config.Admin.Address != expected {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Admin.Address, expected <%s> but actual is <%s>", testutils.Failed, expected, config.Admin.Address)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Admin.Address should be loaded.", testutils.Succeed)
+
+					expected = orDefault(example.adminPort, "6432")
+					if strconv.Itoa(int(config.Admin.Port)) != expected {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Admin.Port, expected <%s> but actual is <%d>", testutils.Failed, expected, config.Admin.Port)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Admin.Port should be loaded.", testutils.Succeed)
+
+					expected = orDefault(example.adminUsername, "pgbouncer")
+					if config.Admin.Username != expected {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Admin.Username, expected <%s> but actual is <%s>", testutils.Failed, expected, config.Admin.Username)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Admin.Username should be loaded.", testutils.Succeed)
+
+					if config.Admin.Password != example.adminPassword {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Admin.Password, expected <%s> but actual is <%s>", testutils.Failed, example.adminPassword, config.Admin.Password)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Admin.Password should be loaded.", testutils.Succeed)
+
+					expected = orDefault(example.adminTimeout, "5s")
+					if config.Admin.Timeout.String() != expected {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Admin.Timeout, expected <%s> but actual is <%v>", testutils.Failed, expected, config.Admin.Timeout)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.Admin.Timeout should be loaded.", testutils.Succeed)
+				}
+			})
+		}
+	}
+}
+
+func TestPgBouncerConfig_panics_when_bad_configuration(t *testing.T) {
+	t.Logf("Given an invalid PgBouncer configuration")
+	{
+		for i, example := range invalidExamples {
+			t.Run("", func(t *testing.T) {
+				defer tearsDown()
+				declareConfigurationExample(example)
+
+				t.Logf("\tTest %d: When load configuration and %s.", i, example.description)
+				{
+					defer func() {
+						if r := recover(); r == nil {
+							t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+						} else {
+							t.Logf("\t\t%s Then the program must panic.", testutils.Succeed)
+						}
+					}()
+
+					pgbouncerConfig := pgbouncer.NewConfigLoader()
+					pgbouncerConfig.Load(context.TODO())
+				}
+
+			})
+		}
+	}
+}
+
+func orDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func declareConfigurationExample(example configurationExample) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_CONFIG_FILE", example.configFile)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_EXEC_FILE", example.execFile)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_DATABASES", example.databases)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_ADMIN_ADDRESS", example.adminAddress)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_ADMIN_PORT", example.adminPort)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_ADMIN_USERNAME", example.adminUsername)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_ADMIN_PASSWORD", example.adminPassword)
+	_ = os.Setenv("NOMINEE_PGBOUNCER_ADMIN_TIMEOUT", example.adminTimeout)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_CONFIG_FILE")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_EXEC_FILE")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_DATABASES")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_ADMIN_ADDRESS")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_ADMIN_PORT")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_ADMIN_USERNAME")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_ADMIN_PASSWORD")
+	_ = os.Unsetenv("NOMINEE_PGBOUNCER_ADMIN_TIMEOUT")
+}
@@ -0,0 +1,46 @@
+package pgbouncer
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"os/exec"
+)
+
+// Daemon runs the pgbouncer process.
+type Daemon interface {
+	Start(ctx context.Context) error
+	Done() base.DoneChan
+}
+
+// ExecDaemon runs pgbouncer in the foreground with the managed ini file.
+type ExecDaemon struct {
+	execFile   string
+	configFile string
+	doneCh     chan struct{}
+}
+
+// NewExecDaemon ...
+func NewExecDaemon(execFile, configFile string) *ExecDaemon {
+	return &ExecDaemon{execFile: execFile, configFile: configFile, doneCh: make(chan struct{})}
+}
+
+// Start ...
+func (d *ExecDaemon) Start(ctx context.Context) error {
+	start := exec.CommandContext(ctx, d.execFile, d.configFile)
+	start.Stdout, start.Stderr = log.Writer(), log.Writer()
+	if err := start.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		err := start.Wait()
+		log.Warnf("pgbouncer has returned: %v", err)
+		close(d.doneCh)
+	}()
+	return nil
+}
+
+// Done ...
+func (d *ExecDaemon) Done() base.DoneChan {
+	return d.doneCh
+}
@@ -0,0 +1,284 @@
+package pgbouncer_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/impl/pgbouncer"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+const iniExample = `[databases]
+; the routes are managed by nominee
+app = host=old.postgres.priv port=5432
+
+[pgbouncer]
+listen_addr = *
+listen_port = 6432
+admin_users = pgbouncer
+`
+
+var (
+	leader   = node.Spec{Name: "postgres-01", Address: "node01.postgres.priv", Port: 5432}
+	follower = node.Spec{Name: "postgres-02", Address: "node02.postgres.priv", Port: 5433}
+)
+
+func newPgBouncer(t *testing.T, databases ...string) (*pgbouncer.PgBouncer, *mock.PgBouncerConsole, *mock.PgBouncerDaemon, string) {
+	ini := filepath.Join(t.TempDir(), "pgbouncer.ini")
+	if err := ioutil.WriteFile(ini, []byte(iniExample), 0640); err != nil {
+		t.Fatalf("\t\t%s FAIL: WriteFile, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+
+	console := mock.NewPgBouncerConsole(t)
+	t.Cleanup(func() { _ = console.Close() })
+
+	proxy := pgbouncer.NewPgBouncer(&mock.PgBouncerConfigSpec{ConfigSpec: &pgbouncer.ConfigSpec{
+		BasicConfig: &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		ConfigFile:  ini,
+		Databases:   databases,
+		Admin:       pgbouncer.Admin{Address: "127.0.0.1", Port: console.Port(), Username: "pgbouncer", Timeout: time.Second},
+	}})
+	daemon := mock.NewPgBouncerDaemon()
+	proxy.Daemon = daemon
+	t.Cleanup(func() { proxy.Stonith(context.Background()) })
+	return proxy, console, daemon, ini
+}
+
+func readIni(t *testing.T, ini string) string {
+	content, err := ioutil.ReadFile(ini)
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: ReadFile, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	return string(content)
+}
+
+func TestPgBouncer_starts_with_the_databases_on_the_leader(t *testing.T) {
+	t.Logf("Given a PgBouncer proxy not started yet")
+	{
+		proxy, console, daemon, ini := newPgBouncer(t, "app", "*")
+		t.Logf("\tWhen it publishes the first leader")
+		{
+			if err := proxy.Publish(&leader, &follower); err != nil {
+				t.Fatalf("\t\t%s FAIL: Publish, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			expected := `[databases]
+app = host=node01.postgres.priv port=5432
+* = host=node01.postgres.priv port=5432
+
+[pgbouncer]
+listen_addr = *
+listen_port = 6432
+admin_users = pgbouncer
+`
+			if actual := readIni(t, ini); actual != expected {
+				t.Fatalf("\t\t%s FAIL: ini file, expected <%s> but actual is <%s>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the [databases] section must point to the leader and the rest must be kept.", testutils.Succeed)
+
+			if daemon.StartHits != 1 {
+				t.Fatalf("\t\t%s FAIL: Daemon.Start, expected <%d> but actual is <%d>", testutils.Failed, 1, daemon.StartHits)
+			}
+			t.Logf("\t\t%s Then the daemon must be started.", testutils.Succeed)
+
+			if len(console.Commands()) != 0 {
+				t.Fatalf("\t\t%s FAIL: console commands, expected none but actual is <%v>", testutils.Failed, console.Commands())
+			}
+			t.Logf("\t\t%s Then the admin console must not be used.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPgBouncer_pauses_reloads_and_resumes_when_the_leader_changes(t *testing.T) {
+	t.Logf("Given a started PgBouncer proxy")
+	{
+		proxy, console, _, ini := newPgBouncer(t, "app")
+		_ = proxy.Publish(&leader)
+
+		t.Logf("\tWhen the follower becomes the leader")
+		{
+			if err := proxy.Publish(&follower, &leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Publish, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			expected := []string{"PAUSE", "RELOAD", "RESUME"}
+			if actual := console.Commands(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: console commands, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the clients must be paused, the config reloaded and the clients resumed.", testutils.Succeed)
+
+			expectedIni := "[databases]\napp = host=node02.postgres.priv port=5433\n\n[pgbouncer]\nlisten_addr = *\nlisten_port = 6432\nadmin_users = pgbouncer\n"
+			if actual := readIni(t, ini); actual != expectedIni {
+				t.Fatalf("\t\t%s FAIL: ini file, expected <%s> but actual is <%s>", testutils.Failed, expectedIni, actual)
+			}
+			t.Logf("\t\t%s Then the [databases] section must point to the new leader.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPgBouncer_does_nothing_when_the_leader_is_the_same(t *testing.T) {
+	t.Logf("Given a started PgBouncer proxy")
+	{
+		proxy, console, _, _ := newPgBouncer(t, "app")
+		_ = proxy.Publish(&leader)
+
+		t.Logf("\tWhen it publishes the same leader with other followers")
+		{
+			_ = proxy.Publish(&leader, &follower)
+			if len(console.Commands()) != 0 {
+				t.Fatalf("\t\t%s FAIL: console commands, expected none but actual is <%v>", testutils.Failed, console.Commands())
+			}
+			t.Logf("\t\t%s Then the admin console must not be used.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPgBouncer_holds_the_clients_while_there_is_no_leader(t *testing.T) {
+	t.Logf("Given a started PgBouncer proxy")
+	{
+		proxy, console, _, ini := newPgBouncer(t, "app")
+		_ = proxy.Publish(&leader)
+		before := readIni(t, ini)
+
+		t.Logf("\tWhen the leader is lost")
+		{
+			_ = proxy.Publish(&node.Spec{}, &follower)
+			_ = proxy.Publish(nil, &follower)
+
+			expected := []string{"PAUSE"}
+			if actual := console.Commands(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: console commands, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the clients must be paused once.", testutils.Succeed)
+
+			if actual := readIni(t, ini); actual != before {
+				t.Fatalf("\t\t%s FAIL: ini file, expected <%s> but actual is <%s>", testutils.Failed, before, actual)
+			}
+			t.Logf("\t\t%s Then the ini file must be kept.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the same leader comes back")
+		{
+			_ = proxy.Publish(&leader, &follower)
+
+			expected := []string{"PAUSE", "RELOAD", "RESUME"}
+			if actual := console.Commands(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: console commands, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the clients must be resumed without a second pause.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPgBouncer_keeps_the_clients_paused_when_the_reload_fails(t *testing.T) {
+	t.Logf("Given a started PgBouncer proxy with a console failing to reload")
+	{
+		proxy, console, _, _ := newPgBouncer(t, "app")
+		_ = proxy.Publish(&leader)
+		console.Reply(func(command string) error {
+			if command == "RELOAD" {
+				return errors.New("reload failed")
+			}
+			return nil
+		})
+
+		t.Logf("\tWhen the leader changes")
+		{
+			if err := proxy.Publish(&follower); err == nil {
+				t.Fatalf("\t\t%s FAIL: Publish, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must fail.", testutils.Succeed)
+
+			expected := []string{"PAUSE", "RELOAD"}
+			if actual := console.Commands(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: console commands, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the clients must stay paused.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the console recovers and the leader is published again")
+		{
+			console.Reply(func(string) error { return nil })
+			if err := proxy.Publish(&follower); err != nil {
+				t.Fatalf("\t\t%s FAIL: Publish, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			expected := []string{"PAUSE", "RELOAD", "RELOAD", "RESUME"}
+			if actual := console.Commands(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: console commands, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the config must be reloaded and the clients resumed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPgBouncer_is_done_when_the_daemon_stops(t *testing.T) {
+	t.Logf("Given a started PgBouncer proxy")
+	{
+		proxy, _, daemon, _ := newPgBouncer(t, "app")
+		_ = proxy.Publish(&leader)
+
+		t.Logf("\tWhen the daemon stops")
+		{
+			close(daemon.DoneChan)
+			select {
+			case <-proxy.Done():
+			case <-time.After(time.Second):
+				t.Fatalf("\t\t%s FAIL: Done, expected to be closed but actual is not", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then the proxy must be done.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPgBouncer_kills_the_clients_when_the_pause_times_out(t *testing.T) {
+	t.Logf("Given a started PgBouncer proxy with clients never releasing their server connections")
+	{
+		proxy, console, _, ini := newPgBouncer(t, "app", "billing")
+		_ = proxy.Publish(&leader)
+		released := make(chan struct{})
+		defer close(released)
+		console.Reply(func(command string) error {
+			if command == "PAUSE" {
+				<-released
+			}
+			return nil
+		})
+
+		t.Logf("\tWhen the follower becomes the leader")
+		{
+			start := time.Now()
+			if err := proxy.Publish(&follower, &leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Publish, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Fatalf("\t\t%s FAIL: Publish, expected to give up the pause after the timeout but it took <%v>", testutils.Failed, elapsed)
+			}
+			t.Logf("\t\t%s Then it must not wait for the clients longer than the timeout.", testutils.Succeed)
+
+			expected := []string{"PAUSE", "KILL app", "KILL billing", "RELOAD", "RESUME"}
+			if actual := console.Commands(); !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("\t\t%s FAIL: console commands, expected <%v> but actual is <%v>", testutils.Failed, expected, actual)
+			}
+			t.Logf("\t\t%s Then the clients must be killed, the config reloaded and the databases resumed.", testutils.Succeed)
+
+			expectedIni := "[databases]\napp = host=node02.postgres.priv port=5433\nbilling = host=node02.postgres.priv port=5433\n\n[pgbouncer]\nlisten_addr = *\nlisten_port = 6432\nadmin_users = pgbouncer\n"
+			if actual := readIni(t, ini); actual != expectedIni {
+				t.Fatalf("\t\t%s FAIL: ini file, expected <%s> but actual is <%s>", testutils.Failed, expectedIni, actual)
+			}
+			t.Logf("\t\t%s Then the [databases] section must point to the new leader.", testutils.Succeed)
+		}
+	}
+}
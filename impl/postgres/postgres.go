@@ -2,17 +2,22 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	gopg "github.com/go-pg/pg/v10"
 	"github.com/sirupsen/logrus"
 	"github/mlyahmed.io/nominee/pkg/base"
+	"github/mlyahmed.io/nominee/pkg/metrics"
 	"github/mlyahmed.io/nominee/pkg/node"
 	"io/ioutil"
+	"net"
 	"os"
-	"os/exec"
 	"os/user"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 )
 
@@ -22,21 +27,28 @@ type role int
 const (
 	started status = iota
 	stopped
+	stopping
 
 	primary role = iota
 	standby
 	recovery
 	virgin
+	demoted
 )
 
 const (
 	defaultWaitRetry = time.Second * 2
 	defaultRetries   = 3
 	postgres         = "postgres"
+	slotPrefix       = "nominee_"
+	asideSuffix      = ".aside-"
 )
 
+// SlotCheckInterval is how often a standby checks whether the leader has created its slot yet.
+var SlotCheckInterval = 5 * time.Second
+
 var (
-	log *logrus.Entry
+	log = logrus.NewEntry(logrus.StandardLogger())
 )
 
 // OSUser ...
@@ -53,52 +65,91 @@ type DBUser struct {
 	Password string
 }
 
+// DB is the SQL layer of the node. *gopg.DB satisfies it.
+type DB interface {
+	ExecContext(ctx context.Context, query interface{}, params ...interface{}) (gopg.Result, error)
+	QueryContext(ctx context.Context, model, query interface{}, params ...interface{}) (gopg.Result, error)
+	QueryOneContext(ctx context.Context, model, query interface{}, params ...interface{}) (gopg.Result, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
 // Postgres ...
 type Postgres struct {
 	*node.Spec
-	cluster     string
-	domain      string
-	doneCh      chan struct{}
-	osUser      OSUser
-	replicaUser DBUser
-	dbaUser     DBUser
-	pgdata      string
-	status      status
-	role        role
-	db          *gopg.DB
-	leader      node.Spec
+	DB               DB
+	Daemon           Daemon
+	Executor         OSExecutor
+	cluster          string
+	domain           string
+	doneCh           chan struct{}
+	osUser           OSUser
+	replicaUser      DBUser
+	dbaUser          DBUser
+	pgdata           string
+	status           status
+	role             role
+	leader           node.Spec
+	maxLag           int64
+	maxLagOnFailover int64
+	synchronousMode  string
+	syncStandby      string
+	slotsGracePeriod time.Duration
+	orphanSlots      map[string]time.Time
+	pgdataRetention  int
+	pgdataAside      string
+	bootstrapper     Bootstrapper
+	cloner           Bootstrapper
+	cancelSlotWait   context.CancelFunc
+	exited           chan struct{}
 }
 
 // NewPostgres ...
-func NewPostgres(cl ConfigLoader) node.Node {
+func NewPostgres(cl ConfigLoader) *Postgres {
 	cl.Load(context.Background())
 	config := cl.GetSpec()
-	osu, _ := user.Lookup(postgres)
 	pg := &Postgres{
-		Spec:    &cl.GetSpec().NodeSpec,
-		doneCh:  make(chan struct{}),
-		cluster: config.Cluster,
-		domain:  config.Domain,
+		Spec:     &cl.GetSpec().NodeSpec,
+		DB:       gopg.Connect(&gopg.Options{User: postgres, Password: config.Postgres.Password}),
+		Daemon:   NewEntrypointDaemon(postgres),
+		Executor: NewSuExecutor(postgres),
+		doneCh:   make(chan struct{}),
+		cluster:  config.Cluster,
+		domain:   config.Domain,
 		osUser: OSUser{
 			username: postgres,
-			homeDir:  osu.HomeDir,
 		},
 		replicaUser: config.Replicator,
 		dbaUser: DBUser{
 			Username: postgres,
 			Password: config.Postgres.Password,
 		},
-		pgdata: os.Getenv("PGDATA"),
-		status: stopped,
+		pgdata:           os.Getenv("PGDATA"),
+		status:           stopped,
+		maxLag:           config.MaxLagOnSwitchover,
+		maxLagOnFailover: config.MaxLagOnFailover,
+		synchronousMode:  config.SynchronousMode,
+		slotsGracePeriod: config.SlotsGracePeriod,
+		orphanSlots:      make(map[string]time.Time),
+		pgdataRetention:  config.PgDataRetention,
+		pgdataAside:      config.PgDataAside,
+	}
+	if pg.pgdataAside == "" {
+		pg.pgdataAside = filepath.Dir(pg.pgdata)
 	}
 
 	pg.Name = fmt.Sprintf("%s-%d", pg.Name, time.Now().Nanosecond())
 	pg.role = pg.lookupCurrentRole()
-	pg.osUser.uid, _ = strconv.Atoi(osu.Uid)
-	pg.osUser.gid, _ = strconv.Atoi(osu.Gid)
-	pg.db = gopg.Connect(&gopg.Options{User: pg.dbaUser.Username, Password: pg.dbaUser.Password})
+	pg.bootstrapper, pg.cloner = pg.newBootstrappers(config)
 
-	_ = pg.createPgPassFile()
+	if osu, err := user.Lookup(postgres); err == nil {
+		pg.osUser.homeDir = osu.HomeDir
+		pg.osUser.uid, _ = strconv.Atoi(osu.Uid)
+		pg.osUser.gid, _ = strconv.Atoi(osu.Gid)
+		_ = pg.createPgPassFile()
+	} else {
+		log.Warnf("postgres: no %s user: %v\n", postgres, err)
+	}
 
 	log = logrus.WithFields(logrus.Fields{"daemon": pg.GetDaemonName(), "node": pg.GetName()})
 	return pg
@@ -113,7 +164,7 @@ func (pg *Postgres) GetDaemonName() string {
 func (pg *Postgres) Lead(context context.Context, myself node.Spec) error {
 	log.Infof("postgres: promote to primary as %v ...\n", myself.Name)
 	pg.leader = myself
-	defer pg.db.Close()
+	pg.stopAwaitingSlot()
 
 	if err := pg.start(context); err != nil {
 		return err
@@ -133,7 +184,7 @@ func (pg *Postgres) Lead(context context.Context, myself node.Spec) error {
 			return err
 		}
 
-	} else if pg.role == standby {
+	} else if pg.role == standby || pg.role == demoted {
 
 		if err := pg.execOSCmd(context, "pg_ctl promote", 0); err != nil {
 			return err
@@ -141,6 +192,12 @@ func (pg *Postgres) Lead(context context.Context, myself node.Spec) error {
 
 	}
 
+	if pg.Synchronous() {
+		if err := pg.resetSyncStandby(context); err != nil {
+			return err
+		}
+	}
+
 	pg.role = primary
 	return nil
 }
@@ -152,17 +209,19 @@ func (pg *Postgres) Follow(ctx context.Context, leader node.Spec) error {
 
 	if pg.role == virgin {
 
-		if err := pg.baseBackup(ctx, leader); err != nil {
+		if err := pg.bootstrapper.Bootstrap(ctx, leader); err != nil {
 			return err
 		}
 
 		if err := pg.start(ctx); err != nil {
 			return err
 		}
+		_ = pg.setPrimaryConnInfo(ctx)
+		_ = pg.reloadConf(ctx)
 
-	} else if pg.role == primary {
+	} else if pg.role == primary || pg.role == demoted {
 
-		if err := pg.execOSCmd(ctx, fmt.Sprintf("pg_rewind --source-server='host=%s port=5432 user=%s' --target-pgdata=%s", pg.leader.Address, pg.dbaUser.Username, pg.pgdata), 3); err != nil {
+		if err := pg.rejoin(ctx); err != nil {
 			return err
 		}
 		_ = pg.execOSCmd(ctx, fmt.Sprintf("touch %s/standby.signal", pg.pgdata), 0)
@@ -185,7 +244,253 @@ func (pg *Postgres) Follow(ctx context.Context, leader node.Spec) error {
 // Stonith ...
 func (pg *Postgres) Stonith(context context.Context) {
 	log.Infof("postgres: stonithing... \n")
+	pg.stopAwaitingSlot()
 	_ = pg.execOSCmd(context, "pg_ctl stop", 0)
+	_ = pg.DB.Close()
+}
+
+// ActualRole asks Postgres whether it is in recovery.
+func (pg *Postgres) ActualRole(ctx context.Context) (node.Role, error) {
+	if pg.status != started {
+		return node.Unknown, errors.New("postgres: not started")
+	}
+	var inRecovery bool
+	if _, err := pg.DB.QueryOneContext(ctx, gopg.Scan(&inRecovery), "SELECT pg_is_in_recovery()"); err != nil {
+		return node.Unknown, err
+	}
+	if inRecovery {
+		return node.Replica, nil
+	}
+	return node.Primary, nil
+}
+
+// CheckCandidate makes sure the candidate streams from this node and lags by at most NOMINEE_POSTGRES_MAX_LAG_ON_SWITCHOVER bytes.
+// In synchronous mode, the candidate must be the synchronous standby.
+func (pg *Postgres) CheckCandidate(ctx context.Context, candidate node.Spec) error {
+	if pg.Synchronous() && candidate.Name != pg.syncStandby {
+		return fmt.Errorf("postgres: %s is not the synchronous standby", candidate.Name)
+	}
+
+	addresses, err := net.LookupHost(candidate.Address)
+	if err != nil {
+		return err
+	}
+
+	var replicas []struct {
+		ClientAddr string
+		State      string
+		Lag        int64
+	}
+	if _, err := pg.DB.QueryContext(ctx, &replicas, "SELECT host(client_addr) AS client_addr, state, "+
+		"COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), flush_lsn), -1)::bigint AS lag FROM pg_stat_replication"); err != nil {
+		return err
+	}
+
+	for _, replica := range replicas {
+		for _, address := range addresses {
+			if replica.ClientAddr != address {
+				continue
+			}
+			if replica.State != "streaming" || replica.Lag < 0 || replica.Lag > pg.maxLag {
+				return fmt.Errorf("postgres: %s is %s and lags by %d bytes", candidate.Name, replica.State, replica.Lag)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("postgres: %s does not replicate from %s", candidate.Name, pg.GetName())
+}
+
+// Demote checkpoints, stops cleanly so that the standbys receive all the WAL, then rewinds and restarts as a standby on
+// Follow, in case the new leader has diverged.
+func (pg *Postgres) Demote(ctx context.Context) error {
+	log.Infof("postgres: demoting... \n")
+	if err := pg.execDBCmd(ctx, "CHECKPOINT"); err != nil {
+		return err
+	}
+
+	pg.status = stopping
+	if err := pg.execOSCmd(ctx, "pg_ctl stop -m fast -w", 0); err != nil {
+		pg.status = started
+		return err
+	}
+	select {
+	case <-pg.exited:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if err := pg.execOSCmd(ctx, fmt.Sprintf("touch %s/standby.signal", pg.pgdata), 0); err != nil {
+		return err
+	}
+	pg.role = demoted
+	return nil
+}
+
+// Metadata tells the WAL positions received and replayed by the standby, they are zero unless it is a started standby.
+func (pg *Postgres) Metadata(ctx context.Context) (node.Metadata, error) {
+	metadata := node.Metadata{}
+	if pg.status != started {
+		return metadata, nil
+	}
+	_, err := pg.DB.QueryOneContext(ctx, &metadata, "SELECT "+
+		"COALESCE(pg_wal_lsn_diff(pg_last_wal_receive_lsn(), '0/0'), 0)::bigint AS received_lsn, "+
+		"COALESCE(pg_wal_lsn_diff(pg_last_wal_replay_lsn(), '0/0'), 0)::bigint AS replayed_lsn")
+	return metadata, err
+}
+
+// CheckEligibility fails when the node lags behind the most up to date of the standbys by more than NOMINEE_POSTGRES_MAX_LAG_ON_FAILOVER bytes.
+func (pg *Postgres) CheckEligibility(ctx context.Context, standbys []node.Spec) error {
+	metadata, err := pg.Metadata(ctx)
+	if err != nil {
+		return err
+	}
+	if lag := metadata.Lag(standbys...); lag > pg.maxLagOnFailover {
+		return fmt.Errorf("postgres: %s lags by %d bytes behind the most up to date standby", pg.GetName(), lag)
+	}
+	return nil
+}
+
+// Synchronous tells whether NOMINEE_POSTGRES_SYNCHRONOUS_MODE is on or strict.
+func (pg *Postgres) Synchronous() bool {
+	return pg.synchronousMode != SyncOff
+}
+
+// Synchronize keeps the current synchronous standby while it streams, otherwise it picks the first streaming follower.
+// Without any, the primary replicates asynchronously, unless the mode is strict where the writes are blocked.
+func (pg *Postgres) Synchronize(ctx context.Context, followers []node.Spec) (string, error) {
+	var replicas []struct {
+		ApplicationName string
+		State           string
+		SyncState       string
+	}
+	if _, err := pg.DB.QueryContext(ctx, &replicas, "SELECT application_name, state, sync_state FROM pg_stat_replication"); err != nil {
+		return "", err
+	}
+
+	streaming := make(map[string]string)
+	for _, replica := range replicas {
+		if replica.State == "streaming" {
+			streaming[replica.ApplicationName] = replica.SyncState
+		}
+	}
+
+	chosen := ""
+	for _, follower := range followers {
+		if _, ok := streaming[follower.Name]; !ok {
+			continue
+		}
+		if chosen == "" || follower.Name == pg.syncStandby {
+			chosen = follower.Name
+		}
+	}
+
+	if chosen == "" {
+		if pg.synchronousMode == SyncStrict {
+			if err := pg.setSyncStandby(ctx, "*"); err != nil {
+				return "", err
+			}
+			return "", errors.New("postgres: no synchronous standby available, the writes are blocked")
+		}
+		return "", pg.setSyncStandby(ctx, "")
+	}
+
+	if err := pg.setSyncStandby(ctx, chosen); err != nil {
+		return "", err
+	}
+	if streaming[chosen] != "sync" {
+		return "", fmt.Errorf("postgres: %s is not synchronous yet", chosen)
+	}
+	return chosen, nil
+}
+
+// KeepSlots creates a physical replication slot per follower, so that the primary keeps the WAL they still need. The
+// slots of the nodes gone for longer than NOMINEE_POSTGRES_SLOTS_GRACE_PERIOD seconds are dropped.
+func (pg *Postgres) KeepSlots(ctx context.Context, followers []node.Spec) error {
+	var slots []struct {
+		SlotName string
+		Active   bool
+	}
+	if _, err := pg.DB.QueryContext(ctx, &slots, "SELECT slot_name, active FROM pg_replication_slots "+
+		"WHERE slot_type = 'physical' AND slot_name LIKE ?", slotPrefix+"%"); err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool)
+	for _, follower := range followers {
+		wanted[slotName(follower.Name)] = true
+	}
+
+	for _, slot := range slots {
+		if wanted[slot.SlotName] {
+			delete(wanted, slot.SlotName)
+			delete(pg.orphanSlots, slot.SlotName)
+			continue
+		}
+		since, ok := pg.orphanSlots[slot.SlotName]
+		if !ok {
+			log.Infof("postgres: slot %s is orphan, dropped in %v\n", slot.SlotName, pg.slotsGracePeriod)
+			pg.orphanSlots[slot.SlotName] = time.Now()
+			continue
+		}
+		if slot.Active || time.Since(since) < pg.slotsGracePeriod {
+			continue
+		}
+		if _, err := pg.DB.ExecContext(ctx, "SELECT pg_drop_replication_slot(?)", slot.SlotName); err != nil {
+			return err
+		}
+		log.Infof("postgres: slot %s dropped\n", slot.SlotName)
+		delete(pg.orphanSlots, slot.SlotName)
+	}
+
+	for slot := range wanted {
+		if _, err := pg.DB.ExecContext(ctx, "SELECT pg_create_physical_replication_slot(?, true)", slot); err != nil {
+			return err
+		}
+		log.Infof("postgres: slot %s created\n", slot)
+	}
+	return nil
+}
+
+// CheckInitialized refuses to initdb a cluster already initialized, and to lead it with the data of another one.
+func (pg *Postgres) CheckInitialized(ctx context.Context, systemID string) error {
+	if systemID == "" {
+		return nil
+	}
+	if pg.role == virgin {
+		return fmt.Errorf("postgres: the cluster is already initialized with %s, refuse to initdb", systemID)
+	}
+	actual, err := pg.SystemID(ctx)
+	if err != nil {
+		return err
+	}
+	if actual != systemID {
+		return fmt.Errorf("postgres: the cluster is initialized with %s but the data of %s are of %s", systemID, pg.GetName(), actual)
+	}
+	return nil
+}
+
+// SystemID reads the database system identifier from the control file, it is empty before initdb.
+func (pg *Postgres) SystemID(ctx context.Context) (string, error) {
+	if pg.role == virgin {
+		return "", nil
+	}
+	output, err := pg.Executor.Output(ctx, fmt.Sprintf("pg_controldata -D %s", pg.pgdata))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Database system identifier:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Database system identifier:")), nil
+		}
+	}
+	return "", errors.New("postgres: no database system identifier in the control file")
+}
+
+// CloneFrom has a new replica take its base backup from one of the members tagged clonefrom.
+func (pg *Postgres) CloneFrom(members []node.Spec) {
+	if cloner, ok := pg.bootstrapper.(*CloneFromBootstrapper); ok {
+		cloner.SetMembers(members)
+	}
 }
 
 // Stop ...
@@ -198,16 +503,19 @@ func (pg *Postgres) start(context context.Context) error {
 		return nil
 	}
 
+	exited := make(chan struct{})
+	pg.exited = exited
+	initdb, logger := pg.role == virgin, log
 	go func() {
-		if pg.role == virgin {
+		defer close(exited)
+		if initdb {
 			_ = os.Setenv("POSTGRES_INITDB_ARGS", fmt.Sprintf("--data-checksums %s", os.Getenv("POSTGRES_INITDB_ARGS")))
 		}
-		start := exec.CommandContext(context, "/docker-entrypoint.sh", pg.osUser.username)
-		start.Stdout, start.Stderr = log.Writer(), log.Writer()
-
-		_ = start.Run() //FIXME:
-		log.Warnf("Run command has returned.")
-		pg.doneCh <- struct{}{}
+		_ = pg.Daemon.Run(context) //FIXME:
+		logger.Warnf("Run command has returned.")
+		if pg.status != stopping { // Unless it is demoted
+			pg.doneCh <- struct{}{}
+		}
 		pg.status = stopped //When the Run returns it means the service is stopped.
 	}()
 
@@ -250,9 +558,96 @@ func (pg *Postgres) authorizeReplication(context context.Context) error {
 	return pg.execOSCmd(context, fmt.Sprintf("echo '%s' >> %s", line, path), 0)
 }
 
-func (pg *Postgres) baseBackup(context context.Context, leader node.Spec) error {
-	cmd := fmt.Sprintf("pg_basebackup -h %s -U %s -p 5432 -D %s -Fp -Xs -P -R", leader.Address, pg.replicaUser.Username, pg.pgdata)
-	return pg.execOSCmd(context, cmd, defaultRetries)
+// newBootstrappers returns the bootstrap method of the node, and pg_basebackup to re-clone a former primary. The base
+// backup of a new replica is taken from the members tagged clonefrom when there are some.
+func (pg *Postgres) newBootstrappers(config *ConfigSpec) (Bootstrapper, Bootstrapper) {
+	executor := ExecutorFunc(pg.execOSCmd)
+	bootstrapper, err := NewBootstrapper(config, pg.pgdata, executor)
+	if err != nil {
+		panic(err)
+	}
+	if config.BootstrapMethod == BaseBackup {
+		bootstrapper = NewCloneFromBootstrapper(bootstrapper)
+	}
+	clone := *config
+	clone.BootstrapMethod, clone.BootstrapCommand = BaseBackup, ""
+	cloner, err := NewBootstrapper(&clone, pg.pgdata, executor)
+	if err != nil {
+		panic(err)
+	}
+	return bootstrapper, cloner
+}
+
+// rejoin brings the former primary in line with the leader. It rewinds it, or re-clones it when the rewind fails, on
+// timelines it cannot reconcile for instance.
+func (pg *Postgres) rejoin(ctx context.Context) error {
+	log.Infof("postgres: rewinding from %s...\n", pg.leader.Name)
+	err := pg.execOSCmd(ctx, fmt.Sprintf("pg_rewind --source-server='host=%s port=5432 user=%s' --target-pgdata=%s", pg.leader.Address, pg.dbaUser.Username, pg.pgdata), 3)
+	metrics.RecoveryStep(metrics.Rewind, err)
+	if err == nil {
+		return nil
+	}
+
+	log.Warnf("postgres: failed to rewind: %v. Re-cloning from %s...\n", err, pg.leader.Name)
+	err = pg.moveDataAside()
+	metrics.RecoveryStep(metrics.MoveAside, err)
+	if err != nil {
+		log.Errorf("postgres: failed to move PGDATA aside: %v\n", err)
+		return err
+	}
+
+	err = pg.cloner.Bootstrap(ctx, pg.leader)
+	metrics.RecoveryStep(metrics.Reclone, err)
+	if err != nil {
+		log.Errorf("postgres: failed to re-clone from %s: %v\n", pg.leader.Name, err)
+		return err
+	}
+	log.Infof("postgres: re-cloned from %s\n", pg.leader.Name)
+	return nil
+}
+
+// moveDataAside moves the content of PGDATA into a new directory of NOMINEE_POSTGRES_PGDATA_ASIDE, next to PGDATA by
+// default. PGDATA itself may be a mount point which cannot be renamed. The entries are only renamed, so the aside
+// directory must be on the filesystem of PGDATA. Only the NOMINEE_POSTGRES_PGDATA_RETENTION most recent copies are kept.
+func (pg *Postgres) moveDataAside() error {
+	if pg.status == started {
+		return errors.New("postgres: cannot move PGDATA aside while started")
+	}
+	prefix := filepath.Join(pg.pgdataAside, filepath.Base(pg.pgdata)+asideSuffix)
+	aside := prefix + time.Now().Format("20060102T150405.000000000")
+	if err := os.Mkdir(aside, 0700); err != nil {
+		return err
+	}
+	entries, err := ioutil.ReadDir(pg.pgdata)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		err := os.Rename(filepath.Join(pg.pgdata, entry.Name()), filepath.Join(aside, entry.Name()))
+		if errors.Is(err, syscall.EXDEV) {
+			_ = os.Remove(aside)
+			return fmt.Errorf("postgres: %s is not on the filesystem of PGDATA, you must specify the env var "+
+				"NOMINEE_POSTGRES_PGDATA_ASIDE to a directory of the volume of PGDATA", pg.pgdataAside)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	log.Infof("postgres: PGDATA moved aside to %s\n", aside)
+
+	copies, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		return err
+	}
+	sort.Strings(copies)
+	for len(copies) > pg.pgdataRetention {
+		if err := os.RemoveAll(copies[0]); err != nil {
+			return err
+		}
+		log.Infof("postgres: %s removed\n", copies[0])
+		copies = copies[1:]
+	}
+	return nil
 }
 
 func (pg *Postgres) createReplicaUser(context context.Context) error {
@@ -267,6 +662,9 @@ func (pg *Postgres) createReplicaUser(context context.Context) error {
 }
 
 func (pg *Postgres) setPrimaryConnInfo(ctx context.Context) error {
+	if err := pg.setPrimarySlotName(ctx); err != nil {
+		return err
+	}
 	return pg.execDBCmd(ctx, fmt.Sprintf("ALTER SYSTEM SET primary_conninfo TO "+
 		"'user=replicator "+
 		"passfile=''/var/lib/postgresql/.pgpass'' "+
@@ -278,13 +676,117 @@ func (pg *Postgres) setPrimaryConnInfo(ctx context.Context) error {
 		"ssl_min_protocol_version=TLSv1.2 "+
 		"gssencmode=prefer "+
 		"krbsrvname=postgres "+
-		"target_session_attrs=any'", pg.leader.Address))
+		"application_name=%s "+
+		"target_session_attrs=any'", pg.leader.Address, pg.GetName()))
+}
+
+// setPrimarySlotName streams from the slot of the node only once the leader has created it, as the leader keeps the
+// slots of its followers at its own pace, when it keeps them at all. Until then, the standby streams without any slot.
+func (pg *Postgres) setPrimarySlotName(ctx context.Context) error {
+	pg.stopAwaitingSlot()
+	slot := slotName(pg.GetName())
+	if exists, err := pg.leaderHasSlot(ctx, pg.leader, slot); err != nil || !exists {
+		pg.awaitSlot(ctx, slot)
+		return pg.execDBCmd(ctx, "ALTER SYSTEM RESET primary_slot_name")
+	}
+	return pg.execDBCmd(ctx, fmt.Sprintf("ALTER SYSTEM SET primary_slot_name TO '%s'", slot))
+}
+
+// awaitSlot checks the leader until it has created the slot, then streams from it.
+func (pg *Postgres) awaitSlot(ctx context.Context, slot string) {
+	ctx, cancel := context.WithCancel(ctx)
+	pg.cancelSlotWait = cancel
+	leader := pg.leader
+	go func() {
+		defer cancel()
+		ticker := time.NewTicker(SlotCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			if exists, err := pg.leaderHasSlot(ctx, leader, slot); err != nil || !exists {
+				continue
+			}
+			if err := pg.execDBCmd(ctx, fmt.Sprintf("ALTER SYSTEM SET primary_slot_name TO '%s'", slot)); err != nil {
+				log.Warnf("postgres: failed to set the slot %s: %v\n", slot, err)
+				continue
+			}
+			if err := pg.reloadConf(ctx); err != nil {
+				log.Warnf("postgres: failed to reload the configuration: %v\n", err)
+				continue
+			}
+			log.Infof("postgres: streaming from the slot %s\n", slot)
+			return
+		}
+	}()
+}
+
+func (pg *Postgres) stopAwaitingSlot() {
+	if pg.cancelSlotWait != nil {
+		pg.cancelSlotWait()
+		pg.cancelSlotWait = nil
+	}
+}
+
+// leaderHasSlot tells whether the slot exists on the leader.
+func (pg *Postgres) leaderHasSlot(ctx context.Context, leader node.Spec, slot string) (bool, error) {
+	db := gopg.Connect(&gopg.Options{Addr: fmt.Sprintf("%s:5432", leader.Address), User: pg.dbaUser.Username, Password: pg.dbaUser.Password})
+	defer db.Close()
+	var exists bool
+	_, err := db.QueryOneContext(ctx, gopg.Scan(&exists), "SELECT count(*) > 0 FROM pg_replication_slots WHERE slot_name = ?", slot)
+	return exists, err
+}
+
+// resetSyncStandby starts a new primary with no synchronous standby, the writes are blocked right away in strict mode.
+func (pg *Postgres) resetSyncStandby(ctx context.Context) error {
+	pg.syncStandby = "-"
+	if pg.synchronousMode == SyncStrict {
+		return pg.setSyncStandby(ctx, "*")
+	}
+	return pg.setSyncStandby(ctx, "")
+}
+
+func (pg *Postgres) setSyncStandby(ctx context.Context, name string) error {
+	if name == pg.syncStandby {
+		return nil
+	}
+	names := name
+	if name != "" && name != "*" {
+		names = fmt.Sprintf(`"%s"`, name)
+	}
+	if err := pg.execDBCmd(ctx, fmt.Sprintf("ALTER SYSTEM SET synchronous_standby_names TO '%s'", names)); err != nil {
+		return err
+	}
+	if err := pg.reloadConf(ctx); err != nil {
+		return err
+	}
+	log.Infof("postgres: synchronous_standby_names set to '%s'\n", names)
+	pg.syncStandby = name
+	return nil
 }
 
 func (pg *Postgres) reloadConf(context context.Context) error {
 	return pg.execDBCmd(context, "select pg_reload_conf()")
 }
 
+// slotName turns the node name into a valid slot name: lower case letters, numbers and underscores only.
+func slotName(name string) string {
+	slot := []rune(slotPrefix)
+	for _, r := range strings.ToLower(name) {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			r = '_'
+		}
+		slot = append(slot, r)
+	}
+	if len(slot) > 63 {
+		slot = slot[:63]
+	}
+	return string(slot)
+}
+
 func (pg *Postgres) lookupCurrentRole() role {
 	if pg.isPgDataEmpty() {
 		return virgin
@@ -297,31 +799,17 @@ func (pg *Postgres) lookupCurrentRole() role {
 }
 
 func (pg *Postgres) execOSCmd(context context.Context, cmd string, retries int) error {
-	for i := retries; ; i-- {
-		command := exec.CommandContext(context, "su", "-c", cmd, pg.osUser.username)
-		command.Stdout, command.Stderr = log.Writer(), log.Writer()
-
-		if err := command.Run(); err != nil {
-			if i >= 0 {
-				time.Sleep(defaultWaitRetry)
-				continue
-			} else {
-				return err
-			}
-		}
-		break
-	}
-	return nil
+	return pg.Executor.Exec(context, cmd, retries)
 }
 
 func (pg *Postgres) execDBCmd(context context.Context, cmd string) error {
-	_, err := pg.db.ExecContext(context, cmd)
+	_, err := pg.DB.ExecContext(context, cmd)
 	return err
 }
 
 func (pg *Postgres) warmUp(context context.Context, retries int) error {
 	for i := retries; ; i-- {
-		if err := pg.db.Ping(context); err != nil {
+		if err := pg.DB.Ping(context); err != nil {
 			if i >= 0 {
 				time.Sleep(defaultWaitRetry)
 				continue
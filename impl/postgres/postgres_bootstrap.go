@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"sync"
+	"text/template"
+)
+
+// The bootstrap methods of a new replica.
+const (
+	// BaseBackup copies the PGDATA of the leader with pg_basebackup.
+	BaseBackup = "basebackup"
+	// PgBackRest restores the last backup of the cluster from the pgBackRest repository.
+	PgBackRest = "pgbackrest"
+	// WalG restores the last backup of the cluster from the WAL-G storage.
+	WalG = "walg"
+	// Script runs the NOMINEE_POSTGRES_BOOTSTRAP_COMMAND.
+	Script = "script"
+)
+
+// commands are the templates of the bootstrap methods, they are given a BootstrapParams.
+var commands = map[string]string{
+	BaseBackup: "pg_basebackup -h {{.Leader.Address}} -U {{.Replicator}} -p 5432 -D {{.PgData}} -Fp -Xs -P -R",
+	PgBackRest: "pgbackrest --stanza={{.Cluster}} --pg1-path={{.PgData}} --type=standby --delta restore",
+	WalG: "wal-g backup-fetch {{.PgData}} LATEST && touch {{.PgData}}/standby.signal && " +
+		"echo \"restore_command = 'wal-g wal-fetch %f %p'\" >> {{.PgData}}/postgresql.auto.conf",
+}
+
+// Executor runs the OS commands as the postgres user.
+type Executor interface {
+	Exec(ctx context.Context, cmd string, retries int) error
+}
+
+// ExecutorFunc ...
+type ExecutorFunc func(ctx context.Context, cmd string, retries int) error
+
+// Exec ...
+func (fn ExecutorFunc) Exec(ctx context.Context, cmd string, retries int) error {
+	return fn(ctx, cmd, retries)
+}
+
+// Bootstrapper builds the PGDATA of a new replica.
+type Bootstrapper interface {
+	// Bootstrap fills the empty PGDATA, so that the node starts as a standby of the leader.
+	Bootstrap(ctx context.Context, leader node.Spec) error
+}
+
+// BootstrapParams are what the bootstrap commands are made of.
+type BootstrapParams struct {
+	Cluster    string
+	Name       string
+	PgData     string
+	Replicator string
+	Leader     node.Spec
+}
+
+// CommandBootstrapper runs the command of its template.
+type CommandBootstrapper struct {
+	Method   string
+	Template *template.Template
+	Params   BootstrapParams
+	Executor Executor
+}
+
+// NewBootstrapper returns the NOMINEE_POSTGRES_BOOTSTRAP_METHOD of the node. The NOMINEE_POSTGRES_BOOTSTRAP_COMMAND
+// replaces the command of the method when set.
+func NewBootstrapper(conf *ConfigSpec, pgdata string, executor Executor) (Bootstrapper, error) {
+	text := conf.BootstrapCommand
+	if text == "" {
+		text = commands[conf.BootstrapMethod]
+	}
+	if text == "" {
+		return nil, fmt.Errorf("postgres: no command to bootstrap with %s", conf.BootstrapMethod)
+	}
+
+	tmpl, err := template.New(conf.BootstrapMethod).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &CommandBootstrapper{
+		Method:   conf.BootstrapMethod,
+		Template: tmpl,
+		Params: BootstrapParams{
+			Cluster:    conf.Cluster,
+			Name:       conf.NodeSpec.Name,
+			PgData:     pgdata,
+			Replicator: conf.Replicator.Username,
+		},
+		Executor: executor,
+	}, nil
+}
+
+// Bootstrap runs the command with the leader, it is retried as the leader may not accept the connections yet.
+func (b *CommandBootstrapper) Bootstrap(ctx context.Context, leader node.Spec) error {
+	cmd, err := b.Command(leader)
+	if err != nil {
+		return err
+	}
+	log.Infof("postgres: bootstrap with %s...\n", b.Method)
+	return b.Executor.Exec(ctx, cmd, defaultRetries)
+}
+
+// Command returns the command to bootstrap from the leader.
+func (b *CommandBootstrapper) Command(leader node.Spec) (string, error) {
+	params := b.Params
+	params.Leader = leader
+	cmd := &bytes.Buffer{}
+	if err := b.Template.Execute(cmd, params); err != nil {
+		return "", err
+	}
+	return cmd.String(), nil
+}
+
+// CloneFromBootstrapper bootstraps from the members tagged clonefrom rather than from the leader, so that the leader is
+// spared the copy. It falls back on the leader when there is none or when none of them succeeds.
+type CloneFromBootstrapper struct {
+	Bootstrapper
+	members []node.Spec
+	mutex   *sync.Mutex
+}
+
+// NewCloneFromBootstrapper ...
+func NewCloneFromBootstrapper(bootstrapper Bootstrapper) *CloneFromBootstrapper {
+	return &CloneFromBootstrapper{Bootstrapper: bootstrapper, mutex: &sync.Mutex{}}
+}
+
+// SetMembers replaces the members tagged clonefrom.
+func (b *CloneFromBootstrapper) SetMembers(members []node.Spec) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.members = append([]node.Spec{}, members...)
+}
+
+// Bootstrap tries the members in turn, then the leader.
+func (b *CloneFromBootstrapper) Bootstrap(ctx context.Context, leader node.Spec) error {
+	b.mutex.Lock()
+	members := b.members
+	b.mutex.Unlock()
+	for _, member := range members {
+		if member.Name == leader.Name {
+			continue
+		}
+		log.Infof("postgres: cloning from %s...\n", member.Name)
+		err := b.Bootstrapper.Bootstrap(ctx, member)
+		if err == nil {
+			return nil
+		}
+		log.Warnf("postgres: failed to clone from %s: %v\n", member.Name, err)
+	}
+	return b.Bootstrapper.Bootstrap(ctx, leader)
+}
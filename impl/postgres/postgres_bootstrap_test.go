@@ -0,0 +1,181 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"github/mlyahmed.io/nominee/impl/postgres"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"strings"
+	"testing"
+)
+
+// fakeExecutor records the commands instead of running them.
+type fakeExecutor struct {
+	commands []string
+}
+
+func (e *fakeExecutor) Exec(_ context.Context, cmd string, _ int) error {
+	e.commands = append(e.commands, cmd)
+	return nil
+}
+
+var bootstrapExamples = []struct {
+	method   string
+	command  string
+	expected string
+}{
+	{
+		method:   postgres.BaseBackup,
+		expected: "pg_basebackup -h node01.postgres.priv -U replicator -p 5432 -D /var/lib/postgresql/data -Fp -Xs -P -R",
+	},
+	{
+		method:   postgres.PgBackRest,
+		expected: "pgbackrest --stanza=cluster-001 --pg1-path=/var/lib/postgresql/data --type=standby --delta restore",
+	},
+	{
+		method: postgres.WalG,
+		expected: "wal-g backup-fetch /var/lib/postgresql/data LATEST && touch /var/lib/postgresql/data/standby.signal && " +
+			"echo \"restore_command = 'wal-g wal-fetch %f %p'\" >> /var/lib/postgresql/data/postgresql.auto.conf",
+	},
+	{
+		method:   postgres.PgBackRest,
+		command:  "pgbackrest --stanza=main --pg1-path={{.PgData}} --type=standby restore",
+		expected: "pgbackrest --stanza=main --pg1-path=/var/lib/postgresql/data --type=standby restore",
+	},
+	{
+		method:   postgres.Script,
+		command:  "/opt/bootstrap.sh {{.Name}} {{.Leader.Address}} {{.Leader.Port}} {{.PgData}}",
+		expected: "/opt/bootstrap.sh postgres-02 node01.postgres.priv 5432 /var/lib/postgresql/data",
+	},
+}
+
+func newBootstrapConfig(method, command string) *postgres.ConfigSpec {
+	return &postgres.ConfigSpec{
+		BasicConfig:      &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		NodeSpec:         node.Spec{Name: "postgres-02"},
+		Replicator:       postgres.DBUser{Username: "replicator"},
+		BootstrapMethod:  method,
+		BootstrapCommand: command,
+	}
+}
+
+func TestBootstrapper_when_bootstrap_then_run_the_command_of_the_method(t *testing.T) {
+	t.Logf("Given the bootstrap methods")
+	{
+		leader := node.Spec{Name: "postgres-01", Address: "node01.postgres.priv", Port: 5432}
+		for i, example := range bootstrapExamples {
+			t.Run("", func(t *testing.T) {
+				t.Logf("\tTest %d: When bootstrap with %s and the command <%s>.", i, example.method, example.command)
+				{
+					executor := &fakeExecutor{}
+					bootstrapper, err := postgres.NewBootstrapper(newBootstrapConfig(example.method, example.command), "/var/lib/postgresql/data", executor)
+					if err != nil {
+						t.Fatalf("\t\t%s FAIL: NewBootstrapper, expected no error but actual is <%v>", testutils.Failed, err)
+					}
+
+					if err := bootstrapper.Bootstrap(context.Background(), leader); err != nil {
+						t.Fatalf("\t\t%s FAIL: Bootstrap, expected no error but actual is <%v>", testutils.Failed, err)
+					}
+					if len(executor.commands) != 1 || executor.commands[0] != example.expected {
+						t.Fatalf("\t\t%s FAIL: Exec, expected <%s> but actual is <%v>", testutils.Failed, example.expected, executor.commands)
+					}
+					t.Logf("\t\t%s Then the command of the method must be run.", testutils.Succeed)
+				}
+			})
+		}
+	}
+}
+
+func TestBootstrapper_when_the_command_is_invalid_then_fail(t *testing.T) {
+	t.Logf("Given bootstrap commands which cannot be built")
+	{
+		for i, example := range []struct {
+			method  string
+			command string
+		}{
+			{method: postgres.Script},
+			{method: postgres.Script, command: "/opt/bootstrap.sh {{.PgData"},
+			{method: postgres.Script, command: "/opt/bootstrap.sh {{.Unknown}}"},
+		} {
+			t.Run("", func(t *testing.T) {
+				t.Logf("\tTest %d: When bootstrap with %s and the command <%s>.", i, example.method, example.command)
+				{
+					executor := &fakeExecutor{}
+					bootstrapper, err := postgres.NewBootstrapper(newBootstrapConfig(example.method, example.command), "/var/lib/postgresql/data", executor)
+					if err == nil {
+						err = bootstrapper.Bootstrap(context.Background(), node.Spec{})
+					}
+					if err == nil || len(executor.commands) != 0 {
+						t.Fatalf("\t\t%s FAIL: Bootstrap, expected an error and no command but actual is <%v> and <%v>", testutils.Failed, err, executor.commands)
+					}
+					t.Logf("\t\t%s Then it must fail without running anything.", testutils.Succeed)
+				}
+			})
+		}
+	}
+}
+
+// failingExecutor fails the commands containing the address.
+type failingExecutor struct {
+	fakeExecutor
+	address string
+}
+
+func (e *failingExecutor) Exec(ctx context.Context, cmd string, retries int) error {
+	_ = e.fakeExecutor.Exec(ctx, cmd, retries)
+	if strings.Contains(cmd, e.address) {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestCloneFromBootstrapper_when_members_tagged_clonefrom_then_clone_from_them_before_the_leader(t *testing.T) {
+	t.Logf("Given a leader and two members tagged clonefrom, the first one unreachable")
+	{
+		leader := node.Spec{Name: "postgres-01", Address: "node01.postgres.priv", Port: 5432}
+		unreachable := node.Spec{Name: "postgres-03", Address: "node03.postgres.priv", Port: 5432, Tags: node.Tags{CloneFrom: true}}
+		source := node.Spec{Name: "postgres-04", Address: "node04.postgres.priv", Port: 5432, Tags: node.Tags{CloneFrom: true}}
+		executor := &failingExecutor{address: unreachable.Address}
+		base, _ := postgres.NewBootstrapper(newBootstrapConfig(postgres.BaseBackup, ""), "/var/lib/postgresql/data", executor)
+		bootstrapper := postgres.NewCloneFromBootstrapper(base)
+
+		t.Logf("\tWhen bootstrap without any member")
+		{
+			if err := bootstrapper.Bootstrap(context.Background(), leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Bootstrap, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if len(executor.commands) != 1 || !strings.Contains(executor.commands[0], leader.Address) {
+				t.Fatalf("\t\t%s FAIL: Exec, expected a copy of the leader but actual is <%v>", testutils.Failed, executor.commands)
+			}
+			t.Logf("\t\t%s Then it must clone from the leader.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen bootstrap with the members")
+		{
+			executor.commands = nil
+			bootstrapper.SetMembers([]node.Spec{unreachable, source})
+			if err := bootstrapper.Bootstrap(context.Background(), leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Bootstrap, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if len(executor.commands) != 2 || !strings.Contains(executor.commands[1], source.Address) {
+				t.Fatalf("\t\t%s FAIL: Exec, expected a copy of %s after %s but actual is <%v>", testutils.Failed, source.Name, unreachable.Name, executor.commands)
+			}
+			t.Logf("\t\t%s Then it must clone from the first member it can copy.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen none of the members can be copied")
+		{
+			executor.commands = nil
+			bootstrapper.SetMembers([]node.Spec{unreachable})
+			if err := bootstrapper.Bootstrap(context.Background(), leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Bootstrap, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if len(executor.commands) != 2 || !strings.Contains(executor.commands[1], leader.Address) {
+				t.Fatalf("\t\t%s FAIL: Exec, expected a copy of the leader after %s but actual is <%v>", testutils.Failed, unreachable.Name, executor.commands)
+			}
+			t.Logf("\t\t%s Then it must fall back on the leader.", testutils.Succeed)
+		}
+	}
+}
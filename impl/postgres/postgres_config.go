@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"github/mlyahmed.io/nominee/pkg/config"
 	"github/mlyahmed.io/nominee/pkg/node"
 	"os"
+	"time"
 )
 
 // ConfigLoader ...
@@ -16,11 +18,29 @@ type ConfigLoader interface {
 // ConfigSpec ...
 type ConfigSpec struct {
 	*config.BasicConfig
-	NodeSpec   node.Spec
-	Postgres   DBUser
-	Replicator DBUser
+	NodeSpec           node.Spec
+	Postgres           DBUser
+	Replicator         DBUser
+	MaxLagOnSwitchover int64
+	MaxLagOnFailover   int64
+	SynchronousMode    string
+	SlotsGracePeriod   time.Duration
+	PgDataRetention    int
+	PgDataAside        string
+	BootstrapMethod    string
+	BootstrapCommand   string
 }
 
+// The synchronous modes.
+const (
+	// SyncOff replicates asynchronously.
+	SyncOff = "off"
+	// SyncOn replicates synchronously to one standby, asynchronously when there is none.
+	SyncOn = "on"
+	// SyncStrict replicates synchronously to one standby, the writes are blocked when there is none.
+	SyncStrict = "strict"
+)
+
 // NewConfigLoader ...
 func NewConfigLoader() ConfigLoader {
 	return &ConfigSpec{
@@ -35,13 +55,43 @@ func NewConfigLoader() ConfigLoader {
 func (conf *ConfigSpec) Load(ctx context.Context) {
 	conf.BasicConfig.Load(ctx)
 	config.SetDefault("NOMINEE_POSTGRES_NODE_PORT", 5432)
+	config.SetDefault("NOMINEE_POSTGRES_MAX_LAG_ON_SWITCHOVER", 1048576)
+	config.SetDefault("NOMINEE_POSTGRES_MAX_LAG_ON_FAILOVER", 1048576)
+	config.SetDefault("NOMINEE_POSTGRES_SYNCHRONOUS_MODE", SyncOff)
+	config.SetDefault("NOMINEE_POSTGRES_SLOTS_GRACE_PERIOD", 300)
+	config.SetDefault("NOMINEE_POSTGRES_PGDATA_RETENTION", 1)
+	config.SetDefault("NOMINEE_POSTGRES_BOOTSTRAP_METHOD", BaseBackup)
 
 	conf.NodeSpec.Name = config.GetStringOrPanic("NOMINEE_POSTGRES_NODE_NAME")
 	conf.NodeSpec.Address = config.GetStringOrPanic("NOMINEE_POSTGRES_NODE_ADDRESS")
 	conf.NodeSpec.Port = int64(config.GetIntOrPanic("NOMINEE_POSTGRES_NODE_PORT"))
+	conf.NodeSpec.Tags = node.LoadTags("NOMINEE_POSTGRES_NODE")
 	conf.Postgres.Password = config.GetStringOrPanic("NOMINEE_POSTGRES_PASSWORD")
 	conf.Replicator.Username = config.GetStringOrPanic("NOMINEE_POSTGRES_REP_USERNAME")
 	conf.Replicator.Password = config.GetStringOrPanic("NOMINEE_POSTGRES_REP_PASSWORD")
+	conf.MaxLagOnSwitchover = int64(config.GetIntOrPanic("NOMINEE_POSTGRES_MAX_LAG_ON_SWITCHOVER"))
+	conf.MaxLagOnFailover = int64(config.GetIntOrPanic("NOMINEE_POSTGRES_MAX_LAG_ON_FAILOVER"))
+	conf.SynchronousMode = config.GetStringOrPanic("NOMINEE_POSTGRES_SYNCHRONOUS_MODE")
+	if conf.SynchronousMode != SyncOff && conf.SynchronousMode != SyncOn && conf.SynchronousMode != SyncStrict {
+		panic(fmt.Sprintf("You must specify the env var NOMINEE_POSTGRES_SYNCHRONOUS_MODE to %s, %s or %s.", SyncOff, SyncOn, SyncStrict))
+	}
+	conf.SlotsGracePeriod = time.Duration(config.GetIntOrPanic("NOMINEE_POSTGRES_SLOTS_GRACE_PERIOD")) * time.Second
+	conf.PgDataRetention = config.GetIntOrPanic("NOMINEE_POSTGRES_PGDATA_RETENTION")
+	if conf.PgDataRetention < 1 {
+		panic("You must specify the env var NOMINEE_POSTGRES_PGDATA_RETENTION to a positive int value.")
+	}
+	conf.PgDataAside = config.GetString("NOMINEE_POSTGRES_PGDATA_ASIDE")
+	conf.BootstrapMethod = config.GetStringOrPanic("NOMINEE_POSTGRES_BOOTSTRAP_METHOD")
+	conf.BootstrapCommand = config.GetString("NOMINEE_POSTGRES_BOOTSTRAP_COMMAND")
+	switch conf.BootstrapMethod {
+	case BaseBackup, PgBackRest, WalG:
+	case Script:
+		if conf.BootstrapCommand == "" {
+			panic(fmt.Sprintf("You must specify the env var NOMINEE_POSTGRES_BOOTSTRAP_COMMAND with the %s method.", Script))
+		}
+	default:
+		panic(fmt.Sprintf("You must specify the env var NOMINEE_POSTGRES_BOOTSTRAP_METHOD to %s, %s, %s or %s.", BaseBackup, PgBackRest, WalG, Script))
+	}
 
 	if err := os.Setenv("POSTGRES_PASSWORD", conf.Postgres.Password); err != nil {
 		panic(err)
@@ -10,6 +10,18 @@ type configurationExamples struct {
 	postgresPassword   string
 	replicatorUsername string
 	replicatorPassword string
+	maxLagOnSwitchover string
+	maxLagOnFailover   string
+	priority           string
+	noFailover         string
+	noLoadBalance      string
+	cloneFrom          string
+	synchronousMode    string
+	slotsGracePeriod   string
+	pgdataRetention    string
+	pgdataAside        string
+	bootstrapMethod    string
+	bootstrapCommand   string
 }
 
 var validExamples = []configurationExamples{
@@ -33,6 +45,15 @@ var validExamples = []configurationExamples{
 		postgresPassword:   "pg$$$$$",
 		replicatorUsername: "repl",
 		replicatorPassword: "@$ecret",
+		maxLagOnSwitchover: "0",
+		maxLagOnFailover:   "0",
+		priority:           "2",
+		noFailover:         "true",
+		noLoadBalance:      "true",
+		synchronousMode:    "on",
+		slotsGracePeriod:   "0",
+		pgdataRetention:    "2",
+		bootstrapMethod:    "pgbackrest",
 	},
 	{
 		description:        "full configuration #2",
@@ -44,10 +65,86 @@ var validExamples = []configurationExamples{
 		postgresPassword:   "()_+++==MIN$%^&)",
 		replicatorUsername: "repl",
 		replicatorPassword: "SHUT$$$",
+		maxLagOnSwitchover: "16777216",
+		maxLagOnFailover:   "33554432",
+		priority:           "0",
+		cloneFrom:          "true",
+		synchronousMode:    "strict",
+		slotsGracePeriod:   "600",
+		pgdataRetention:    "3",
+		pgdataAside:        "/var/lib/postgresql/aside",
+		bootstrapMethod:    "script",
+		bootstrapCommand:   "/opt/bootstrap.sh {{.PgData}}",
 	},
 }
 
 var invalidExamples = []configurationExamples{
+	{
+		description:        "priority is negative",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "postgres-01",
+		nodeAddress:        "node01.postgres.priv",
+		postgresPassword:   "postgre$",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+		priority:           "-1",
+	},
+	{
+		description:        "synchronous mode is unknown",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "postgres-01",
+		nodeAddress:        "node01.postgres.priv",
+		postgresPassword:   "postgre$",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+		synchronousMode:    "always",
+	},
+	{
+		description:        "PGDATA retention is negative",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "postgres-01",
+		nodeAddress:        "node01.postgres.priv",
+		postgresPassword:   "postgre$",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+		pgdataRetention:    "-1",
+	},
+	{
+		description:        "PGDATA retention is zero",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "postgres-01",
+		nodeAddress:        "node01.postgres.priv",
+		postgresPassword:   "postgre$",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+		pgdataRetention:    "0",
+	},
+	{
+		description:        "bootstrap method is unknown",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "postgres-01",
+		nodeAddress:        "node01.postgres.priv",
+		postgresPassword:   "postgre$",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+		bootstrapMethod:    "barman",
+	},
+	{
+		description:        "bootstrap script without command",
+		cluster:            "cluster-001",
+		domain:             "domain-001",
+		nodeName:           "postgres-01",
+		nodeAddress:        "node01.postgres.priv",
+		postgresPassword:   "postgre$",
+		replicatorUsername: "replicator",
+		replicatorPassword: "$ecret",
+		bootstrapMethod:    "script",
+	},
 	{
 		description:        "cluster name is missing",
 		domain:             "domain-111",
@@ -3,10 +3,12 @@ package postgres_test
 import (
 	"context"
 	"github/mlyahmed.io/nominee/impl/postgres"
+	"github/mlyahmed.io/nominee/pkg/node"
 	"github/mlyahmed.io/nominee/pkg/testutils"
 	"os"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestPGConfig_loads_configurations(t *testing.T) {
@@ -73,6 +75,73 @@ func TestPGConfig_loads_configurations(t *testing.T) {
 						t.Fatalf("\t\t%s FAIL: ConfigSpec.Replicator.Password, expected <%s> but actual is <%s>", testutils.Failed, example.replicatorPassword, pgConfig.Replicator.Password)
 					}
 					t.Logf("\t\t%s Then the ConfigSpec.Replicator.Password should be loaded.", testutils.Succeed)
+
+					expectedMaxLag := int64(1048576)
+					if example.maxLagOnSwitchover != "" {
+						expectedMaxLag, _ = strconv.ParseInt(example.maxLagOnSwitchover, 10, 64)
+					}
+					if pgConfig.MaxLagOnSwitchover != expectedMaxLag {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.MaxLagOnSwitchover, expected <%d> but actual is <%d>", testutils.Failed, expectedMaxLag, pgConfig.MaxLagOnSwitchover)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.MaxLagOnSwitchover should be loaded.", testutils.Succeed)
+
+					expectedMaxLag = int64(1048576)
+					if example.maxLagOnFailover != "" {
+						expectedMaxLag, _ = strconv.ParseInt(example.maxLagOnFailover, 10, 64)
+					}
+					if pgConfig.MaxLagOnFailover != expectedMaxLag {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.MaxLagOnFailover, expected <%d> but actual is <%d>", testutils.Failed, expectedMaxLag, pgConfig.MaxLagOnFailover)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.MaxLagOnFailover should be loaded.", testutils.Succeed)
+
+					priority, _ := strconv.Atoi(example.priority)
+					expectedTags := node.Tags{Priority: priority, NoFailover: example.noFailover == "true", NoLoadBalance: example.noLoadBalance == "true", CloneFrom: example.cloneFrom == "true"}
+					if pgConfig.NodeSpec.Tags != expectedTags {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Tags, expected <%v> but actual is <%v>", testutils.Failed, expectedTags, pgConfig.NodeSpec.Tags)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.NodeSpec.Tags should be loaded.", testutils.Succeed)
+
+					expectedMode := postgres.SyncOff
+					if example.synchronousMode != "" {
+						expectedMode = example.synchronousMode
+					}
+					if pgConfig.SynchronousMode != expectedMode {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.SynchronousMode, expected <%s> but actual is <%s>", testutils.Failed, expectedMode, pgConfig.SynchronousMode)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.SynchronousMode should be loaded.", testutils.Succeed)
+
+					expectedGracePeriod := 300 * time.Second
+					if example.slotsGracePeriod != "" {
+						seconds, _ := strconv.Atoi(example.slotsGracePeriod)
+						expectedGracePeriod = time.Duration(seconds) * time.Second
+					}
+					if pgConfig.SlotsGracePeriod != expectedGracePeriod {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.SlotsGracePeriod, expected <%v> but actual is <%v>", testutils.Failed, expectedGracePeriod, pgConfig.SlotsGracePeriod)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.SlotsGracePeriod should be loaded.", testutils.Succeed)
+
+					expectedRetention := 1
+					if example.pgdataRetention != "" {
+						expectedRetention, _ = strconv.Atoi(example.pgdataRetention)
+					}
+					if pgConfig.PgDataRetention != expectedRetention {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.PgDataRetention, expected <%d> but actual is <%d>", testutils.Failed, expectedRetention, pgConfig.PgDataRetention)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.PgDataRetention should be loaded.", testutils.Succeed)
+
+					if pgConfig.PgDataAside != example.pgdataAside {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.PgDataAside, expected <%s> but actual is <%s>", testutils.Failed, example.pgdataAside, pgConfig.PgDataAside)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.PgDataAside should be loaded.", testutils.Succeed)
+
+					expectedMethod := postgres.BaseBackup
+					if example.bootstrapMethod != "" {
+						expectedMethod = example.bootstrapMethod
+					}
+					if pgConfig.BootstrapMethod != expectedMethod || pgConfig.BootstrapCommand != example.bootstrapCommand {
+						t.Fatalf("\t\t%s FAIL: ConfigSpec.Bootstrap, expected <%s %s> but actual is <%s %s>", testutils.Failed, expectedMethod, example.bootstrapCommand, pgConfig.BootstrapMethod, pgConfig.BootstrapCommand)
+					}
+					t.Logf("\t\t%s Then the ConfigSpec.BootstrapMethod and BootstrapCommand should be loaded.", testutils.Succeed)
 				}
 
 			})
@@ -116,6 +185,18 @@ func declareConfigurationExample(example configurationExamples) {
 	_ = os.Setenv("NOMINEE_POSTGRES_PASSWORD", example.postgresPassword)
 	_ = os.Setenv("NOMINEE_POSTGRES_REP_USERNAME", example.replicatorUsername)
 	_ = os.Setenv("NOMINEE_POSTGRES_REP_PASSWORD", example.replicatorPassword)
+	_ = os.Setenv("NOMINEE_POSTGRES_MAX_LAG_ON_SWITCHOVER", example.maxLagOnSwitchover)
+	_ = os.Setenv("NOMINEE_POSTGRES_MAX_LAG_ON_FAILOVER", example.maxLagOnFailover)
+	_ = os.Setenv("NOMINEE_POSTGRES_NODE_PRIORITY", example.priority)
+	_ = os.Setenv("NOMINEE_POSTGRES_NODE_NOFAILOVER", example.noFailover)
+	_ = os.Setenv("NOMINEE_POSTGRES_NODE_NOLOADBALANCE", example.noLoadBalance)
+	_ = os.Setenv("NOMINEE_POSTGRES_NODE_CLONEFROM", example.cloneFrom)
+	_ = os.Setenv("NOMINEE_POSTGRES_SYNCHRONOUS_MODE", example.synchronousMode)
+	_ = os.Setenv("NOMINEE_POSTGRES_SLOTS_GRACE_PERIOD", example.slotsGracePeriod)
+	_ = os.Setenv("NOMINEE_POSTGRES_PGDATA_RETENTION", example.pgdataRetention)
+	_ = os.Setenv("NOMINEE_POSTGRES_PGDATA_ASIDE", example.pgdataAside)
+	_ = os.Setenv("NOMINEE_POSTGRES_BOOTSTRAP_METHOD", example.bootstrapMethod)
+	_ = os.Setenv("NOMINEE_POSTGRES_BOOTSTRAP_COMMAND", example.bootstrapCommand)
 }
 
 func tearsDown() {
@@ -127,4 +208,16 @@ func tearsDown() {
 	_ = os.Unsetenv("NOMINEE_POSTGRES_PASSWORD")
 	_ = os.Unsetenv("NOMINEE_POSTGRES_REP_USERNAME")
 	_ = os.Unsetenv("NOMINEE_POSTGRES_REP_PASSWORD")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_MAX_LAG_ON_SWITCHOVER")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_MAX_LAG_ON_FAILOVER")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_NODE_PRIORITY")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_NODE_NOFAILOVER")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_NODE_NOLOADBALANCE")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_NODE_CLONEFROM")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_SYNCHRONOUS_MODE")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_SLOTS_GRACE_PERIOD")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_PGDATA_RETENTION")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_PGDATA_ASIDE")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_BOOTSTRAP_METHOD")
+	_ = os.Unsetenv("NOMINEE_POSTGRES_BOOTSTRAP_COMMAND")
 }
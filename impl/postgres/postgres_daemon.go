@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// Daemon runs the postgres process.
+type Daemon interface {
+	// Run returns once postgres is stopped.
+	Run(ctx context.Context) error
+}
+
+// EntrypointDaemon runs postgres through the docker entrypoint, which runs initdb on the first start.
+type EntrypointDaemon struct {
+	username string
+}
+
+// NewEntrypointDaemon ...
+func NewEntrypointDaemon(username string) *EntrypointDaemon {
+	return &EntrypointDaemon{username: username}
+}
+
+// Run ...
+func (d *EntrypointDaemon) Run(ctx context.Context) error {
+	start := exec.CommandContext(ctx, "/docker-entrypoint.sh", d.username)
+	start.Stdout, start.Stderr = log.Writer(), log.Writer()
+	return start.Run()
+}
+
+// OSExecutor runs the OS commands as the postgres user and reads their output.
+type OSExecutor interface {
+	Executor
+	// Output returns the standard output of the command.
+	Output(ctx context.Context, cmd string) ([]byte, error)
+}
+
+// SuExecutor runs the OS commands with su, as the postgres user.
+type SuExecutor struct {
+	username string
+}
+
+// NewSuExecutor ...
+func NewSuExecutor(username string) *SuExecutor {
+	return &SuExecutor{username: username}
+}
+
+// Exec retries the command up to retries times.
+func (e *SuExecutor) Exec(ctx context.Context, cmd string, retries int) error {
+	for i := retries; ; i-- {
+		command := exec.CommandContext(ctx, "su", "-c", cmd, e.username)
+		command.Stdout, command.Stderr = log.Writer(), log.Writer()
+
+		if err := command.Run(); err != nil {
+			if i >= 0 {
+				time.Sleep(defaultWaitRetry)
+				continue
+			} else {
+				return err
+			}
+		}
+		break
+	}
+	return nil
+}
+
+// Output ...
+func (e *SuExecutor) Output(ctx context.Context, cmd string) ([]byte, error) {
+	command := exec.CommandContext(ctx, "su", "-c", cmd, e.username)
+	command.Stderr = log.Writer()
+	return command.Output()
+}
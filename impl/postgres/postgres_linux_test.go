@@ -0,0 +1,81 @@
+package postgres_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/postgres"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// mount mounts a tmpfs on the directory until the end of the test, which is skipped when it cannot.
+func mount(t *testing.T, dir string) {
+	if err := syscall.Mount("tmpfs", dir, "tmpfs", 0, "mode=0700"); err != nil {
+		t.Skipf("cannot mount %s: %v", dir, err)
+	}
+	t.Cleanup(func() { _ = syscall.Unmount(dir, 0) })
+}
+
+func TestPostgres_when_PGDATA_is_on_a_volume_then_move_its_content_into_the_aside_directory_of_the_volume(t *testing.T) {
+	t.Logf("Given a former primary whose PGDATA and aside directory are on a volume")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		volume := filepath.Dir(newPgData(t))
+		mount(t, volume)
+		pgdata, aside := filepath.Join(volume, "data"), filepath.Join(volume, "aside")
+		for _, dir := range []string{pgdata, aside} {
+			if err := os.Mkdir(dir, 0700); err != nil {
+				t.Fatalf("\t\t%s FAIL: Mkdir, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+		}
+		fillPgData(t, pgdata)
+		before, _ := os.Stat(pgdata)
+		pg, _, executor := newPostgres(t, pgdata, func(spec *postgres.ConfigSpec) { spec.PgDataAside = aside })
+		failRewind(executor)
+
+		t.Logf("\tWhen it cannot be rewound")
+		{
+			if err := pg.Follow(ctx, leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Follow, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			copies := asideCopies(t, aside, pgdata)
+			if len(copies) != 1 || clones(executor.GetCommands()) != 1 {
+				t.Fatalf("\t\t%s FAIL: Follow, expected one copy and one clone but actual is <%v> and <%v>", testutils.Failed, copies, executor.GetCommands())
+			}
+			checkMovedAside(t, pgdata, before, copies[0])
+			t.Logf("\t\t%s Then the content of PGDATA must be moved into the aside directory before the re-clone.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_the_aside_directory_is_not_on_the_volume_of_PGDATA_then_fail_without_copying(t *testing.T) {
+	t.Logf("Given a former primary whose PGDATA is a mount point, with the default aside directory")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pgdata := newPgData(t)
+		mount(t, pgdata)
+		fillPgData(t, pgdata)
+		pg, _, executor := newPostgres(t, pgdata)
+		failRewind(executor)
+
+		t.Logf("\tWhen it cannot be rewound")
+		{
+			if err := pg.Follow(ctx, leader); err == nil {
+				t.Fatalf("\t\t%s FAIL: Follow, expected an error but actual is none", testutils.Failed)
+			}
+
+			if copies := asideCopies(t, filepath.Dir(pgdata), pgdata); len(copies) != 0 || clones(executor.GetCommands()) != 0 {
+				t.Fatalf("\t\t%s FAIL: Follow, expected no copy and no clone but actual is <%v> and <%v>", testutils.Failed, copies, executor.GetCommands())
+			}
+			if _, err := os.Stat(filepath.Join(pgdata, "base/1")); err != nil {
+				t.Fatalf("\t\t%s FAIL: Stat, expected the data in PGDATA but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then it must fail and leave PGDATA as is.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gopg "github.com/go-pg/pg/v10"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// managed are the parameters nominee sets itself, they cannot be set from the cluster-wide configuration.
+var managed = map[string]bool{
+	"primary_conninfo":          true,
+	"primary_slot_name":         true,
+	"synchronous_standby_names": true,
+	"listen_addresses":          true,
+	"port":                      true,
+}
+
+// appliedFile records the names of the parameters set from the cluster-wide configuration. It lives in PGDATA, so that
+// the replicas get it along with postgresql.auto.conf.
+const appliedFile = "nominee.parameters"
+
+// parameterName matches the names of the parameters, the customized ones are qualified by their extension.
+var parameterName = regexp.MustCompile(`^[a-z_][a-z0-9_]*(\.[a-z_][a-z0-9_]*)?$`)
+
+// CheckParameters refuses the parameters which are not valid names, and those managed by nominee.
+func CheckParameters(parameters node.Parameters) error {
+	for _, name := range sortedNames(parameters) {
+		if !parameterName.MatchString(name) {
+			return fmt.Errorf("postgres: %q is not a valid parameter name", name)
+		}
+		if managed[name] {
+			return fmt.Errorf("postgres: %s is managed by nominee", name)
+		}
+	}
+	return nil
+}
+
+// Configure sets the parameters with ALTER SYSTEM then reloads the configuration. Those set before from the cluster-wide
+// configuration and no longer there are reset, the others set with ALTER SYSTEM are left as they are. The parameters
+// of the postmaster context wait for a restart to take effect.
+func (pg *Postgres) Configure(ctx context.Context, parameters node.Parameters) error {
+	if pg.status != started {
+		return errors.New("postgres: not started")
+	}
+	if err := CheckParameters(parameters); err != nil {
+		return err
+	}
+
+	applied, err := pg.applied()
+	if err != nil {
+		return err
+	}
+	for _, name := range applied {
+		if _, ok := parameters[name]; ok || managed[name] || !parameterName.MatchString(name) {
+			continue
+		}
+		if _, err := pg.DB.ExecContext(ctx, "ALTER SYSTEM RESET "+name); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedNames(parameters) {
+		if _, err := pg.DB.ExecContext(ctx, "ALTER SYSTEM SET "+name+" TO ?", parameters[name]); err != nil {
+			return err
+		}
+	}
+	if err := pg.setApplied(sortedNames(parameters)); err != nil {
+		return err
+	}
+	if err := pg.reloadConf(ctx); err != nil {
+		return err
+	}
+
+	if len(parameters) == 0 {
+		return nil
+	}
+	var settings []struct {
+		Name    string
+		Context string
+	}
+	if _, err := pg.DB.QueryContext(ctx, &settings, "SELECT name, context FROM pg_settings WHERE name IN (?)", gopg.In(sortedNames(parameters))); err != nil {
+		return err
+	}
+	for _, setting := range settings {
+		if setting.Context == "postmaster" {
+			log.Infof("postgres: %s set, restart required\n", setting.Name)
+		} else {
+			log.Infof("postgres: %s set, reloaded\n", setting.Name)
+		}
+	}
+	return nil
+}
+
+// applied are the names of the parameters set from the cluster-wide configuration, none before the first time.
+func (pg *Postgres) applied() ([]string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(pg.pgdata, appliedFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(content)), nil
+}
+
+// setApplied records the names of the parameters set from the cluster-wide configuration, owned by postgres as the
+// base backups copy it.
+func (pg *Postgres) setApplied(names []string) error {
+	path := filepath.Join(pg.pgdata, appliedFile)
+	if err := ioutil.WriteFile(path, []byte(strings.Join(names, "\n")), 0600); err != nil {
+		return err
+	}
+	return os.Chown(path, pg.osUser.uid, pg.osUser.gid)
+}
+
+// PendingRestart tells whether some parameters changed in the configuration files wait for a restart.
+func (pg *Postgres) PendingRestart(ctx context.Context) (bool, error) {
+	if pg.status != started {
+		return false, errors.New("postgres: not started")
+	}
+	var pending bool
+	_, err := pg.DB.QueryOneContext(ctx, gopg.Scan(&pending), "SELECT count(*) > 0 FROM pg_settings WHERE pending_restart")
+	return pending, err
+}
+
+func sortedNames(parameters node.Parameters) []string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,114 @@
+package postgres_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/postgres"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckParameters_when_valid_then_accept(t *testing.T) {
+	t.Logf("Given valid parameters")
+	{
+		parameters := node.Parameters{"max_connections": "200", "work_mem": "16MB", "pg_stat_statements.track": "all"}
+
+		t.Logf("\tWhen check them")
+		{
+			if err := postgres.CheckParameters(parameters); err != nil {
+				t.Fatalf("\t\t%s FAIL: CheckParameters, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then they must be accepted.", testutils.Succeed)
+		}
+	}
+}
+
+func TestCheckParameters_when_invalid_or_managed_then_reject(t *testing.T) {
+	t.Logf("Given invalid parameters and parameters managed by nominee")
+	{
+		for i, name := range []string{"", "Work_Mem", "work_mem; DROP TABLE x", "a.b.c", "primary_conninfo", "primary_slot_name", "synchronous_standby_names", "port"} {
+			t.Run("", func(t *testing.T) {
+				t.Logf("\tTest %d: When check <%s>.", i, name)
+				{
+					if err := postgres.CheckParameters(node.Parameters{name: "x"}); err == nil {
+						t.Fatalf("\t\t%s FAIL: CheckParameters, expected an error but actual is none", testutils.Failed)
+					}
+					t.Logf("\t\t%s Then it must be rejected.", testutils.Succeed)
+				}
+			})
+		}
+	}
+}
+
+func resets(statements []string) []string {
+	var reset []string
+	for _, statement := range statements {
+		if strings.HasPrefix(statement, "ALTER SYSTEM RESET ") {
+			reset = append(reset, strings.TrimPrefix(statement, "ALTER SYSTEM RESET "))
+		}
+	}
+	return reset
+}
+
+func TestPostgres_when_configured_then_reset_only_the_parameters_applied_before(t *testing.T) {
+	t.Logf("Given a standby bootstrapped with a restore_command")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pgdata := newPgData(t)
+		fillPgData(t, pgdata)
+		if err := ioutil.WriteFile(filepath.Join(pgdata, "postgresql.auto.conf"), []byte("restore_command = 'wal-g wal-fetch %f %p'\n"), 0600); err != nil {
+			t.Fatalf("\t\t%s FAIL: WriteFile, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+		pg, db, _ := newPostgres(t, pgdata)
+		db.QueryFn = func(_ context.Context, model interface{}, _ string, _ ...interface{}) error {
+			if names, ok := model.(*[]string); ok {
+				*names = []string{"restore_command", "work_mem"}
+			}
+			return nil
+		}
+		if err := pg.Lead(ctx, *pg.Spec); err != nil {
+			t.Fatalf("\t\t%s FAIL: Lead, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+
+		t.Logf("\tWhen the cluster-wide configuration sets then removes work_mem")
+		{
+			if err := pg.Configure(ctx, node.Parameters{"work_mem": "16MB"}); err != nil {
+				t.Fatalf("\t\t%s FAIL: Configure, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if reset := resets(db.GetStatements()); len(reset) != 0 {
+				t.Fatalf("\t\t%s FAIL: Configure, expected no reset but actual is <%v>", testutils.Failed, reset)
+			}
+			if err := pg.Configure(ctx, node.Parameters{}); err != nil {
+				t.Fatalf("\t\t%s FAIL: Configure, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			if reset := resets(db.GetStatements()); len(reset) != 1 || reset[0] != "work_mem" {
+				t.Fatalf("\t\t%s FAIL: Configure, expected to reset work_mem only but actual is <%v>", testutils.Failed, reset)
+			}
+			t.Logf("\t\t%s Then the restore_command written by the bootstrap must survive.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen restarted then configured without work_mem")
+		{
+			if err := pg.Configure(ctx, node.Parameters{"work_mem": "16MB"}); err != nil {
+				t.Fatalf("\t\t%s FAIL: Configure, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			restarted, db, _ := newPostgres(t, pgdata)
+			if err := restarted.Lead(ctx, *restarted.Spec); err != nil {
+				t.Fatalf("\t\t%s FAIL: Lead, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if err := restarted.Configure(ctx, node.Parameters{}); err != nil {
+				t.Fatalf("\t\t%s FAIL: Configure, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			if reset := resets(db.GetStatements()); len(reset) != 1 || reset[0] != "work_mem" {
+				t.Fatalf("\t\t%s FAIL: Configure, expected to reset work_mem only but actual is <%v>", testutils.Failed, reset)
+			}
+			t.Logf("\t\t%s Then the parameters applied before the restart must be reset.", testutils.Succeed)
+		}
+	}
+}
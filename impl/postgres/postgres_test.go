@@ -0,0 +1,663 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/impl/postgres"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+// newPostgres returns a node on the PGDATA whose server is stopped by pg_ctl stop. The configuration can be changed
+// by configure.
+func newPostgres(t *testing.T, pgdata string, configure ...func(spec *postgres.ConfigSpec)) (*postgres.Postgres, *mock.PostgresDB, *mock.PostgresExecutor) {
+	if err := os.Setenv("PGDATA", pgdata); err != nil {
+		t.Fatalf("\t\t%s FAIL: Setenv, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	spec := &postgres.ConfigSpec{
+		BasicConfig:        &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		NodeSpec:           node.Spec{Name: "postgres-01", Address: "node01.postgres.priv", Port: 5432},
+		Postgres:           postgres.DBUser{Username: "postgres", Password: "postgres"},
+		Replicator:         postgres.DBUser{Username: "replicator", Password: "replicator"},
+		MaxLagOnSwitchover: 1048576,
+		MaxLagOnFailover:   1048576,
+		SynchronousMode:    postgres.SyncOff,
+		PgDataRetention:    1,
+		BootstrapMethod:    postgres.BaseBackup,
+	}
+	for _, fn := range configure {
+		fn(spec)
+	}
+	pg := postgres.NewPostgres(&mock.PostgresConfigSpec{ConfigSpec: spec})
+	stopped := make(chan struct{}, 1)
+	db, daemon, executor := mock.NewPostgresDB(), mock.NewPostgresDaemon(), mock.NewPostgresExecutor()
+	daemon.RunFn = func(ctx context.Context) error {
+		select {
+		case <-stopped:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	executor.ExecFn = func(_ context.Context, cmd string) error {
+		if strings.HasPrefix(cmd, "pg_ctl stop") {
+			stopped <- struct{}{}
+		}
+		return nil
+	}
+	pg.DB, pg.Daemon, pg.Executor = db, daemon, executor
+	return pg, db, executor
+}
+
+var leader = node.Spec{Name: "postgres-02", Address: "127.0.0.1", Port: 5432}
+
+// newPrimary returns a started primary, stopped at the end of the test.
+func newPrimary(t *testing.T, configure ...func(spec *postgres.ConfigSpec)) (*postgres.Postgres, *mock.PostgresDB, *mock.PostgresExecutor) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	pgdata := newPgData(t)
+	fillPgData(t, pgdata)
+	pg, db, executor := newPostgres(t, pgdata, configure...)
+	if err := pg.Lead(ctx, *pg.Spec); err != nil {
+		t.Fatalf("\t\t%s FAIL: Lead, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	return pg, db, executor
+}
+
+// newPgData returns an empty PGDATA, alone in its parent directory.
+func newPgData(t *testing.T) string {
+	parent, err := ioutil.TempDir("", "postgresql")
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: TempDir, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(parent) })
+	pgdata := filepath.Join(parent, "data")
+	if err := os.Mkdir(pgdata, 0700); err != nil {
+		t.Fatalf("\t\t%s FAIL: Mkdir, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	return pgdata
+}
+
+// fillPgData writes the data of a primary in the PGDATA.
+func fillPgData(t *testing.T, pgdata string) {
+	for _, dir := range []string{"base", "pg_wal"} {
+		if err := os.MkdirAll(filepath.Join(pgdata, dir), 0700); err != nil {
+			t.Fatalf("\t\t%s FAIL: MkdirAll, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+	}
+	for _, file := range []string{"PG_VERSION", ".hidden", "base/1", "pg_wal/000000010000000000000001"} {
+		if err := ioutil.WriteFile(filepath.Join(pgdata, file), []byte(file), 0600); err != nil {
+			t.Fatalf("\t\t%s FAIL: WriteFile, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+	}
+}
+
+// asideCopies returns the copies of the PGDATA moved aside into the aside directory, the latest last.
+func asideCopies(t *testing.T, aside, pgdata string) []string {
+	copies, err := filepath.Glob(filepath.Join(aside, filepath.Base(pgdata)+".aside-*"))
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: Glob, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	return copies
+}
+
+// checkMovedAside makes sure the PGDATA is the same empty directory, and its data are in the copy.
+func checkMovedAside(t *testing.T, pgdata string, before os.FileInfo, copy string) {
+	after, err := os.Stat(pgdata)
+	if err != nil || !os.SameFile(before, after) {
+		t.Fatalf("\t\t%s FAIL: Stat, expected the same PGDATA but actual is <%v>", testutils.Failed, err)
+	}
+	if entries, _ := ioutil.ReadDir(pgdata); len(entries) != 0 {
+		t.Fatalf("\t\t%s FAIL: ReadDir, expected an empty PGDATA but actual has <%d> entries", testutils.Failed, len(entries))
+	}
+	for _, file := range []string{"PG_VERSION", ".hidden", "base/1", "pg_wal/000000010000000000000001"} {
+		if content, err := ioutil.ReadFile(filepath.Join(copy, file)); err != nil || string(content) != file {
+			t.Fatalf("\t\t%s FAIL: ReadFile, expected <%s> in %s but actual is <%s> and <%v>", testutils.Failed, file, copy, content, err)
+		}
+	}
+}
+
+// failRewind has the executor fail pg_rewind, on top of stopping the server.
+func failRewind(executor *mock.PostgresExecutor) {
+	stop := executor.ExecFn
+	executor.ExecFn = func(ctx context.Context, cmd string) error {
+		if strings.HasPrefix(cmd, "pg_rewind") {
+			return errors.New("could not find common ancestor of the source and target cluster's timelines")
+		}
+		return stop(ctx, cmd)
+	}
+}
+
+func clones(commands []string) int {
+	count := 0
+	for _, command := range commands {
+		if strings.HasPrefix(command, "pg_basebackup -h "+leader.Address) {
+			count++
+		}
+	}
+	return count
+}
+
+func promotions(commands []string) int {
+	count := 0
+	for _, command := range commands {
+		if command == "pg_ctl promote" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestPostgres_when_demoted_then_re_elected_then_promote(t *testing.T) {
+	t.Logf("Given a primary")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pgdata := newPgData(t)
+		fillPgData(t, pgdata)
+		pg, _, executor := newPostgres(t, pgdata)
+		if err := pg.Lead(ctx, *pg.Spec); err != nil {
+			t.Fatalf("\t\t%s FAIL: Lead, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+		if promotions(executor.GetCommands()) != 0 {
+			t.Fatalf("\t\t%s FAIL: Lead, expected no promotion of a primary but actual is <%v>", testutils.Failed, executor.GetCommands())
+		}
+
+		t.Logf("\tWhen it is demoted then re-elected")
+		{
+			if err := pg.Demote(ctx); err != nil {
+				t.Fatalf("\t\t%s FAIL: Demote, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if err := pg.Lead(ctx, *pg.Spec); err != nil {
+				t.Fatalf("\t\t%s FAIL: Lead, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			if promotions(executor.GetCommands()) != 1 {
+				t.Fatalf("\t\t%s FAIL: Lead, expected one promotion but actual is <%v>", testutils.Failed, executor.GetCommands())
+			}
+			t.Logf("\t\t%s Then it must be promoted.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_the_rewind_fails_then_move_the_data_aside_and_re_clone(t *testing.T) {
+	t.Logf("Given a former primary which cannot be rewound")
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		pgdata := newPgData(t)
+		fillPgData(t, pgdata)
+		before, _ := os.Stat(pgdata)
+		pg, _, executor := newPostgres(t, pgdata)
+		failRewind(executor)
+
+		t.Logf("\tWhen it follows the leader")
+		{
+			if err := pg.Follow(ctx, leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Follow, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			copies := asideCopies(t, filepath.Dir(pgdata), pgdata)
+			if len(copies) != 1 || clones(executor.GetCommands()) != 1 {
+				t.Fatalf("\t\t%s FAIL: Follow, expected one copy and one clone but actual is <%v> and <%v>", testutils.Failed, copies, executor.GetCommands())
+			}
+			checkMovedAside(t, pgdata, before, copies[0])
+			t.Logf("\t\t%s Then the content of PGDATA must be moved aside before the re-clone.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen it leads, then is demoted and cannot be rewound again")
+		{
+			if err := pg.Lead(ctx, *pg.Spec); err != nil {
+				t.Fatalf("\t\t%s FAIL: Lead, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if err := pg.Demote(ctx); err != nil {
+				t.Fatalf("\t\t%s FAIL: Demote, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			fillPgData(t, pgdata)
+			first := asideCopies(t, filepath.Dir(pgdata), pgdata)[0]
+			if err := pg.Follow(ctx, leader); err != nil {
+				t.Fatalf("\t\t%s FAIL: Follow, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			copies := asideCopies(t, filepath.Dir(pgdata), pgdata)
+			if len(copies) != 1 || copies[0] == first || clones(executor.GetCommands()) != 2 {
+				t.Fatalf("\t\t%s FAIL: Follow, expected only the latest copy but actual is <%v>", testutils.Failed, copies)
+			}
+			checkMovedAside(t, pgdata, before, copies[0])
+			t.Logf("\t\t%s Then only the most recent copy must be kept.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_demote_then_checkpoint_stop_and_restart_as_a_standby(t *testing.T) {
+	t.Logf("Given a primary")
+	{
+		pg, db, executor := newPrimary(t)
+		pgdata := os.Getenv("PGDATA")
+
+		t.Logf("\tWhen it is demoted")
+		{
+			if err := pg.Demote(context.Background()); err != nil {
+				t.Fatalf("\t\t%s FAIL: Demote, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+
+			if statements := db.GetStatements(); len(statements) != 1 || statements[0] != "CHECKPOINT" {
+				t.Fatalf("\t\t%s FAIL: Demote, expected a CHECKPOINT but actual is <%v>", testutils.Failed, statements)
+			}
+			expected := []string{"pg_ctl stop -m fast -w", "touch " + pgdata + "/standby.signal"}
+			if commands := executor.GetCommands(); !reflect.DeepEqual(commands, expected) {
+				t.Fatalf("\t\t%s FAIL: Demote, expected <%v> but actual is <%v>", testutils.Failed, expected, commands)
+			}
+			if metadata, err := pg.Metadata(context.Background()); err != nil || metadata != (node.Metadata{}) {
+				t.Fatalf("\t\t%s FAIL: Metadata, expected the server stopped but actual is <%v> and <%v>", testutils.Failed, metadata, err)
+			}
+			t.Logf("\t\t%s Then it must checkpoint, stop and restart as a standby.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_the_checkpoint_fails_then_do_not_demote(t *testing.T) {
+	t.Logf("Given a primary which cannot checkpoint")
+	{
+		pg, db, executor := newPrimary(t)
+		db.ExecFn = func(context.Context, string, ...interface{}) error { return errors.New("could not write to file") }
+
+		t.Logf("\tWhen it is demoted")
+		{
+			if err := pg.Demote(context.Background()); err == nil {
+				t.Fatalf("\t\t%s FAIL: Demote, expected an error but actual is none", testutils.Failed)
+			}
+
+			if commands := executor.GetCommands(); len(commands) != 0 {
+				t.Fatalf("\t\t%s FAIL: Demote, expected no command but actual is <%v>", testutils.Failed, commands)
+			}
+			t.Logf("\t\t%s Then it must not stop.", testutils.Succeed)
+		}
+	}
+}
+
+// replication is what pg_stat_replication tells about the standbys.
+type replication []struct {
+	ClientAddr string
+	State      string
+	Lag        int64
+}
+
+func replicatedBy(db *mock.PostgresDB, replicas replication) {
+	db.QueryFn = func(_ context.Context, model interface{}, _ string, _ ...interface{}) error {
+		if rows, ok := model.(*[]struct {
+			ClientAddr string
+			State      string
+			Lag        int64
+		}); ok {
+			*rows = replicas
+		}
+		return nil
+	}
+}
+
+var candidateExamples = []struct {
+	description string
+	mode        string
+	replicas    replication
+	accepted    bool
+}{
+	{
+		description: "the candidate streams and lags within the maximum",
+		mode:        postgres.SyncOff,
+		replicas:    replication{{ClientAddr: "10.0.0.3", State: "streaming"}, {ClientAddr: "10.0.0.2", State: "streaming", Lag: 1048576}},
+		accepted:    true,
+	},
+	{
+		description: "the candidate lags beyond the maximum",
+		mode:        postgres.SyncOff,
+		replicas:    replication{{ClientAddr: "10.0.0.2", State: "streaming", Lag: 1048577}},
+	},
+	{
+		description: "the candidate catches up",
+		mode:        postgres.SyncOff,
+		replicas:    replication{{ClientAddr: "10.0.0.2", State: "catchup"}},
+	},
+	{
+		description: "the candidate has not flushed anything yet",
+		mode:        postgres.SyncOff,
+		replicas:    replication{{ClientAddr: "10.0.0.2", State: "streaming", Lag: -1}},
+	},
+	{
+		description: "the candidate does not replicate",
+		mode:        postgres.SyncOff,
+		replicas:    replication{{ClientAddr: "10.0.0.3", State: "streaming"}},
+	},
+	{
+		description: "the candidate is not the synchronous standby",
+		mode:        postgres.SyncOn,
+		replicas:    replication{{ClientAddr: "10.0.0.2", State: "streaming"}},
+	},
+}
+
+func TestPostgres_when_check_the_candidate_then_accept_only_an_up_to_date_standby(t *testing.T) {
+	candidate := node.Spec{Name: "postgres-02", Address: "10.0.0.2", Port: 5432}
+	for i, example := range candidateExamples {
+		t.Run("", func(t *testing.T) {
+			t.Logf("\tTest %d: When %s.", i, example.description)
+			{
+				pg, db, _ := newPrimary(t, func(spec *postgres.ConfigSpec) { spec.SynchronousMode = example.mode })
+				replicatedBy(db, example.replicas)
+
+				err := pg.CheckCandidate(context.Background(), candidate)
+
+				if example.accepted && err != nil {
+					t.Fatalf("\t\t%s FAIL: CheckCandidate, expected no error but actual is <%v>", testutils.Failed, err)
+				}
+				if !example.accepted && err == nil {
+					t.Fatalf("\t\t%s FAIL: CheckCandidate, expected an error but actual is none", testutils.Failed)
+				}
+				t.Logf("\t\t%s Then the candidate must be accepted <%v>.", testutils.Succeed, example.accepted)
+			}
+		})
+	}
+}
+
+func TestPostgres_when_check_the_eligibility_then_refuse_a_node_lagging_beyond_the_maximum(t *testing.T) {
+	t.Logf("Given a node which has received 1000 bytes of WAL")
+	{
+		pg, db, _ := newPrimary(t)
+		db.QueryFn = func(_ context.Context, model interface{}, _ string, _ ...interface{}) error {
+			if metadata, ok := model.(*node.Metadata); ok {
+				*metadata = node.Metadata{ReceivedLSN: 1000, ReplayedLSN: 900}
+			}
+			return nil
+		}
+		standby := func(position int64) node.Spec {
+			return node.Spec{Name: "postgres-02", Metadata: node.Metadata{ReceivedLSN: position}}
+		}
+
+		t.Logf("\tWhen the most up to date standby is ahead by the maximum")
+		{
+			if err := pg.CheckEligibility(context.Background(), []node.Spec{standby(0), standby(1000 + 1048576)}); err != nil {
+				t.Fatalf("\t\t%s FAIL: CheckEligibility, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then the node must be eligible.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the most up to date standby is ahead by more than the maximum")
+		{
+			if err := pg.CheckEligibility(context.Background(), []node.Spec{standby(1000 + 1048577), standby(0)}); err == nil {
+				t.Fatalf("\t\t%s FAIL: CheckEligibility, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then the node must not be eligible.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_check_initialized_then_refuse_to_initdb_an_initialized_cluster(t *testing.T) {
+	t.Logf("Given a node without data")
+	{
+		pg, _, _ := newPostgres(t, newPgData(t))
+
+		t.Logf("\tWhen the cluster is not initialized yet")
+		{
+			if err := pg.CheckInitialized(context.Background(), ""); err != nil {
+				t.Fatalf("\t\t%s FAIL: CheckInitialized, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then it may initdb.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the cluster is already initialized")
+		{
+			if err := pg.CheckInitialized(context.Background(), "6943853622418735456"); err == nil {
+				t.Fatalf("\t\t%s FAIL: CheckInitialized, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must refuse to initdb.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_check_initialized_then_compare_with_the_system_identifier_of_the_data(t *testing.T) {
+	t.Logf("Given a node with the data of the cluster 6943853622418735456")
+	{
+		pg, _, executor := newPrimary(t)
+		pgdata := os.Getenv("PGDATA")
+		executor.OutputFn = func(_ context.Context, cmd string) ([]byte, error) {
+			if cmd != "pg_controldata -D "+pgdata {
+				return nil, fmt.Errorf("unexpected command %s", cmd)
+			}
+			return []byte("pg_control version number:            1300\n" +
+				"Database system identifier:           6943853622418735456\n" +
+				"Database cluster state:               in production\n"), nil
+		}
+
+		t.Logf("\tWhen the cluster is initialized with the same system identifier")
+		{
+			if err := pg.CheckInitialized(context.Background(), "6943853622418735456"); err != nil {
+				t.Fatalf("\t\t%s FAIL: CheckInitialized, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then it may lead the cluster.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the cluster is initialized with another system identifier")
+		{
+			if err := pg.CheckInitialized(context.Background(), "6943853622418735457"); err == nil {
+				t.Fatalf("\t\t%s FAIL: CheckInitialized, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must refuse to lead the cluster.", testutils.Succeed)
+		}
+	}
+}
+
+// streaming is what pg_stat_replication tells about the standbys, by application name.
+type streaming []struct {
+	ApplicationName string
+	State           string
+	SyncState       string
+}
+
+func streamedBy(db *mock.PostgresDB, replicas *streaming) {
+	db.QueryFn = func(_ context.Context, model interface{}, _ string, _ ...interface{}) error {
+		if rows, ok := model.(*[]struct {
+			ApplicationName string
+			State           string
+			SyncState       string
+		}); ok {
+			*rows = *replicas
+		}
+		return nil
+	}
+}
+
+// syncStandbyNames returns the synchronous_standby_names set since the first statements.
+func syncStandbyNames(db *mock.PostgresDB, since int) []string {
+	var names []string
+	for _, statement := range db.GetStatements()[since:] {
+		if strings.HasPrefix(statement, "ALTER SYSTEM SET synchronous_standby_names") {
+			names = append(names, strings.TrimPrefix(statement, "ALTER SYSTEM SET synchronous_standby_names TO "))
+		}
+	}
+	return names
+}
+
+func TestPostgres_when_synchronize_then_keep_the_synchronous_standby_while_it_follows(t *testing.T) {
+	t.Logf("Given a primary in synchronous mode")
+	{
+		pg, db, _ := newPrimary(t, func(spec *postgres.ConfigSpec) { spec.SynchronousMode = postgres.SyncOn })
+		replicas := &streaming{{ApplicationName: "postgres-02", State: "streaming", SyncState: "async"}}
+		streamedBy(db, replicas)
+		since := len(db.GetStatements())
+		a, b := node.Spec{Name: "postgres-02"}, node.Spec{Name: "postgres-03"}
+
+		t.Logf("\tWhen the only follower is not synchronous yet")
+		{
+			if _, err := pg.Synchronize(context.Background(), []node.Spec{a}); err == nil {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected an error but actual is none", testutils.Failed)
+			}
+			if names := syncStandbyNames(db, since); !reflect.DeepEqual(names, []string{`'"postgres-02"'`}) {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected <%v> but actual is <%v>", testutils.Failed, `'"postgres-02"'`, names)
+			}
+			t.Logf("\t\t%s Then it must be the synchronous standby.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen another follower joins ahead of it")
+		{
+			*replicas = streaming{
+				{ApplicationName: "postgres-03", State: "streaming", SyncState: "potential"},
+				{ApplicationName: "postgres-02", State: "streaming", SyncState: "sync"},
+			}
+			standby, err := pg.Synchronize(context.Background(), []node.Spec{b, a})
+			if err != nil || standby != "postgres-02" {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected <postgres-02> but actual is <%s> and <%v>", testutils.Failed, standby, err)
+			}
+			if names := syncStandbyNames(db, since); len(names) != 1 {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected no change but actual is <%v>", testutils.Failed, names)
+			}
+			t.Logf("\t\t%s Then it must stay the synchronous standby.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_synchronize_strictly_without_follower_then_block_the_writes(t *testing.T) {
+	t.Logf("Given a primary in strict synchronous mode")
+	{
+		pg, db, _ := newPrimary(t, func(spec *postgres.ConfigSpec) { spec.SynchronousMode = postgres.SyncStrict })
+		replicas := &streaming{{ApplicationName: "postgres-02", State: "streaming", SyncState: "sync"}}
+		streamedBy(db, replicas)
+		since := len(db.GetStatements())
+		if _, err := pg.Synchronize(context.Background(), []node.Spec{{Name: "postgres-02"}}); err != nil {
+			t.Fatalf("\t\t%s FAIL: Synchronize, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+
+		t.Logf("\tWhen there is no follower anymore")
+		{
+			*replicas = streaming{}
+			if _, err := pg.Synchronize(context.Background(), nil); err == nil {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected an error but actual is none", testutils.Failed)
+			}
+			expected := []string{`'"postgres-02"'`, `'*'`}
+			if names := syncStandbyNames(db, since); !reflect.DeepEqual(names, expected) {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected <%v> but actual is <%v>", testutils.Failed, expected, names)
+			}
+			t.Logf("\t\t%s Then it must wait for any standby.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_synchronize_without_follower_then_replicate_asynchronously(t *testing.T) {
+	t.Logf("Given a primary in synchronous mode")
+	{
+		pg, db, _ := newPrimary(t, func(spec *postgres.ConfigSpec) { spec.SynchronousMode = postgres.SyncOn })
+		replicas := &streaming{{ApplicationName: "postgres-02", State: "streaming", SyncState: "sync"}}
+		streamedBy(db, replicas)
+		since := len(db.GetStatements())
+		if _, err := pg.Synchronize(context.Background(), []node.Spec{{Name: "postgres-02"}}); err != nil {
+			t.Fatalf("\t\t%s FAIL: Synchronize, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+
+		t.Logf("\tWhen there is no follower anymore")
+		{
+			*replicas = streaming{}
+			if standby, err := pg.Synchronize(context.Background(), nil); err != nil || standby != "" {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected no standby but actual is <%s> and <%v>", testutils.Failed, standby, err)
+			}
+			expected := []string{`'"postgres-02"'`, `''`}
+			if names := syncStandbyNames(db, since); !reflect.DeepEqual(names, expected) {
+				t.Fatalf("\t\t%s FAIL: Synchronize, expected <%v> but actual is <%v>", testutils.Failed, expected, names)
+			}
+			t.Logf("\t\t%s Then it must not wait for any standby.", testutils.Succeed)
+		}
+	}
+}
+
+// slots is what pg_replication_slots tells about the slots of nominee.
+type slots []struct {
+	SlotName string
+	Active   bool
+}
+
+// keptIn serves the slots and records the dropped ones.
+func keptIn(db *mock.PostgresDB, replication *slots) *[]string {
+	dropped := &[]string{}
+	db.QueryFn = func(_ context.Context, model interface{}, _ string, _ ...interface{}) error {
+		if rows, ok := model.(*[]struct {
+			SlotName string
+			Active   bool
+		}); ok {
+			*rows = *replication
+		}
+		return nil
+	}
+	db.ExecFn = func(_ context.Context, query string, params ...interface{}) error {
+		if strings.Contains(query, "pg_drop_replication_slot") {
+			*dropped = append(*dropped, params[0].(string))
+		}
+		return nil
+	}
+	return dropped
+}
+
+func TestPostgres_when_a_follower_is_gone_then_drop_its_slot_after_the_grace_period(t *testing.T) {
+	t.Logf("Given a primary with the slot of a follower gone")
+	{
+		pg, db, _ := newPrimary(t, func(spec *postgres.ConfigSpec) { spec.SlotsGracePeriod = 100 * time.Millisecond })
+		dropped := keptIn(db, &slots{{SlotName: "nominee_postgres_02"}})
+
+		t.Logf("\tWhen the slot is orphan for less than the grace period")
+		{
+			for i := 0; i < 2; i++ {
+				if err := pg.KeepSlots(context.Background(), nil); err != nil {
+					t.Fatalf("\t\t%s FAIL: KeepSlots, expected no error but actual is <%v>", testutils.Failed, err)
+				}
+			}
+			if len(*dropped) != 0 {
+				t.Fatalf("\t\t%s FAIL: KeepSlots, expected no slot dropped but actual is <%v>", testutils.Failed, *dropped)
+			}
+			t.Logf("\t\t%s Then the slot must be kept.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the slot is orphan for longer than the grace period")
+		{
+			time.Sleep(150 * time.Millisecond)
+			if err := pg.KeepSlots(context.Background(), nil); err != nil {
+				t.Fatalf("\t\t%s FAIL: KeepSlots, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if !reflect.DeepEqual(*dropped, []string{"nominee_postgres_02"}) {
+				t.Fatalf("\t\t%s FAIL: KeepSlots, expected <nominee_postgres_02> dropped but actual is <%v>", testutils.Failed, *dropped)
+			}
+			t.Logf("\t\t%s Then the slot must be dropped.", testutils.Succeed)
+		}
+	}
+}
+
+func TestPostgres_when_a_slot_is_active_then_never_drop_it(t *testing.T) {
+	t.Logf("Given a primary with the slot of a follower gone but still streaming")
+	{
+		pg, db, _ := newPrimary(t, func(spec *postgres.ConfigSpec) { spec.SlotsGracePeriod = 0 })
+		dropped := keptIn(db, &slots{{SlotName: "nominee_postgres_02", Active: true}})
+
+		t.Logf("\tWhen the slot is orphan for longer than the grace period")
+		{
+			for i := 0; i < 3; i++ {
+				if err := pg.KeepSlots(context.Background(), nil); err != nil {
+					t.Fatalf("\t\t%s FAIL: KeepSlots, expected no error but actual is <%v>", testutils.Failed, err)
+				}
+			}
+			if len(*dropped) != 0 {
+				t.Fatalf("\t\t%s FAIL: KeepSlots, expected no slot dropped but actual is <%v>", testutils.Failed, *dropped)
+			}
+			t.Logf("\t\t%s Then the slot must be kept.", testutils.Succeed)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package raft
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/node"
+)
+
+// Raft ...
+type Raft struct {
+	*ConfigSpec
+	ctx    context.Context
+	cancel func()
+}
+
+var (
+	log *logrus.Entry
+)
+
+// NewRaft ...
+func NewRaft(cl ConfigLoader) *Raft {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Raft{
+		ConfigSpec: cl.GetSpec(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Cleanup ...
+func (r *Raft) Cleanup() {
+	r.cancel()
+}
+
+func (r *Raft) toNodeSpec(id string, spec node.Spec) node.Spec {
+	spec.ElectionKey = fmt.Sprintf("%s/%s", r.electionKey(), id)
+	return spec
+}
+
+func (r *Raft) electionKey() string {
+	return fmt.Sprintf("nominee/domain/%s/cluster/%s", r.Domain, r.Cluster)
+}
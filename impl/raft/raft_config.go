@@ -0,0 +1,73 @@
+package raft
+
+import (
+	"context"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"strings"
+	"time"
+)
+
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	NodeID           string
+	Peers            map[string]string
+	DataDir          string
+	HeartbeatTimeout time.Duration
+	ElectionTimeout  time.Duration
+	Loaded           bool
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{BasicConfig: config.NewBasicConfig()}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	config.SetDefault("NOMINEE_RAFT_DATA_DIR", "/var/lib/nominee/raft")
+	config.SetDefault("NOMINEE_RAFT_HEARTBEAT_TIMEOUT", "1s")
+	config.SetDefault("NOMINEE_RAFT_ELECTION_TIMEOUT", "1s")
+
+	conf.NodeID = config.GetString("NOMINEE_RAFT_NODE_ID")
+	conf.Peers = parsePeers(config.GetStringOrPanic("NOMINEE_RAFT_PEERS"))
+	conf.DataDir = config.GetString("NOMINEE_RAFT_DATA_DIR")
+	conf.HeartbeatTimeout = getDurationOrPanic("NOMINEE_RAFT_HEARTBEAT_TIMEOUT")
+	conf.ElectionTimeout = getDurationOrPanic("NOMINEE_RAFT_ELECTION_TIMEOUT")
+	conf.Loaded = true
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	if !conf.Loaded {
+		panic("config not loaded.")
+	}
+	return conf
+}
+
+// parsePeers parses the list of the cluster members in the form id1=host1:port1,id2=host2:port2...
+func parsePeers(value string) map[string]string {
+	peers := make(map[string]string)
+	for _, peer := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(peer), "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			panic(fmt.Sprintf("malformed raft peer <%s>, expected <id=host:port>.", peer))
+		}
+		peers[parts[0]] = parts[1]
+	}
+	return peers
+}
+
+func getDurationOrPanic(key string) time.Duration {
+	duration, err := time.ParseDuration(config.GetStringOrPanic(key))
+	if err != nil {
+		panic(err)
+	}
+	return duration
+}
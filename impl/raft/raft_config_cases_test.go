@@ -0,0 +1,76 @@
+package raft_test
+
+type configurationExample struct {
+	description      string
+	cluster          string
+	domain           string
+	nodeID           string
+	peers            string
+	dataDir          string
+	heartbeatTimeout string
+	electionTimeout  string
+	expectedPeers    map[string]string
+}
+
+var validExamples = []configurationExample{
+	{
+		description:   "minimum configuration",
+		cluster:       "nominee",
+		domain:        "postgres",
+		peers:         "node-1=10.0.0.1:7000",
+		expectedPeers: map[string]string{"node-1": "10.0.0.1:7000"},
+	},
+	{
+		description:      "full configuration",
+		cluster:          "cluster-002",
+		domain:           "foo",
+		nodeID:           "node-2",
+		peers:            "node-1=10.0.0.1:7000,node-2=10.0.0.2:7000,node-3=10.0.0.3:7000",
+		dataDir:          "/data/raft",
+		heartbeatTimeout: "500ms",
+		electionTimeout:  "2s",
+		expectedPeers:    map[string]string{"node-1": "10.0.0.1:7000", "node-2": "10.0.0.2:7000", "node-3": "10.0.0.3:7000"},
+	},
+	{
+		description:      "full configuration",
+		cluster:          "cluster-003",
+		domain:           "domain-007",
+		nodeID:           "pg-a",
+		peers:            "pg-a=pg-a.priv:7946, pg-b=pg-b.priv:7946",
+		dataDir:          "/tmp/nominee",
+		heartbeatTimeout: "3s",
+		electionTimeout:  "3s",
+		expectedPeers:    map[string]string{"pg-a": "pg-a.priv:7946", "pg-b": "pg-b.priv:7946"},
+	},
+}
+
+var invalidExamples = []configurationExample{
+	{
+		description: "the cluster name is missing",
+		domain:      "postgres",
+		peers:       "node-1=10.0.0.1:7000",
+	},
+	{
+		description: "the domain name is missing",
+		cluster:     "nominee",
+		peers:       "node-1=10.0.0.1:7000",
+	},
+	{
+		description: "the peers are missing",
+		cluster:     "nominee",
+		domain:      "postgres",
+	},
+	{
+		description: "a peer is malformed",
+		cluster:     "nominee",
+		domain:      "postgres",
+		peers:       "node-1=10.0.0.1:7000,node-2",
+	},
+	{
+		description:      "the heartbeat timeout is not a duration",
+		cluster:          "nominee",
+		domain:           "postgres",
+		peers:            "node-1=10.0.0.1:7000",
+		heartbeatTimeout: "often",
+	},
+}
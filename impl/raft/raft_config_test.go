@@ -0,0 +1,100 @@
+package raft_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/raft"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRaftConfig_it_must_load_all_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := raft.NewConfigLoader()
+			loader.Load(context.TODO())
+			raftConfig := loader.GetSpec()
+			if raftConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, raftConfig.Cluster)
+			}
+
+			if raftConfig.Domain != example.domain {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, raftConfig.Domain)
+			}
+
+			if raftConfig.NodeID != example.nodeID {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeID, expected <%s> but actual is <%s>", testutils.Failed, example.nodeID, raftConfig.NodeID)
+			}
+
+			if !reflect.DeepEqual(raftConfig.Peers, example.expectedPeers) {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Peers, expected <%v> but actual is <%v>", testutils.Failed, example.expectedPeers, raftConfig.Peers)
+			}
+
+			expectedDataDir := example.dataDir
+			if expectedDataDir == "" {
+				expectedDataDir = "/var/lib/nominee/raft"
+			}
+			if raftConfig.DataDir != expectedDataDir {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.DataDir, expected <%s> but actual is <%s>", testutils.Failed, expectedDataDir, raftConfig.DataDir)
+			}
+
+			expectedHeartbeat := durationOrDefault(example.heartbeatTimeout, time.Second)
+			if raftConfig.HeartbeatTimeout != expectedHeartbeat {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.HeartbeatTimeout, expected <%v> but actual is <%v>", testutils.Failed, expectedHeartbeat, raftConfig.HeartbeatTimeout)
+			}
+
+			expectedElection := durationOrDefault(example.electionTimeout, time.Second)
+			if raftConfig.ElectionTimeout != expectedElection {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.ElectionTimeout, expected <%v> but actual is <%v>", testutils.Failed, expectedElection, raftConfig.ElectionTimeout)
+			}
+		})
+	}
+}
+
+func TestRaftConfig_it_must_panic_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run("", func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			raftConfig := raft.NewConfigLoader()
+			raftConfig.Load(context.TODO())
+		})
+	}
+}
+
+func durationOrDefault(value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	duration, _ := time.ParseDuration(value)
+	return duration
+}
+
+func declareConfigurationExample(example configurationExample) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_RAFT_NODE_ID", example.nodeID)
+	_ = os.Setenv("NOMINEE_RAFT_PEERS", example.peers)
+	_ = os.Setenv("NOMINEE_RAFT_DATA_DIR", example.dataDir)
+	_ = os.Setenv("NOMINEE_RAFT_HEARTBEAT_TIMEOUT", example.heartbeatTimeout)
+	_ = os.Setenv("NOMINEE_RAFT_ELECTION_TIMEOUT", example.electionTimeout)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_RAFT_NODE_ID")
+	_ = os.Unsetenv("NOMINEE_RAFT_PEERS")
+	_ = os.Unsetenv("NOMINEE_RAFT_DATA_DIR")
+	_ = os.Unsetenv("NOMINEE_RAFT_HEARTBEAT_TIMEOUT")
+	_ = os.Unsetenv("NOMINEE_RAFT_ELECTION_TIMEOUT")
+}
@@ -0,0 +1,143 @@
+package raft
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/metrics"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"time"
+)
+
+// Elector ...
+type Elector struct {
+	*Raft
+	*election.DefaultElector
+	member *Member
+	lost   chan struct{}
+}
+
+// NewElector ...
+func NewElector(cl ConfigLoader) *Elector {
+	cl.Load(context.Background())
+	spec := cl.GetSpec()
+	log = logger.G(context.Background()).WithFields(logrus.Fields{"elector": "raft", "domain": spec.Domain, "cluster": spec.Cluster, "member": spec.NodeID})
+	return &Elector{Raft: NewRaft(cl), lost: make(chan struct{}, 1)}
+}
+
+// Run ...
+func (e *Elector) Run(n node.Node) error {
+	log = log.WithFields(logrus.Fields{"daemon": n.GetDaemonName(), "node": n.GetName()})
+	log.Infof("starting...")
+	e.DefaultElector = election.NewElector(n)
+
+	e.member = NewMember(e.ctx, e.ConfigSpec)
+	if err := e.member.Start(); err != nil {
+		return err
+	}
+
+	e.campaign()
+	e.register()
+	e.observe()
+	log.Infof("started.")
+	return nil
+}
+
+// Cleanup ...
+func (e *Elector) Cleanup() {
+	e.Raft.Cleanup()
+	if e.member != nil {
+		e.member.Shutdown()
+	}
+}
+
+// campaign makes the Raft leader the leader of the nodes.
+func (e *Elector) campaign() {
+	ctx, spec := e.ctx, *e.Managed.GetSpec()
+	go func() {
+		for {
+			select {
+			case leader := <-e.member.LeaderCh():
+				if !leader {
+					// Raft has no session with a store, losing the quorum is what loses the session of the others.
+					log.Infof("raft leadership lost.")
+					metrics.SessionReconnected("raft")
+					select {
+					case e.lost <- struct{}{}:
+					default:
+					}
+					continue
+				}
+				log.Infof("raft leadership acquired, campaign as %v...", e.Managed.GetName())
+				if err := e.member.Register(e.NodeID, spec); err != nil {
+					log.Errorf("failed to register: %v", err)
+					continue
+				}
+				if err := e.member.Elect(e.NodeID); err != nil {
+					log.Errorf("failed to elect: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// register sends the node spec to each new Raft leader, so it is known by the whole cluster.
+func (e *Elector) register() {
+	ctx, spec := e.ctx, *e.Managed.GetSpec()
+	go func() {
+		ticker := time.NewTicker(e.HeartbeatTimeout)
+		defer ticker.Stop()
+		registered := ""
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			leader := e.member.Leader()
+			if leader == "" || leader == registered || e.member.IsLeader() {
+				continue
+			}
+			if err := e.member.RegisterTo(leader, spec); err != nil {
+				log.Warnf("failed to register to %s: %v", leader, err)
+				continue
+			}
+			registered = leader
+		}
+	}()
+}
+
+// observe is the only one to update the leader. It never promotes the node while this member is not
+// the Raft leader, even when the replicated state says otherwise (e.g. the state replayed after a restart).
+func (e *Elector) observe() {
+	ctx := e.ctx
+	go func() {
+		var current *node.Spec
+		for {
+			state, changed := e.member.fsm.current()
+			if leader := state.LeaderSpec(); leader != nil {
+				spec := e.toNodeSpec(state.Leader, *leader)
+				mine := state.Leader == e.NodeID
+				if (current == nil || *current != spec) && (!mine || e.member.IsLeader()) {
+					current = &spec
+					_ = e.UpdateLeader(current)
+				}
+			}
+
+			select {
+			case <-changed:
+			case <-e.lost:
+				if current != nil && current.Name == e.Managed.GetName() {
+					current = &node.Spec{}
+					_ = e.UpdateLeader(current)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
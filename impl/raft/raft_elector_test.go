@@ -0,0 +1,250 @@
+package raft_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/raft"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func TestRaftElector_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			election.TestElector(t, func() election.Elector {
+				// The second member never starts: no quorum, so no election settles by itself.
+				return raft.NewElector(example.config(t, "member-1", peers(t, 2)))
+			})
+		})
+	}
+}
+
+func TestRaftElector_when_not_a_peer_then_fail_to_run(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			elector := raft.NewElector(example.config(t, "stranger", peers(t, 1)))
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err == nil {
+				t.Fatalf("\t\t%s FAIL: RaftElector, expected to fail to run. Actually not.", testutils.Failed)
+			}
+		})
+	}
+}
+
+func TestRaftElector_must_persist_the_log_under_the_data_dir(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			conf := example.config(t, "member-1", peers(t, 2))
+			elector := raft.NewElector(conf)
+			defer elector.Cleanup()
+
+			if err := elector.Run(mock.NewNode(t, example.nodeSpec)); err != nil {
+				t.Fatalf("\t\t%s FATAL: RaftElector, %v", testutils.Failed, err)
+			}
+
+			if _, err := os.Stat(filepath.Join(conf.DataDir, "raft.db")); err != nil {
+				t.Fatalf("\t\t%s FAIL: RaftElector, expected the log under <%s>. %v", testutils.Failed, conf.DataDir, err)
+			}
+		})
+	}
+}
+
+func TestRaftElector_when_alone_then_promote_the_node(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			elector := raft.NewElector(example.config(t, "member-1", peers(t, 1)))
+			defer elector.Cleanup()
+
+			mutex := &sync.Mutex{}
+			var leader node.Spec
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(_ context.Context, spec node.Spec) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				leader = spec
+				return nil
+			}
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: RaftElector, %v", testutils.Failed, err)
+			}
+
+			eventually(t, func() bool {
+				mutex.Lock()
+				defer mutex.Unlock()
+				return leader.Name == example.nodeSpec.Name
+			})
+
+			expectedKey := fmt.Sprintf("%s/member-1", example.electionKey())
+			if leader.ElectionKey != expectedKey {
+				t.Fatalf("\t\t%s FAIL: RaftElector, expected the election key <%s> but actual is <%s>", testutils.Failed, expectedKey, leader.ElectionKey)
+			}
+		})
+	}
+}
+
+func TestRaftElector_when_the_leader_leaves_then_elect_another_one(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			cluster := newCluster(t, example, 3)
+			defer cluster.cleanup()
+
+			var first string
+			eventually(t, func() bool {
+				first = cluster.leader()
+				return first != "" && cluster.followers(first) == 2
+			})
+
+			cluster.electors[first].Cleanup()
+			delete(cluster.electors, first)
+
+			eventually(t, func() bool {
+				second := cluster.leader()
+				return second != "" && second != first && cluster.followers(second) == 1
+			})
+		})
+	}
+}
+
+func TestRaftElector_when_the_leadership_is_lost_then_demote_without_following(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			cluster := newCluster(t, example, 3)
+			defer cluster.cleanup()
+
+			var first string
+			eventually(t, func() bool {
+				first = cluster.leader()
+				return first != "" && cluster.followers(first) == 2
+			})
+
+			for id, elector := range cluster.electors {
+				if id != first {
+					elector.Cleanup()
+					delete(cluster.electors, id)
+				}
+			}
+
+			eventually(t, func() bool {
+				cluster.mutex.Lock()
+				defer cluster.mutex.Unlock()
+				return cluster.demoted[first]
+			})
+
+			cluster.mutex.Lock()
+			defer cluster.mutex.Unlock()
+			if cluster.followedNobody[first] {
+				t.Fatalf("\t\t%s FAIL: Follow, expected no call with an empty leader but actual is one", testutils.Failed)
+			}
+		})
+	}
+}
+
+type cluster struct {
+	mutex          *sync.Mutex
+	electors       map[string]*raft.Elector
+	leaders        map[string]string
+	follows        map[string]string
+	demoted        map[string]bool
+	followedNobody map[string]bool
+	peers          map[string]string
+}
+
+// newCluster runs n electors, one per Raft member, all in the same process.
+func newCluster(t *testing.T, example exampleSpec, n int) *cluster {
+	c := &cluster{mutex: &sync.Mutex{}, electors: make(map[string]*raft.Elector), leaders: make(map[string]string), follows: make(map[string]string),
+		demoted: make(map[string]bool), followedNobody: make(map[string]bool)}
+	members := peers(t, n)
+	c.peers = members
+	ids := make([]string, 0, n)
+	for id := range members {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		id := id
+		nod := mock.NewNode(t, &node.Spec{Name: id, Address: example.nodeSpec.Address, Port: example.nodeSpec.Port})
+		nod.LeadFn = func(context.Context, node.Spec) error {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.leaders[id] = id
+			delete(c.follows, id)
+			return nil
+		}
+		nod.FollowFn = func(_ context.Context, leader node.Spec) error {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.follows[id] = leader.Name
+			if leader.Name == "" {
+				c.followedNobody[id] = true
+			}
+			return nil
+		}
+		nod.DemoteFn = func(context.Context) error {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.demoted[id] = true
+			delete(c.leaders, id)
+			return nil
+		}
+		nod.StonithFn = func(context.Context) {}
+
+		elector := raft.NewElector(example.config(t, id, members))
+		if err := elector.Run(nod); err != nil {
+			t.Fatalf("\t\t%s FATAL: RaftElector, %v", testutils.Failed, err)
+		}
+		c.electors[id] = elector
+	}
+	return c
+}
+
+// leader returns the running member that promoted its node, empty if none or many.
+func (c *cluster) leader() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	leader := ""
+	for id := range c.leaders {
+		if _, running := c.electors[id]; !running {
+			continue
+		}
+		if leader != "" {
+			return ""
+		}
+		leader = id
+	}
+	return leader
+}
+
+// followers counts the running members following the leader.
+func (c *cluster) followers(leader string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	count := 0
+	for id, followed := range c.follows {
+		if _, running := c.electors[id]; running && followed == leader {
+			count++
+		}
+	}
+	return count
+}
+
+func (c *cluster) cleanup() {
+	for _, elector := range c.electors {
+		elector.Cleanup()
+	}
+}
@@ -0,0 +1,79 @@
+package raft_test
+
+import (
+	"fmt"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/impl/raft"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"testing"
+	"time"
+)
+
+type exampleSpec struct {
+	description string
+	cluster     string
+	domain      string
+	nodeSpec    *node.Spec
+}
+
+func (example exampleSpec) config(t *testing.T, nodeID string, peers map[string]string) *mock.RaftConfigSpec {
+	return &mock.RaftConfigSpec{
+		ConfigSpec: &raft.ConfigSpec{
+			NodeID:           nodeID,
+			Peers:            peers,
+			DataDir:          t.TempDir(),
+			HeartbeatTimeout: 100 * time.Millisecond,
+			ElectionTimeout:  100 * time.Millisecond,
+			BasicConfig:      &config.BasicConfig{Cluster: example.cluster, Domain: example.domain},
+		},
+	}
+}
+
+func (example exampleSpec) electionKey() string {
+	return fmt.Sprintf("nominee/domain/%s/cluster/%s", example.domain, example.cluster)
+}
+
+// peers returns n members listening on free loopback addresses, named member-1...member-n.
+func peers(t *testing.T, n int) map[string]string {
+	members := make(map[string]string, n)
+	for i := 1; i <= n; i++ {
+		members[fmt.Sprintf("member-%d", i)] = mock.FreeAddress(t)
+	}
+	return members
+}
+
+var examples = []exampleSpec{
+	{
+		description: "one node cluster",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeSpec:    &node.Spec{Name: "nominee-1", Address: "nominee-1", Port: 1245},
+	},
+	{
+		description: "three nodes cluster",
+		cluster:     "cluster-501",
+		domain:      "domain-981",
+		nodeSpec:    &node.Spec{Name: "nominee-2", Address: "nominee-2", Port: 3254},
+	},
+	{
+		description: "another domain",
+		cluster:     "cluster-777",
+		domain:      "domain-113",
+		nodeSpec:    &node.Spec{Name: "nominee-3", Address: "nominee-3", Port: 9778},
+	},
+}
+
+// eventually is testutils.AsyncAssertion.ItMustBeTrue with a settle time long enough for a Raft election.
+func eventually(t *testing.T, assert func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if assert() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("\t\t%s FATAL: expected to be true. Actually it returns false", testutils.Failed)
+}
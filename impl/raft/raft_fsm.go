@@ -0,0 +1,158 @@
+package raft
+
+import (
+	"context"
+	"encoding/json"
+	hraft "github.com/hashicorp/raft"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"io"
+	"sync"
+)
+
+const (
+	registerCommand = "register"
+	leaderCommand   = "leader"
+)
+
+type command struct {
+	Type string
+	ID   string
+	Spec node.Spec
+}
+
+// State is the view of the cluster replicated by Raft.
+type State struct {
+	Index   uint64
+	Leader  string
+	Members map[string]node.Spec
+}
+
+// LeaderSpec returns nil when no leader has been recorded yet.
+func (s State) LeaderSpec() *node.Spec {
+	if spec, ok := s.Members[s.Leader]; ok {
+		return &spec
+	}
+	return nil
+}
+
+// Followers ...
+func (s State) Followers() map[string]node.Spec {
+	followers := make(map[string]node.Spec, len(s.Members))
+	for id, spec := range s.Members {
+		if id != s.Leader {
+			followers[id] = spec
+		}
+	}
+	return followers
+}
+
+type fsm struct {
+	mutex   *sync.Mutex
+	state   State
+	changed chan struct{}
+}
+
+type fsmSnapshot struct {
+	data []byte
+}
+
+func newFSM() *fsm {
+	return &fsm{
+		mutex:   &sync.Mutex{},
+		state:   State{Members: make(map[string]node.Spec)},
+		changed: make(chan struct{}),
+	}
+}
+
+// Apply ...
+func (f *fsm) Apply(l *hraft.Log) interface{} {
+	var cmd command
+	if err := json.Unmarshal(l.Data, &cmd); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	switch cmd.Type {
+	case registerCommand:
+		f.state.Members[cmd.ID] = cmd.Spec
+	case leaderCommand:
+		f.state.Leader = cmd.ID
+	}
+	f.state.Index = l.Index
+	f.notify()
+	return nil
+}
+
+// Snapshot ...
+func (f *fsm) Snapshot() (hraft.FSMSnapshot, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	data, err := json.Marshal(f.state)
+	if err != nil {
+		return nil, err
+	}
+	return &fsmSnapshot{data: data}, nil
+}
+
+// Restore ...
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+	state := State{}
+	if err := json.NewDecoder(rc).Decode(&state); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if state.Members == nil {
+		state.Members = make(map[string]node.Spec)
+	}
+	f.state = state
+	f.notify()
+	return nil
+}
+
+// current returns a copy of the state and a channel closed on the next change.
+func (f *fsm) current() (State, <-chan struct{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	state := f.state
+	state.Members = make(map[string]node.Spec, len(f.state.Members))
+	for id, spec := range f.state.Members {
+		state.Members[id] = spec
+	}
+	return state, f.changed
+}
+
+// wait blocks until the state is newer than the index or the context is done.
+func (f *fsm) wait(ctx context.Context, index uint64) State {
+	for {
+		state, changed := f.current()
+		if state.Index > index {
+			return state
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return state
+		}
+	}
+}
+
+func (f *fsm) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// Persist ...
+func (s *fsmSnapshot) Persist(sink hraft.SnapshotSink) error {
+	if _, err := sink.Write(s.data); err != nil {
+		_ = sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+// Release ...
+func (s *fsmSnapshot) Release() {}
@@ -0,0 +1,181 @@
+package raft
+
+import (
+	"context"
+	"encoding/json"
+	hraft "github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	stateWaitTimeout = 10 * time.Second
+	rpcTimeout       = 10 * time.Second
+)
+
+// Member is the Raft node embedded in an elector. Its log and snapshots are persisted under the data directory,
+// so a restarted member rejoins the cluster with its former state.
+type Member struct {
+	*ConfigSpec
+	ctx       context.Context
+	raft      *hraft.Raft
+	fsm       *fsm
+	layer     *streamLayer
+	store     *raftboltdb.BoltStore
+	transport *hraft.NetworkTransport
+	output    *io.PipeWriter
+}
+
+// NewMember ...
+func NewMember(ctx context.Context, config *ConfigSpec) *Member {
+	return &Member{ConfigSpec: config, ctx: ctx, fsm: newFSM()}
+}
+
+// Start ...
+func (m *Member) Start() error {
+	address, ok := m.Peers[m.NodeID]
+	if !ok {
+		return errors.Errorf("the node <%s> is not one of the raft peers %v", m.NodeID, m.Peers)
+	}
+
+	if err := os.MkdirAll(m.DataDir, 0700); err != nil {
+		return err
+	}
+
+	var err error
+	m.output = log.WriterLevel(logrus.DebugLevel)
+	if m.store, err = raftboltdb.NewBoltStore(filepath.Join(m.DataDir, "raft.db")); err != nil {
+		return err
+	}
+
+	snapshots, err := hraft.NewFileSnapshotStore(m.DataDir, 2, m.output)
+	if err != nil {
+		return err
+	}
+
+	if m.layer, err = newStreamLayer(address, m.serve); err != nil {
+		return err
+	}
+	m.transport = hraft.NewNetworkTransport(m.layer, 3, rpcTimeout, m.output)
+
+	conf := hraft.DefaultConfig()
+	conf.LocalID = hraft.ServerID(m.NodeID)
+	conf.HeartbeatTimeout = m.HeartbeatTimeout
+	conf.ElectionTimeout = m.ElectionTimeout
+	conf.LeaderLeaseTimeout = m.HeartbeatTimeout / 2
+	conf.LogOutput = m.output
+	if m.raft, err = hraft.NewRaft(conf, m.fsm, m.store, m.store, snapshots, m.transport); err != nil {
+		return err
+	}
+
+	existing, err := hraft.HasExistingState(m.store, m.store, snapshots)
+	if err != nil {
+		return err
+	}
+
+	if !existing {
+		log.Infof("bootstrap the raft cluster %v...", m.Peers)
+		servers := make([]hraft.Server, 0, len(m.Peers))
+		for id, address := range m.Peers {
+			servers = append(servers, hraft.Server{ID: hraft.ServerID(id), Address: hraft.ServerAddress(address)})
+		}
+		if err := m.raft.BootstrapCluster(hraft.Configuration{Servers: servers}).Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown ...
+func (m *Member) Shutdown() {
+	if m.raft != nil {
+		_ = m.raft.Shutdown().Error()
+	}
+	if m.transport != nil {
+		_ = m.transport.Close()
+	}
+	if m.store != nil {
+		_ = m.store.Close()
+	}
+	if m.output != nil {
+		_ = m.output.Close()
+	}
+}
+
+// LeaderCh ...
+func (m *Member) LeaderCh() <-chan bool {
+	return m.raft.LeaderCh()
+}
+
+// Register records the node spec of a member. It must be called on the Raft leader.
+func (m *Member) Register(id string, spec node.Spec) error {
+	return m.apply(command{Type: registerCommand, ID: id, Spec: spec})
+}
+
+// Elect records the member as the leader of the nodes. It must be called on the Raft leader.
+func (m *Member) Elect(id string) error {
+	return m.apply(command{Type: leaderCommand, ID: id})
+}
+
+// RegisterTo sends the node spec of this member to the Raft leader listening on the address.
+func (m *Member) RegisterTo(leader string, spec node.Spec) error {
+	_, err := call(leader, rpcTimeout, request{Type: registerRequest, ID: m.NodeID, Spec: spec})
+	return err
+}
+
+// Leader returns the address of the current Raft leader, empty if there is none.
+func (m *Member) Leader() string {
+	return string(m.raft.Leader())
+}
+
+// IsLeader ...
+func (m *Member) IsLeader() bool {
+	return m.raft.State() == hraft.Leader
+}
+
+func (m *Member) apply(cmd command) error {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	future := m.raft.Apply(data, rpcTimeout)
+	if err := future.Error(); err != nil {
+		return err
+	}
+	if err, ok := future.Response().(error); ok {
+		return err
+	}
+	return nil
+}
+
+func (m *Member) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(stateWaitTimeout + rpcTimeout))
+
+	req := request{}
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+
+	resp := response{}
+	switch req.Type {
+	case registerRequest:
+		if err := m.Register(req.ID, req.Spec); err != nil {
+			resp.Error = err.Error()
+		}
+	case stateRequest:
+		ctx, cancel := context.WithTimeout(m.ctx, stateWaitTimeout)
+		resp.State = m.fsm.wait(ctx, req.Index)
+		cancel()
+	default:
+		resp.Error = "unknown request " + req.Type
+	}
+	_ = json.NewEncoder(conn).Encode(resp)
+}
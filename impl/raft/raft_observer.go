@@ -0,0 +1,106 @@
+package raft
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/proxy"
+	"sort"
+	"time"
+)
+
+// Observer does not join the Raft cluster. It follows the replicated state by polling the members.
+type Observer struct {
+	*Raft
+	*election.BasicObserver
+}
+
+// NewObserver ...
+func NewObserver(cl ConfigLoader) *Observer {
+	cl.Load(context.Background())
+	log = logrus.WithFields(logrus.Fields{"observer": "raft"})
+	return &Observer{Raft: NewRaft(cl)}
+}
+
+// Observe ...
+func (observer *Observer) Observe(proxy proxy.Proxy) error {
+	observer.BasicObserver = election.NewBasicObserver(proxy)
+	observer.poll()
+	return nil
+}
+
+// poll asks the members the state newer than the last one received. It moves to the next member
+// when the current one is unreachable.
+func (observer *Observer) poll() {
+	ctx, peers := observer.ctx, observer.peers()
+	go func() {
+		var index uint64
+		var leader *node.Spec
+		followers := make(map[string]*node.Spec)
+		for i := 0; ctx.Err() == nil; {
+			resp, err := call(peers[i%len(peers)], stateWaitTimeout+rpcTimeout, request{Type: stateRequest, Index: index})
+			if err != nil {
+				log.Debugf("member %s unreachable: %v", peers[i%len(peers)], err)
+				i++
+				select {
+				case <-time.After(observer.HeartbeatTimeout):
+				case <-ctx.Done():
+				}
+				continue
+			}
+
+			if resp.State.Index > index {
+				index = resp.State.Index
+				leader, followers = observer.update(resp.State, leader, followers)
+			}
+		}
+	}()
+}
+
+func (observer *Observer) update(state State, leader *node.Spec, known map[string]*node.Spec) (*node.Spec, map[string]*node.Spec) {
+	current := make(map[string]*node.Spec)
+	for id, spec := range state.Followers() {
+		decoded := observer.toNodeSpec(id, spec)
+		current[id] = &decoded
+	}
+
+	for id, spec := range known {
+		if _, ok := current[id]; !ok {
+			if err := observer.RemoveNodes(spec); err != nil {
+				panic(err)
+			}
+			log.Infof("Node deleted : %s", spec.Marshal())
+		}
+	}
+
+	for id, spec := range current {
+		if previous, ok := known[id]; !ok || *previous != *spec {
+			if err := observer.UpdateNodes(spec); err != nil {
+				panic(err)
+			}
+			log.Infof("node updated : %s", spec.Marshal())
+		}
+	}
+
+	if spec := state.LeaderSpec(); spec != nil {
+		decoded := observer.toNodeSpec(state.Leader, *spec)
+		if leader == nil || *leader != decoded {
+			if err := observer.UpdateLeader(&decoded); err != nil {
+				panic(err)
+			}
+			log.Infof("Leader updated : %s", decoded.Marshal())
+			leader = &decoded
+		}
+	}
+	return leader, current
+}
+
+func (observer *Observer) peers() []string {
+	peers := make([]string, 0, len(observer.Peers))
+	for _, address := range observer.Peers {
+		peers = append(peers, address)
+	}
+	sort.Strings(peers)
+	return peers
+}
@@ -0,0 +1,68 @@
+package raft_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/raft"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"testing"
+)
+
+func TestRaftObserver_must_be_conform(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			election.TestObserver(t, func() election.Observer {
+				return raft.NewObserver(example.config(t, "", peers(t, 3)))
+			})
+		})
+	}
+}
+
+func TestRaftObserver_when_a_node_is_elected_then_publish_it_as_leader(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			members := peers(t, 1)
+			observer := raft.NewObserver(example.config(t, "", members))
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: RaftObserver, %v", testutils.Failed, err)
+			}
+
+			nod := mock.NewNode(t, example.nodeSpec)
+			nod.LeadFn = func(context.Context, node.Spec) error { return nil }
+			elector := raft.NewElector(example.config(t, "member-1", members))
+			defer elector.Cleanup()
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: RaftElector, %v", testutils.Failed, err)
+			}
+
+			eventually(t, func() bool {
+				return proxy.Leader != nil && proxy.Leader.Name == example.nodeSpec.Name
+			})
+		})
+	}
+}
+
+func TestRaftObserver_must_publish_the_other_members_as_followers(t *testing.T) {
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			c := newCluster(t, example, 3)
+			defer c.cleanup()
+
+			observer := raft.NewObserver(example.config(t, "", c.peers))
+			defer observer.Cleanup()
+			proxy := mock.NewProxy()
+			if err := observer.Observe(proxy); err != nil {
+				t.Fatalf("\t\t%s FATAL: RaftObserver, %v", testutils.Failed, err)
+			}
+
+			eventually(t, func() bool {
+				leader := c.leader()
+				return leader != "" && proxy.Leader != nil && proxy.Leader.Name == leader && len(proxy.Followers) == 2
+			})
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	hraft "github.com/hashicorp/raft"
+	"github.com/pkg/errors"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// memberRPC is the first byte of the nominee requests sent to a member. The Raft RPC types go from 0 to 3,
+// so the first byte of a connection is enough to tell both protocols apart on the same port.
+const memberRPC byte = 'N'
+
+const (
+	registerRequest = "register"
+	stateRequest    = "state"
+)
+
+type request struct {
+	Type  string
+	ID    string
+	Spec  node.Spec
+	Index uint64
+}
+
+type response struct {
+	Error string
+	State State
+}
+
+// streamLayer implements hraft.StreamLayer. It hands the Raft connections to the transport
+// and the nominee ones to the handler.
+type streamLayer struct {
+	net.Listener
+	advertise net.Addr
+	raftConns chan net.Conn
+	closed    chan struct{}
+	handler   func(net.Conn)
+}
+
+type prefixedConn struct {
+	net.Conn
+	reader io.Reader
+}
+
+func newStreamLayer(address string, handler func(net.Conn)) (*streamLayer, error) {
+	advertise, err := net.ResolveTCPAddr("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("", strconv.Itoa(advertise.Port)))
+	if err != nil {
+		return nil, err
+	}
+
+	layer := &streamLayer{
+		Listener:  listener,
+		advertise: advertise,
+		raftConns: make(chan net.Conn),
+		closed:    make(chan struct{}),
+		handler:   handler,
+	}
+	go layer.serve()
+	return layer, nil
+}
+
+// Accept ...
+func (layer *streamLayer) Accept() (net.Conn, error) {
+	select {
+	case conn := <-layer.raftConns:
+		return conn, nil
+	case <-layer.closed:
+		return nil, errors.New("raft transport closed")
+	}
+}
+
+// Close ...
+func (layer *streamLayer) Close() error {
+	select {
+	case <-layer.closed:
+		return nil
+	default:
+		close(layer.closed)
+	}
+	return layer.Listener.Close()
+}
+
+// Addr ...
+func (layer *streamLayer) Addr() net.Addr {
+	return layer.advertise
+}
+
+// Dial ...
+func (layer *streamLayer) Dial(address hraft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", string(address), timeout)
+}
+
+func (layer *streamLayer) serve() {
+	for {
+		conn, err := layer.Listener.Accept()
+		if err != nil {
+			return
+		}
+		go layer.dispatch(conn)
+	}
+}
+
+func (layer *streamLayer) dispatch(conn net.Conn) {
+	first := make([]byte, 1)
+	if _, err := io.ReadFull(conn, first); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	if first[0] == memberRPC {
+		layer.handler(conn)
+		return
+	}
+
+	select {
+	case layer.raftConns <- &prefixedConn{Conn: conn, reader: io.MultiReader(bytes.NewReader(first), conn)}:
+	case <-layer.closed:
+		_ = conn.Close()
+	}
+}
+
+// Read ...
+func (conn *prefixedConn) Read(p []byte) (int, error) {
+	return conn.reader.Read(p)
+}
+
+// call sends a nominee request to the member listening on the address.
+func call(address string, timeout time.Duration, req request) (*response, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := conn.Write([]byte{memberRPC}); err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+
+	resp := &response{}
+	if err := json.NewDecoder(conn).Decode(resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,142 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"strconv"
+	"time"
+)
+
+type status int
+
+const (
+	started status = iota
+	stopped
+)
+
+const (
+	defaultWaitRetry = time.Second * 2
+	defaultRetries   = 3
+)
+
+var (
+	log = logrus.WithFields(logrus.Fields{"daemon": "redis"})
+)
+
+// Redis ...
+type Redis struct {
+	*node.Spec
+	Daemon   Daemon
+	cluster  string
+	domain   string
+	password string
+	client   *goredis.Client
+	status   status
+	leader   node.Spec
+}
+
+// NewRedis ...
+func NewRedis(cl ConfigLoader) *Redis {
+	cl.Load(context.Background())
+	config := cl.GetSpec()
+	r := &Redis{
+		Spec:     &config.NodeSpec,
+		Daemon:   NewEntrypointDaemon(config.NodeSpec.Port, config.Password),
+		cluster:  config.Cluster,
+		domain:   config.Domain,
+		password: config.Password,
+		client: goredis.NewClient(&goredis.Options{
+			Addr:     fmt.Sprintf("127.0.0.1:%d", config.NodeSpec.Port),
+			Password: config.Password,
+		}),
+		status: stopped,
+	}
+
+	log = logrus.WithFields(logrus.Fields{"daemon": r.GetDaemonName(), "node": r.GetName()})
+	return r
+}
+
+// GetDaemonName ...
+func (r *Redis) GetDaemonName() string {
+	return "redis"
+}
+
+// Lead ...
+func (r *Redis) Lead(ctx context.Context, myself node.Spec) error {
+	log.Infof("redis: promote to primary as %v ...\n", myself.Name)
+	r.leader = myself
+
+	if err := r.start(ctx); err != nil {
+		return err
+	}
+
+	return r.client.Do(ctx, "REPLICAOF", "NO", "ONE").Err()
+}
+
+// Follow ...
+func (r *Redis) Follow(ctx context.Context, leader node.Spec) error {
+	log.Infof("redis: following the new leader: %v \n", leader.Name)
+	r.leader = leader
+
+	if err := r.start(ctx); err != nil {
+		return err
+	}
+
+	if r.password != "" {
+		if err := r.client.ConfigSet(ctx, "masterauth", r.password).Err(); err != nil {
+			return err
+		}
+	}
+
+	return r.client.Do(ctx, "REPLICAOF", leader.Address, strconv.FormatInt(leader.Port, 10)).Err()
+}
+
+// Stonith ...
+func (r *Redis) Stonith(ctx context.Context) {
+	log.Infof("redis: stonithing... \n")
+	_ = r.Daemon.Stop(ctx)
+	r.status = stopped
+}
+
+// Done ...
+func (r *Redis) Done() base.DoneChan {
+	return r.Daemon.Done()
+}
+
+func (r *Redis) start(ctx context.Context) error {
+	if r.status == started {
+		return nil
+	}
+
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		if err := r.Daemon.Start(ctx); err != nil {
+			return err
+		}
+		if err := r.warmUp(ctx, defaultRetries); err != nil {
+			return err
+		}
+	}
+
+	r.status = started
+	return nil
+}
+
+// warmUp waits for the server to answer. It answers LOADING errors while it loads the dataset from the disk.
+func (r *Redis) warmUp(ctx context.Context, retries int) error {
+	for i := retries; ; i-- {
+		if err := r.client.Ping(ctx).Err(); err != nil {
+			if i >= 0 {
+				time.Sleep(defaultWaitRetry)
+				continue
+			} else {
+				return err
+			}
+		}
+		break
+	}
+	return nil
+}
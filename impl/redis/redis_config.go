@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+)
+
+// ConfigLoader ...
+type ConfigLoader interface {
+	config.Loader
+	GetSpec() *ConfigSpec
+}
+
+// ConfigSpec ...
+type ConfigSpec struct {
+	*config.BasicConfig
+	NodeSpec node.Spec
+	Password string
+}
+
+// NewConfigLoader ...
+func NewConfigLoader() ConfigLoader {
+	return &ConfigSpec{
+		BasicConfig: config.NewBasicConfig(),
+		NodeSpec:    node.Spec{},
+	}
+}
+
+// LoadConfig ...
+func (conf *ConfigSpec) Load(ctx context.Context) {
+	conf.BasicConfig.Load(ctx)
+	config.SetDefault("NOMINEE_REDIS_NODE_PORT", 6379)
+
+	conf.NodeSpec.Name = config.GetStringOrPanic("NOMINEE_REDIS_NODE_NAME")
+	conf.NodeSpec.Address = config.GetStringOrPanic("NOMINEE_REDIS_NODE_ADDRESS")
+	conf.NodeSpec.Port = int64(config.GetIntOrPanic("NOMINEE_REDIS_NODE_PORT"))
+	conf.NodeSpec.Tags = node.LoadTags("NOMINEE_REDIS_NODE")
+	conf.Password = config.GetString("NOMINEE_REDIS_PASSWORD")
+}
+
+func (conf *ConfigSpec) GetSpec() *ConfigSpec {
+	return conf
+}
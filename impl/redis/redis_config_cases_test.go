@@ -0,0 +1,66 @@
+package redis_test
+
+type configurationExamples struct {
+	description string
+	cluster     string
+	domain      string
+	nodeName    string
+	nodeAddress string
+	nodePort    string
+	password    string
+}
+
+var validExamples = []configurationExamples{
+	{
+		description: "minimum configuration",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeName:    "redis-01",
+		nodeAddress: "node01.redis.priv",
+	},
+	{
+		description: "full configuration #1",
+		cluster:     "cluster-009",
+		domain:      "domain-012",
+		nodeName:    "redis-99",
+		nodeAddress: "node99.redis.priv",
+		nodePort:    "6380",
+		password:    "r3d!$",
+	},
+	{
+		description: "full configuration #2",
+		cluster:     "cluster-209",
+		domain:      "domain-713",
+		nodeName:    "redis-77",
+		nodeAddress: "node77.redis.priv",
+		nodePort:    "7000",
+		password:    "()_+++==MIN$%^&)",
+	},
+}
+
+var invalidExamples = []configurationExamples{
+	{
+		description: "cluster name is missing",
+		domain:      "domain-111",
+		nodeName:    "node-11",
+		nodeAddress: "node11.redis.priv",
+	},
+	{
+		description: "domain name is missing",
+		cluster:     "cluster-991",
+		nodeName:    "redis-71",
+		nodeAddress: "redis71.cache.priv",
+	},
+	{
+		description: "node name is missing",
+		cluster:     "cluster-001",
+		domain:      "domain-001",
+		nodeAddress: "node01.redis.priv",
+	},
+	{
+		description: "node address is missing",
+		cluster:     "cluster-009",
+		domain:      "domain-012",
+		nodeName:    "redis-99",
+	},
+}
@@ -0,0 +1,83 @@
+package redis_test
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/impl/redis"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestRedisConfig_loads_configurations(t *testing.T) {
+	for _, example := range validExamples {
+		t.Run(example.description, func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			loader := redis.NewConfigLoader()
+			loader.Load(context.TODO())
+			redisConfig := loader.GetSpec()
+			if redisConfig.Cluster != example.cluster {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Cluster, expected <%s> but actual is <%s>", testutils.Failed, example.cluster, redisConfig.Cluster)
+			}
+
+			if redisConfig.Domain != example.domain {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Domain, expected <%s> but actual is <%s>", testutils.Failed, example.domain, redisConfig.Domain)
+			}
+
+			if redisConfig.NodeSpec.Name != example.nodeName {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Name, expected <%s> but actual is <%s>", testutils.Failed, example.nodeName, redisConfig.NodeSpec.Name)
+			}
+
+			if redisConfig.NodeSpec.Address != example.nodeAddress {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Address, expected <%s> but actual is <%s>", testutils.Failed, example.nodeAddress, redisConfig.NodeSpec.Address)
+			}
+
+			expectedPort := example.nodePort
+			if expectedPort == "" {
+				expectedPort = "6379"
+			}
+			if strconv.Itoa(int(redisConfig.NodeSpec.Port)) != expectedPort {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.NodeSpec.Port, expected <%s> but actual is <%d>", testutils.Failed, expectedPort, redisConfig.NodeSpec.Port)
+			}
+
+			if redisConfig.Password != example.password {
+				t.Fatalf("\t\t%s FAIL: ConfigSpec.Password, expected <%s> but actual is <%s>", testutils.Failed, example.password, redisConfig.Password)
+			}
+		})
+	}
+}
+
+func TestRedisConfig_panics_when_bad_configuration(t *testing.T) {
+	for _, example := range invalidExamples {
+		t.Run(example.description, func(t *testing.T) {
+			defer tearsDown()
+			declareConfigurationExample(example)
+			defer func() {
+				if r := recover(); r == nil {
+					t.Fatalf("\t\t%s FAIL: ConfigSpec.Load(). Expected the program to panic. Actual not.", testutils.Failed)
+				}
+			}()
+			redisConfig := redis.NewConfigLoader()
+			redisConfig.Load(context.TODO())
+		})
+	}
+}
+
+func declareConfigurationExample(example configurationExamples) {
+	_ = os.Setenv("NOMINEE_CLUSTER_NAME", example.cluster)
+	_ = os.Setenv("NOMINEE_DOMAIN_NAME", example.domain)
+	_ = os.Setenv("NOMINEE_REDIS_NODE_NAME", example.nodeName)
+	_ = os.Setenv("NOMINEE_REDIS_NODE_ADDRESS", example.nodeAddress)
+	_ = os.Setenv("NOMINEE_REDIS_NODE_PORT", example.nodePort)
+	_ = os.Setenv("NOMINEE_REDIS_PASSWORD", example.password)
+}
+
+func tearsDown() {
+	_ = os.Unsetenv("NOMINEE_CLUSTER_NAME")
+	_ = os.Unsetenv("NOMINEE_DOMAIN_NAME")
+	_ = os.Unsetenv("NOMINEE_REDIS_NODE_NAME")
+	_ = os.Unsetenv("NOMINEE_REDIS_NODE_ADDRESS")
+	_ = os.Unsetenv("NOMINEE_REDIS_NODE_PORT")
+	_ = os.Unsetenv("NOMINEE_REDIS_PASSWORD")
+}
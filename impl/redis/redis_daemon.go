@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"github/mlyahmed.io/nominee/pkg/base"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// Daemon runs the redis-server process.
+type Daemon interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Done() base.DoneChan
+}
+
+// EntrypointDaemon runs redis-server through the docker entrypoint.
+type EntrypointDaemon struct {
+	port     int64
+	password string
+	doneCh   chan struct{}
+}
+
+// NewEntrypointDaemon ...
+func NewEntrypointDaemon(port int64, password string) *EntrypointDaemon {
+	return &EntrypointDaemon{port: port, password: password, doneCh: make(chan struct{})}
+}
+
+// Start ...
+func (d *EntrypointDaemon) Start(ctx context.Context) error {
+	args := []string{"redis-server", "--port", strconv.FormatInt(d.port, 10)}
+	if d.password != "" {
+		args = append(args, "--requirepass", d.password, "--masterauth", d.password)
+	}
+	start := exec.CommandContext(ctx, "docker-entrypoint.sh", args...)
+	start.Stdout, start.Stderr = log.Writer(), log.Writer()
+	if err := start.Start(); err != nil {
+		return err
+	}
+
+	go func() {
+		_ = start.Wait() //FIXME:
+		log.Warnf("Run command has returned.")
+		d.doneCh <- struct{}{}
+	}()
+	return nil
+}
+
+// Stop ...
+func (d *EntrypointDaemon) Stop(ctx context.Context) error {
+	stop := exec.CommandContext(ctx, "redis-cli", "-p", strconv.FormatInt(d.port, 10), "shutdown")
+	stop.Env = append(os.Environ(), "REDISCLI_AUTH="+d.password)
+	stop.Stdout, stop.Stderr = log.Writer(), log.Writer()
+	return stop.Run()
+}
+
+// Done ...
+func (d *EntrypointDaemon) Done() base.DoneChan {
+	return d.doneCh
+}
@@ -0,0 +1,121 @@
+package redis_test
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/impl/mock"
+	"github/mlyahmed.io/nominee/impl/redis"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var leader = node.Spec{Name: "redis-01", Address: "node01.redis.priv", Port: 6380}
+
+func newRedis(t *testing.T, password string) (*redis.Redis, *mock.RedisServer, *mock.RedisDaemon) {
+	server := mock.NewRedisServer(t)
+	r := redis.NewRedis(&mock.RedisConfigSpec{ConfigSpec: &redis.ConfigSpec{
+		BasicConfig: &config.BasicConfig{Cluster: "cluster-001", Domain: "domain-001"},
+		NodeSpec:    node.Spec{Name: "redis-02", Address: "node02.redis.priv", Port: server.Port()},
+		Password:    password,
+	}})
+	daemon := mock.NewRedisDaemon()
+	r.Daemon = daemon
+	return r, server, daemon
+}
+
+func TestRedis_when_lead_then_stop_the_replication(t *testing.T) {
+	r, server, _ := newRedis(t, "")
+	defer server.Close()
+
+	if err := r.Lead(context.TODO(), *r.Spec); err != nil {
+		t.Fatalf("\t\t%s FATAL: Redis.Lead, %v", testutils.Failed, err)
+	}
+
+	expected := [][]string{{"REPLICAOF", "NO", "ONE"}}
+	if !reflect.DeepEqual(server.Commands(), expected) {
+		t.Fatalf("\t\t%s FAIL: Redis.Lead, expected <%v> but actual is <%v>", testutils.Failed, expected, server.Commands())
+	}
+}
+
+func TestRedis_when_follow_then_replicate_the_leader(t *testing.T) {
+	r, server, _ := newRedis(t, "")
+	defer server.Close()
+
+	if err := r.Follow(context.TODO(), leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Redis.Follow, %v", testutils.Failed, err)
+	}
+
+	expected := [][]string{{"REPLICAOF", "node01.redis.priv", "6380"}}
+	if !reflect.DeepEqual(server.Commands(), expected) {
+		t.Fatalf("\t\t%s FAIL: Redis.Follow, expected <%v> but actual is <%v>", testutils.Failed, expected, server.Commands())
+	}
+}
+
+func TestRedis_when_protected_then_authenticate_to_the_leader(t *testing.T) {
+	r, server, _ := newRedis(t, "$ecret")
+	defer server.Close()
+
+	if err := r.Follow(context.TODO(), leader); err != nil {
+		t.Fatalf("\t\t%s FATAL: Redis.Follow, %v", testutils.Failed, err)
+	}
+
+	expected := [][]string{
+		{"auth", "$ecret"},
+		{"config", "set", "masterauth", "$ecret"},
+		{"REPLICAOF", "node01.redis.priv", "6380"},
+	}
+	if !reflect.DeepEqual(server.Commands(), expected) {
+		t.Fatalf("\t\t%s FAIL: Redis.Follow, expected <%v> but actual is <%v>", testutils.Failed, expected, server.Commands())
+	}
+}
+
+func TestRedis_when_the_server_refuses_then_return_the_error(t *testing.T) {
+	r, server, _ := newRedis(t, "")
+	defer server.Close()
+	server.Reply(func(command []string) string {
+		if command[0] == "REPLICAOF" {
+			return "-ERR REPLICAOF not allowed in cluster mode.\r\n"
+		}
+		return mock.RedisOK(command)
+	})
+
+	if err := r.Lead(context.TODO(), *r.Spec); err == nil {
+		t.Fatalf("\t\t%s FAIL: Redis.Lead, expected an error. Actually not.", testutils.Failed)
+	}
+
+	if err := r.Follow(context.TODO(), leader); err == nil {
+		t.Fatalf("\t\t%s FAIL: Redis.Follow, expected an error. Actually not.", testutils.Failed)
+	}
+}
+
+func TestRedis_when_the_server_is_up_then_do_not_start_it(t *testing.T) {
+	r, server, daemon := newRedis(t, "")
+	defer server.Close()
+
+	if err := r.Lead(context.TODO(), *r.Spec); err != nil {
+		t.Fatalf("\t\t%s FATAL: Redis.Lead, %v", testutils.Failed, err)
+	}
+
+	if daemon.StartHits != 0 {
+		t.Fatalf("\t\t%s FAIL: Redis.Lead, expected not to start the server but actual is <%d>", testutils.Failed, daemon.StartHits)
+	}
+}
+
+func TestRedis_when_stonith_then_stop_the_server(t *testing.T) {
+	r, server, daemon := newRedis(t, "")
+	defer server.Close()
+
+	r.Stonith(context.TODO())
+
+	if daemon.StopHits != 1 {
+		t.Fatalf("\t\t%s FAIL: Redis.Stonith, expected to stop the server. Actually not.", testutils.Failed)
+	}
+}
@@ -85,6 +85,12 @@ func GetString(key string) string {
 	return viper.GetString(key)
 }
 
+// GetBool ...
+func GetBool(key string) bool {
+	setItUp()
+	return viper.GetBool(key)
+}
+
 // Reset ...
 func Reset() {
 	for _, key := range append(viper.AllKeys(), "NOMINEE_CONF_FILE") {
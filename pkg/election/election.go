@@ -8,6 +8,19 @@ type LeaderWatcher interface {
 	UpdateLeader(leader *node.Spec) error
 }
 
+// StateReporter tells what the elector knows about the leader and the role it gave to the managed node.
+type StateReporter interface {
+	GetLeader() *node.Spec
+	GetRole() node.Role
+	IsPaused() bool
+}
+
+// Pauser holds the elector back from acting on the managed node, either for the whole cluster or for this node only.
+type Pauser interface {
+	PauseCluster(paused bool)
+	PauseNode(paused bool)
+}
+
 type NodesWatcher interface {
 	UpdateNodes(nodes ...*node.Spec) error
 	RemoveNodes(nodes ...*node.Spec) error
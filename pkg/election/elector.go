@@ -2,15 +2,22 @@ package election
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github/mlyahmed.io/nominee/pkg/base"
 	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/metrics"
 	"github/mlyahmed.io/nominee/pkg/node"
 	"github/mlyahmed.io/nominee/pkg/stonither"
+	"sync"
+	"time"
 )
 
 // Elector ...
 type Elector interface {
 	LeaderWatcher
+	StateReporter
+	Pauser
 	Run(node.Node) error
 	stonither.Stonither
 	base.Cleaner
@@ -19,51 +26,279 @@ type Elector interface {
 // DefaultElector ...
 type DefaultElector struct {
 	*stonither.Basic
-	Managed node.Node
-	Leader  *node.Spec
+	Managed       node.Node
+	Leader        *node.Spec
+	role          node.Role
+	mutex         *sync.RWMutex
+	transition    *sync.Mutex
+	clusterPaused bool
+	nodePaused    bool
+	pending       *node.Spec
+	resumed       chan struct{}
+}
+
+var metricsRoles = map[node.Role]metrics.Role{
+	node.Primary: metrics.Leader,
+	node.Replica: metrics.Follower,
+	node.Unknown: metrics.None,
 }
 
 // NewElector ...
 func NewElector(managed node.Node) *DefaultElector {
 	elector := &DefaultElector{
-		Basic:   stonither.NewBasic(),
-		Managed: managed,
+		Basic:      stonither.NewBasic(),
+		Managed:    managed,
+		role:       node.Unknown,
+		mutex:      &sync.RWMutex{},
+		transition: &sync.Mutex{},
 	}
 	elector.listenToTheNodeStopChan()
 	return elector
 }
 
 // UpdateLeader ...
+// When another node takes over, the managed node is demoted then follows it, unless it cannot be demoted.
+// A leader without a name means there is no leader: the leading node is only demoted, a following one keeps following.
+// While paused, the leader is only recorded and applied once resumed.
 func (e *DefaultElector) UpdateLeader(leader *node.Spec) error {
+	e.transition.Lock()
+	defer e.transition.Unlock()
+	return e.updateLeader(leader)
+}
+
+// updateLeader applies the leader, the transition lock is held.
+func (e *DefaultElector) updateLeader(leader *node.Spec) error {
+	e.mutex.Lock()
+	if e.clusterPaused || e.nodePaused {
+		e.pending = leader
+		e.mutex.Unlock()
+		logger.G(context.Background()).Infof("paused: %s is the leader. Nothing to do.", leader.Name)
+		return nil
+	}
+	e.mutex.Unlock()
+
 	amICurrentlyTheLeader := e.amITheLeader()
 	amITheNewLeader := leader.Name == e.Managed.GetName()
+	noLeader := leader.Name == ""
+	sameLeader := e.Leader != nil && e.Leader.Name == leader.Name
+	if !sameLeader {
+		metrics.LeaderChanged()
+	}
+	e.mutex.Lock()
 	e.Leader = leader
+	e.mutex.Unlock()
 	if amITheNewLeader && amICurrentlyTheLeader {
 		logger.G(context.Background()).Infof("I stay the leader. Nothing to do.")
 	} else if amITheNewLeader && !amICurrentlyTheLeader {
 		logger.G(context.Background()).Infof("promoting The Node...")
-		if err := e.Managed.Lead(e.Ctx, *e.Leader); err != nil {
+		start := time.Now()
+		err := e.Managed.Lead(e.Ctx, *e.Leader)
+		metrics.ObserveTransition("lead", start, err)
+		if err != nil {
+			metrics.Stonith(metrics.LeadFailed)
+			e.setRole(node.Unknown)
 			e.Stonith(context.TODO())
+		} else {
+			e.setRole(node.Primary)
 		}
 	} else if !amITheNewLeader && amICurrentlyTheLeader {
-		e.Managed.Stonith(e.Ctx)
-		e.Stonith(e.Ctx)
-	} else {
-		if err := e.Managed.Follow(e.Ctx, *e.Leader); err != nil {
+		if demoter, ok := e.Managed.(node.Demoter); ok {
+			if err := e.demote(e.Ctx, demoter); err == nil && !noLeader {
+				e.follow()
+			}
+		} else {
+			metrics.Stonith(metrics.LeadershipLost)
+			e.setRole(node.Unknown)
 			e.Managed.Stonith(e.Ctx)
 			e.Stonith(e.Ctx)
 		}
+	} else if noLeader {
+		logger.G(context.Background()).Infof("no leader. Nothing to do.")
+	} else if sameLeader && e.GetRole() == node.Replica {
+		logger.G(context.Background()).Infof("I keep following %s. Nothing to do.", leader.Name)
+	} else {
+		e.follow()
+	}
+	return nil
+}
+
+func (e *DefaultElector) follow() {
+	start := time.Now()
+	err := e.Managed.Follow(e.Ctx, *e.Leader)
+	metrics.ObserveTransition("follow", start, err)
+	if err != nil {
+		metrics.Stonith(metrics.FollowFailed)
+		e.setRole(node.Unknown)
+		e.Managed.Stonith(e.Ctx)
+		e.Stonith(e.Ctx)
+	} else {
+		e.setRole(node.Replica)
+	}
+}
+
+// demote steps the managed node down, the node and the elector are stonithed when it fails.
+func (e *DefaultElector) demote(ctx context.Context, demoter node.Demoter) error {
+	logger.G(ctx).Infof("demoting The Node...")
+	start := time.Now()
+	err := demoter.Demote(ctx)
+	metrics.ObserveTransition("demote", start, err)
+	e.setRole(node.Unknown)
+	if err != nil {
+		metrics.Stonith(metrics.DemoteFailed)
+		e.Managed.Stonith(e.Ctx)
+		e.Stonith(e.Ctx)
+	}
+	return err
+}
+
+// CheckSwitchover fails unless the managed node leads and the candidate can take over from it.
+func (e *DefaultElector) CheckSwitchover(ctx context.Context, candidate node.Spec) error {
+	if e.IsPaused() {
+		return errors.New("switchover: paused")
+	}
+	if !e.amITheLeader() || e.GetRole() != node.Primary {
+		return errors.New("switchover: not the leader")
+	}
+	if candidate.Name == e.Managed.GetName() {
+		return errors.New("switchover: already the leader")
+	}
+	demoter, ok := e.Managed.(node.Demoter)
+	if !ok {
+		return fmt.Errorf("switchover: %s cannot be demoted", e.Managed.GetDaemonName())
+	}
+	return demoter.CheckCandidate(ctx, candidate)
+}
+
+// StepDown demotes the managed node and forgets the leadership, so that it follows the next leader instead of being stonithed.
+// When the demotion fails, the node and the elector are stonithed.
+func (e *DefaultElector) StepDown(ctx context.Context) error {
+	demoter, ok := e.Managed.(node.Demoter)
+	if !ok {
+		return fmt.Errorf("switchover: %s cannot be demoted", e.Managed.GetDaemonName())
+	}
+
+	if err := e.demote(ctx, demoter); err != nil {
+		return err
 	}
+
+	e.mutex.Lock()
+	e.Leader = nil
+	e.mutex.Unlock()
 	return nil
 }
 
+// PauseCluster pauses or resumes the elector for the whole cluster.
+func (e *DefaultElector) PauseCluster(paused bool) {
+	e.pause(func() { e.clusterPaused = paused })
+}
+
+// PauseNode pauses or resumes the elector for the managed node only.
+func (e *DefaultElector) PauseNode(paused bool) {
+	e.pause(func() { e.nodePaused = paused })
+}
+
+// IsPaused tells whether the cluster or the managed node is paused.
+func (e *DefaultElector) IsPaused() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.clusterPaused || e.nodePaused
+}
+
+// pause applies the change then, when it resumes the elector, applies the leader elected meanwhile. The transition lock
+// is held until then, so that a newer leader is applied after the pending one and never overridden by it.
+func (e *DefaultElector) pause(change func()) {
+	e.transition.Lock()
+	defer e.transition.Unlock()
+
+	e.mutex.Lock()
+	wasPaused := e.clusterPaused || e.nodePaused
+	change()
+	paused := e.clusterPaused || e.nodePaused
+	pending := e.pending
+	switch {
+	case paused && !wasPaused:
+		e.resumed = make(chan struct{})
+	case !paused && wasPaused:
+		close(e.resumed)
+		e.pending = nil
+	}
+	e.mutex.Unlock()
+
+	if paused == wasPaused {
+		return
+	}
+	metrics.SetPaused(paused)
+	if paused {
+		logger.G(context.Background()).Infof("paused.")
+		return
+	}
+	logger.G(context.Background()).Infof("resumed.")
+	if pending != nil {
+		_ = e.updateLeader(pending)
+	}
+}
+
+// listenToTheNodeStopChan stonithes the elector when the node stops. While paused, the node is left alone: a node
+// stopped for good is stonithed once resumed.
 func (e *DefaultElector) listenToTheNodeStopChan() {
 	go func() {
-		<-e.Managed.Done()
+		for {
+			_, open := <-e.Managed.Done()
+			resumed := e.whilePaused()
+			if resumed == nil {
+				break
+			}
+			logger.G(context.Background()).Warnf("paused: the node stopped. Nothing to do.")
+			if open {
+				continue
+			}
+			select {
+			case <-resumed:
+			case <-e.Done():
+				return
+			}
+		}
+		metrics.Stonith(metrics.NodeStopped)
+		e.setRole(node.Unknown)
 		e.Stonith(context.TODO())
 	}()
 }
 
+// whilePaused returns a channel closed once resumed, nil when not paused.
+func (e *DefaultElector) whilePaused() <-chan struct{} {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if !e.clusterPaused && !e.nodePaused {
+		return nil
+	}
+	return e.resumed
+}
+
+// GetLeader returns the last known leader, nil if there is none yet. While paused, it is the leader not applied yet.
+func (e *DefaultElector) GetLeader() *node.Spec {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	if e.pending != nil {
+		return e.pending
+	}
+	return e.Leader
+}
+
+// GetRole returns the role the managed node has successfully taken.
+func (e *DefaultElector) GetRole() node.Role {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.role
+}
+
+func (e *DefaultElector) setRole(role node.Role) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.role = role
+	metrics.SetRole(metricsRoles[role])
+}
+
 func (e *DefaultElector) amITheLeader() bool {
-	return e.Leader != nil && e.Leader.Name == e.Managed.GetName()
+	leader := e.GetLeader()
+	return leader != nil && leader.Name == e.Managed.GetName()
 }
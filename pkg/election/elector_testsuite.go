@@ -9,6 +9,7 @@ import (
 	"github/mlyahmed.io/nominee/pkg/testutils"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type electorSuite struct{}
@@ -31,9 +32,15 @@ func TestElector(t *testing.T, factory func() Elector) {
 		{"when the node stops then stonith", suite.whenTheNodeStopsThenStonith},
 		{"when elected then promote the node", suite.whenElectedThenPromoteTheNode},
 		{"when error on promote then stonith", suite.whenErrorOnPromoteThenStonith},
-		{"when demoted then stonith", suite.whenDemotedThenStonith},
+		{"when another node takes over then demote and follow it", suite.whenAnotherNodeTakesOverThenDemoteAndFollowIt},
+		{"when error on demote then stonith", suite.whenErrorOnDemoteThenStonith},
+		{"when another node takes over and no demotion then stonith", suite.whenAnotherNodeTakesOverAndNoDemotionThenStonith},
 		{"when another node is promoted then follow it", suite.whenAnotherNodeIsPromotedThenFollowIt},
 		{"when error on follow then stonith", suite.whenErrorOnFollowThenStonith},
+		{"when paused then do nothing", suite.whenPausedThenDoNothing},
+		{"when resumed then apply the last leader", suite.whenResumedThenApplyTheLastLeader},
+		{"when the node stops while paused then stonith once resumed", suite.whenTheNodeStopsWhilePausedThenStonithOnceResumed},
+		{"when a newer leader comes while resuming then follow it last", suite.whenANewerLeaderComesWhileResumingThenFollowItLast},
 	}
 
 	for _, test := range tests {
@@ -94,6 +101,10 @@ func (electorSuite) whenElectedThenPromoteTheNode(t *testing.T, factory func() E
 			if nod.LeadHits != 1 {
 				t.Fatalf("\t\t%s FATAL: Elector, expected to promote the node. Actally not.", testutils.Failed)
 			}
+
+			if elector.GetRole() != node.Primary || elector.GetLeader() != example {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as primary. Actual <%v> as %v", testutils.Failed, example, elector.GetLeader(), elector.GetRole())
+			}
 		})
 	}
 }
@@ -120,17 +131,63 @@ func (electorSuite) whenErrorOnPromoteThenStonith(t *testing.T, factory func() E
 			//FIXME: must stonith the node also
 
 			testutils.AsyncAssertion.ItMustBeStopped(t, elector.Done())
+
+			if elector.GetRole() != node.Unknown {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as role. Actual <%v>", testutils.Failed, node.Unknown, elector.GetRole())
+			}
 		})
 	}
 }
 
-func (electorSuite) whenDemotedThenStonith(t *testing.T, factory func() Elector) {
+func (electorSuite) whenAnotherNodeTakesOverThenDemoteAndFollowIt(t *testing.T, factory func() Elector) {
 	for _, example := range nodeSpecExamples {
 		t.Run("", func(t *testing.T) {
 			elector := factory()
 			defer elector.Cleanup()
 			nod := mock.NewNode(t, example)
+			leader := node.Spec{Name: string(uuid.NodeID())}
 			nod.LeadFn = func(_ context.Context, spec node.Spec) error { return nil }
+			nod.DemoteFn = func(context.Context) error { return nil }
+			nod.FollowFn = func(_ context.Context, l node.Spec) error {
+				if leader != l {
+					t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as leader. Actual <%v>", testutils.Failed, leader, l)
+				}
+				return nil
+			}
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: Elector, failed to run %v", testutils.Failed, err)
+			}
+
+			if err := elector.UpdateLeader(example); err != nil { // promote it
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			if err := elector.UpdateLeader(&leader); err != nil { // another node takes over
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			if nod.DemoteHits != 1 || nod.FollowHits != 1 || nod.StonithHits != 0 {
+				t.Fatalf("\t\t%s FATAL: Elector, expected to demote then follow. Actual <%d> demotions, <%d> follows and <%d> stonith.", testutils.Failed, nod.DemoteHits, nod.FollowHits, nod.StonithHits)
+			}
+
+			testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+
+			if elector.GetRole() != node.Replica {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as role. Actual <%v>", testutils.Failed, node.Replica, elector.GetRole())
+			}
+		})
+	}
+}
+
+func (electorSuite) whenErrorOnDemoteThenStonith(t *testing.T, factory func() Elector) {
+	for _, example := range nodeSpecExamples {
+		t.Run("", func(t *testing.T) {
+			elector := factory()
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example)
+			nod.LeadFn = func(_ context.Context, spec node.Spec) error { return nil }
+			nod.DemoteFn = func(context.Context) error { return errors.New("") }
 			nod.StonithFn = func(context.Context) {}
 
 			if err := elector.Run(nod); err != nil {
@@ -141,7 +198,46 @@ func (electorSuite) whenDemotedThenStonith(t *testing.T, factory func() Elector)
 				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
 			}
 
-			if err := elector.UpdateLeader(&node.Spec{Name: string(uuid.NodeID())}); err != nil { // Demote it
+			if err := elector.UpdateLeader(&node.Spec{Name: string(uuid.NodeID())}); err != nil { // another node takes over
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			if nod.StonithHits != 1 || nod.FollowHits != 0 {
+				t.Fatalf("\t\t%s FATAL: Elector, expected to stonith the node without following.", testutils.Failed)
+			}
+
+			testutils.AsyncAssertion.ItMustBeStopped(t, elector.Done())
+
+			if elector.GetRole() != node.Unknown {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as role. Actual <%v>", testutils.Failed, node.Unknown, elector.GetRole())
+			}
+		})
+	}
+}
+
+// nonDemotable hides the demotion of the mocked node.
+type nonDemotable struct {
+	node.Node
+}
+
+func (electorSuite) whenAnotherNodeTakesOverAndNoDemotionThenStonith(t *testing.T, factory func() Elector) {
+	for _, example := range nodeSpecExamples {
+		t.Run("", func(t *testing.T) {
+			elector := factory()
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example)
+			nod.LeadFn = func(_ context.Context, spec node.Spec) error { return nil }
+			nod.StonithFn = func(context.Context) {}
+
+			if err := elector.Run(nonDemotable{nod}); err != nil {
+				t.Fatalf("\t\t%s FATAL: Elector, failed to run %v", testutils.Failed, err)
+			}
+
+			if err := elector.UpdateLeader(example); err != nil { // promote it
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			if err := elector.UpdateLeader(&node.Spec{Name: string(uuid.NodeID())}); err != nil { // another node takes over
 				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
 			}
 
@@ -150,6 +246,10 @@ func (electorSuite) whenDemotedThenStonith(t *testing.T, factory func() Elector)
 			}
 
 			testutils.AsyncAssertion.ItMustBeStopped(t, elector.Done())
+
+			if elector.GetRole() != node.Unknown {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as role. Actual <%v>", testutils.Failed, node.Unknown, elector.GetRole())
+			}
 		})
 	}
 }
@@ -179,6 +279,10 @@ func (electorSuite) whenAnotherNodeIsPromotedThenFollowIt(t *testing.T, factory
 			if nod.FollowHits != 1 {
 				t.Fatalf("\t\t%s FATAL: Elector, expected to follow the leader. Actally not.", testutils.Failed)
 			}
+
+			if elector.GetRole() != node.Replica {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as role. Actual <%v>", testutils.Failed, node.Replica, elector.GetRole())
+			}
 		})
 	}
 }
@@ -209,6 +313,137 @@ func (electorSuite) whenErrorOnFollowThenStonith(t *testing.T, factory func() El
 			}
 
 			testutils.AsyncAssertion.ItMustBeStopped(t, elector.Done())
+
+			if elector.GetRole() != node.Unknown {
+				t.Fatalf("\t\t%s FATAL: Elector, expected <%v> as role. Actual <%v>", testutils.Failed, node.Unknown, elector.GetRole())
+			}
+		})
+	}
+}
+
+func (electorSuite) whenPausedThenDoNothing(t *testing.T, factory func() Elector) {
+	for _, example := range nodeSpecExamples {
+		t.Run("", func(t *testing.T) {
+			elector := factory()
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example)
+			nod.LeadFn = func(_ context.Context, spec node.Spec) error { return nil }
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: Elector, failed to run %v", testutils.Failed, err)
+			}
+
+			if err := elector.UpdateLeader(example); err != nil { // promote it
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			elector.PauseCluster(true)
+			leader := node.Spec{Name: string(uuid.NodeID())}
+			if err := elector.UpdateLeader(&leader); err != nil { // Demote it
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			if nod.StonithHits != 0 || nod.FollowHits != 0 {
+				t.Fatalf("\t\t%s FATAL: Elector, expected to do nothing. Actual <%d> stonith and <%d> follow", testutils.Failed, nod.StonithHits, nod.FollowHits)
+			}
+
+			testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+
+			if !elector.IsPaused() || elector.GetRole() != node.Primary || *elector.GetLeader() != leader {
+				t.Fatalf("\t\t%s FATAL: Elector, expected to be paused as <%v> and to know <%v>. Actual <%v>, <%v> and <%v>", testutils.Failed, node.Primary, leader, elector.IsPaused(), elector.GetRole(), elector.GetLeader())
+			}
 		})
 	}
 }
+
+func (electorSuite) whenResumedThenApplyTheLastLeader(t *testing.T, factory func() Elector) {
+	for _, example := range nodeSpecExamples {
+		t.Run("", func(t *testing.T) {
+			elector := factory()
+			defer elector.Cleanup()
+			nod := mock.NewNode(t, example)
+			leader := node.Spec{Name: string(uuid.NodeID())}
+			nod.FollowFn = func(ctx context.Context, l node.Spec) error { return nil }
+
+			if err := elector.Run(nod); err != nil {
+				t.Fatalf("\t\t%s FATAL: Elector, failed to run %v", testutils.Failed, err)
+			}
+
+			elector.PauseNode(true)
+			if err := elector.UpdateLeader(&leader); err != nil { // promote another node
+				t.Fatalf("\t\t%s FATAL: Elector, failed to update leader %v", testutils.Failed, err)
+			}
+
+			if nod.FollowHits != 0 {
+				t.Fatalf("\t\t%s FATAL: Elector, expected not to follow while paused. Actually it did.", testutils.Failed)
+			}
+
+			elector.PauseNode(false)
+
+			if nod.FollowHits != 1 || nod.Leader != leader {
+				t.Fatalf("\t\t%s FATAL: Elector, expected to follow <%v> once resumed. Actual <%d> follows of <%v>", testutils.Failed, leader, nod.FollowHits, nod.Leader)
+			}
+
+			if elector.IsPaused() || elector.GetRole() != node.Replica {
+				t.Fatalf("\t\t%s FATAL: Elector, expected to be resumed as <%v>. Actual <%v> and <%v>", testutils.Failed, node.Replica, elector.IsPaused(), elector.GetRole())
+			}
+		})
+	}
+}
+
+func (electorSuite) whenANewerLeaderComesWhileResumingThenFollowItLast(t *testing.T, factory func() Elector) {
+	elector := factory()
+	defer elector.Cleanup()
+	nod := mock.NewNode(t, nodeSpecExamples[0])
+	pending, newer := node.Spec{Name: string(uuid.NodeID())}, node.Spec{Name: string(uuid.NodeID()) + "-newer"}
+	following, release := make(chan node.Spec, 2), make(chan struct{})
+	nod.FollowFn = func(_ context.Context, leader node.Spec) error {
+		following <- leader
+		<-release
+		return nil
+	}
+	if err := elector.Run(nod); err != nil {
+		t.Fatalf("\t\t%s FATAL: Elector, failed to run %v", testutils.Failed, err)
+	}
+	elector.PauseCluster(true)
+	_ = elector.UpdateLeader(&pending)
+
+	resumed := make(chan struct{})
+	go func() {
+		elector.PauseCluster(false)
+		close(resumed)
+	}()
+	if leader := <-following; leader != pending {
+		t.Fatalf("\t\t%s FATAL: Elector, expected to follow <%v> first. Actual <%v>", testutils.Failed, pending, leader)
+	}
+	updated := make(chan struct{})
+	go func() {
+		_ = elector.UpdateLeader(&newer)
+		close(updated)
+	}()
+	close(release)
+	<-resumed
+	<-updated
+
+	if leader := <-following; leader != newer || *elector.GetLeader() != newer {
+		t.Fatalf("\t\t%s FATAL: Elector, expected to follow <%v> last. Actual <%v> and <%v>", testutils.Failed, newer, leader, elector.GetLeader())
+	}
+}
+
+func (electorSuite) whenTheNodeStopsWhilePausedThenStonithOnceResumed(t *testing.T, factory func() Elector) {
+	elector := factory()
+	defer elector.Cleanup()
+	nod := mock.NewNode(t, &node.Spec{})
+
+	if err := elector.Run(nod); err != nil {
+		t.Fatalf("\t\t%s FATAL: Elector, failed to run %v", testutils.Failed, err)
+	}
+
+	elector.PauseCluster(true)
+	close(nod.StopChan)
+	time.Sleep(100 * time.Millisecond)
+	testutils.AsyncAssertion.ItMustKeepRunning(t, elector.Done())
+
+	elector.PauseCluster(false)
+	testutils.AsyncAssertion.ItMustBeStopped(t, elector.Done())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github/mlyahmed.io/nominee/pkg/base"
 	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/metrics"
 	"github/mlyahmed.io/nominee/pkg/node"
 	"github/mlyahmed.io/nominee/pkg/proxy"
 	"github/mlyahmed.io/nominee/pkg/stonither"
@@ -59,13 +60,18 @@ func (observer *BasicObserver) publish() {
 	if observer.updated {
 		logger.G(context.Background()).Info("Publish to the proxy...")
 
-		followers := make([]*node.Spec, len(observer.Followers))
-		i := 0
+		followers := make([]*node.Spec, 0, len(observer.Followers))
 		for _, follower := range observer.Followers {
-			followers[i] = follower
-			i++
+			if !follower.Tags.NoLoadBalance {
+				followers = append(followers, follower)
+			}
+		}
+		start := time.Now()
+		err := observer.Managed.Publish(observer.Leader, followers...)
+		metrics.ObservePublish(start, err)
+		if err != nil {
+			logger.G(context.Background()).Errorf("Failed to publish: %v", err)
 		}
-		_ = observer.Managed.Publish(observer.Leader, followers...)
 		observer.updated = false
 		return
 	}
@@ -84,9 +90,11 @@ func (observer *BasicObserver) UpdateNodes(nodes ...*node.Spec) error {
 	observer.mutex.Lock()
 	defer observer.mutex.Unlock()
 	for _, spec := range nodes {
+		if known, ok := observer.Followers[spec.ElectionKey]; !ok || !sameButMetadata(*known, *spec) {
+			observer.updated = true
+		}
 		observer.Followers[spec.ElectionKey] = spec
 	}
-	observer.updated = true
 	return nil
 }
 
@@ -103,9 +111,16 @@ func (observer *BasicObserver) RemoveNodes(nodes ...*node.Spec) error {
 	return nil
 }
 
+// sameButMetadata tells whether the specs differ by their metadata only, which the proxy does not care about.
+func sameButMetadata(a, b node.Spec) bool {
+	a.Metadata, b.Metadata = node.Metadata{}, node.Metadata{}
+	return a == b
+}
+
 func (observer *BasicObserver) listenToTheProxyStopChan() {
 	go func() {
 		<-observer.Managed.Done()
+		metrics.Stonith(metrics.ProxyStopped)
 		observer.Stonith(context.TODO())
 	}()
 }
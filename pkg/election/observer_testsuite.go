@@ -24,6 +24,7 @@ func TestObserver(t *testing.T, factory func() Observer) {
 		{"when new node then add it as follower", suite.whenNewNodeThenAddItAsFollower},
 		{"when a follower is removed then removes it", suite.whenAFollowerIsRemovedThenRemovesIt},
 		{"when the leader is removed then removes it", suite.whenTheLeaderIsRemovedThenRemovesIt},
+		{"when a node is not load balanced then do not publish it", suite.whenANodeIsNotLoadBalancedThenDoNotPublishIt},
 	}
 
 	for _, test := range tests {
@@ -190,6 +191,27 @@ func (observerSuite) whenTheLeaderIsRemovedThenRemovesIt(t *testing.T, factory f
 	testutils.AsyncAssertion.ItMustBeTrue(t, equal)
 }
 
+func (observerSuite) whenANodeIsNotLoadBalancedThenDoNotPublishIt(t *testing.T, factory func() Observer) {
+	observer := factory()
+	defer observer.Cleanup()
+	proxy := mock.NewProxy()
+	if err := observer.Observe(proxy); err != nil {
+		t.Fatalf("\t\t%s FATAL: Observer, failed to run %v", testutils.Failed, err)
+	}
+
+	balanced := &node.Spec{ElectionKey: "balanced-key-001", Name: "balanced-node-001", Address: "10.0.0.21", Port: 5432}
+	excluded := &node.Spec{ElectionKey: "excluded-key-001", Name: "excluded-node-001", Address: "10.0.0.22", Port: 5432, Tags: node.Tags{NoLoadBalance: true}}
+	if err := observer.UpdateNodes(balanced, excluded); err != nil {
+		t.Fatalf("\t\t%s FATAL: Observer, failed to update nodes %v", testutils.Failed, err)
+	}
+
+	expected := []*node.Spec{balanced}
+	equal := func() bool {
+		return reflect.DeepEqual(expected, proxy.Followers)
+	}
+	testutils.AsyncAssertion.ItMustBeTrue(t, equal)
+}
+
 func sortNodes(nodes []*node.Spec) {
 	sort.Slice(nodes, func(i, j int) bool {
 		return nodes[i].Name < nodes[j].Name
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"net"
+	"net/http"
+)
+
+// Status is the body of the health and role endpoints.
+type Status struct {
+	Name   string
+	Role   node.Role
+	Leader *node.Spec
+	Paused bool
+}
+
+type server struct {
+	elector election.Elector
+	managed node.Node
+}
+
+// Serve exposes the health and role endpoints on NOMINEE_HTTP_ADDR until the context is done. It does nothing when the address is not set.
+//
+// /health answers 200 as long as the elector runs, /primary, /replica and /readiness answer 200 only when the role given
+// by the election is the one the node actually runs with, /leader returns the current leader. They answer 503 otherwise.
+func Serve(ctx context.Context, elector election.Elector, managed node.Node) error {
+	address := config.GetString("NOMINEE_HTTP_ADDR")
+	if address == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	s := &server{elector: elector, managed: managed}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", s.health)
+	mux.HandleFunc("/primary", s.expect(node.Primary))
+	mux.HandleFunc("/replica", s.expect(node.Replica))
+	mux.HandleFunc("/readiness", s.expect(node.Primary, node.Replica))
+	mux.HandleFunc("/leader", s.leader)
+	httpServer := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Close()
+	}()
+
+	go func() {
+		logger.G(ctx).Infof("health served on %s", listener.Addr())
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.G(ctx).Errorf("health server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *server) health(w http.ResponseWriter, r *http.Request) {
+	status := s.status(r.Context())
+	code := http.StatusOK
+	if s.isStopped() {
+		code = http.StatusServiceUnavailable
+	}
+	reply(w, code, status)
+}
+
+func (s *server) expect(roles ...node.Role) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := s.status(r.Context())
+		for _, role := range roles {
+			if status.Role == role {
+				reply(w, http.StatusOK, status)
+				return
+			}
+		}
+		reply(w, http.StatusServiceUnavailable, status)
+	}
+}
+
+func (s *server) leader(w http.ResponseWriter, _ *http.Request) {
+	leader := s.elector.GetLeader()
+	if leader == nil || leader.Name == "" {
+		reply(w, http.StatusServiceUnavailable, leader)
+		return
+	}
+	reply(w, http.StatusOK, leader)
+}
+
+// status trusts the role given by the election only when the node confirms it, a node still promoting or demoting is unknown.
+func (s *server) status(ctx context.Context) Status {
+	status := Status{Name: s.managed.GetName(), Role: s.elector.GetRole(), Leader: s.elector.GetLeader(), Paused: s.elector.IsPaused()}
+	if s.isStopped() {
+		status.Role = node.Unknown
+		return status
+	}
+
+	reporter, ok := s.managed.(node.RoleReporter)
+	if !ok || status.Role == node.Unknown {
+		return status
+	}
+
+	actual, err := reporter.ActualRole(ctx)
+	if err != nil {
+		logger.G(ctx).Warnf("health: failed to get the actual role: %v", err)
+		status.Role = node.Unknown
+	} else if actual != status.Role {
+		logger.G(ctx).Warnf("health: elected as %s but actually %s", status.Role, actual)
+		status.Role = node.Unknown
+	}
+	return status
+}
+
+func (s *server) isStopped() bool {
+	select {
+	case <-s.elector.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func reply(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,219 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/health"
+	"github/mlyahmed.io/nominee/pkg/mock"
+	"github/mlyahmed.io/nominee/pkg/node"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+var (
+	myself = node.Spec{ElectionKey: "key-001", Name: "postgres-01", Address: "10.0.0.1", Port: 5432}
+	other  = node.Spec{ElectionKey: "key-001", Name: "postgres-02", Address: "10.0.0.2", Port: 5432}
+)
+
+// reportingNode is a node telling its actual role.
+type reportingNode struct {
+	*mock.Node
+	ActualRoleFn func(context.Context) (node.Role, error)
+}
+
+func (n *reportingNode) ActualRole(ctx context.Context) (node.Role, error) {
+	return n.ActualRoleFn(ctx)
+}
+
+func serve(t *testing.T, elector *mock.Elector, managed node.Node) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: Listen, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	_ = os.Setenv("NOMINEE_HTTP_ADDR", address)
+	defer func() { _ = os.Unsetenv("NOMINEE_HTTP_ADDR") }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := health.Serve(ctx, elector, managed); err != nil {
+		t.Fatalf("\t\t%s FAIL: Serve, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	return "http://" + address
+}
+
+func get(t *testing.T, url string, body interface{}) int {
+	var response *http.Response
+	var err error
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if response, err = http.Get(url); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: GET %s, expected no error but actual is <%v>", testutils.Failed, url, err)
+	}
+	defer response.Body.Close()
+	if body != nil {
+		_ = json.NewDecoder(response.Body).Decode(body)
+	}
+	return response.StatusCode
+}
+
+func assertCodes(t *testing.T, base string, expected map[string]int) {
+	for path, code := range expected {
+		if actual := get(t, base+path, nil); actual != code {
+			t.Fatalf("\t\t%s FAIL: GET %s, expected <%d> but actual is <%d>", testutils.Failed, path, code, actual)
+		}
+	}
+}
+
+func TestHealth_when_no_address_then_nothing_is_served(t *testing.T) {
+	t.Logf("Given no HTTP address")
+	{
+		_ = os.Unsetenv("NOMINEE_HTTP_ADDR")
+		t.Logf("\tWhen serve the endpoints")
+		{
+			if err := health.Serve(context.Background(), mock.NewElector(t), mock.NewNode(t, &myself)); err != nil {
+				t.Fatalf("\t\t%s FAIL: Serve, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then it must do nothing.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHealth_when_elected_primary_then_answer_as_primary(t *testing.T) {
+	t.Logf("Given a node elected as the primary")
+	{
+		elector := mock.NewElector(t)
+		elector.Leader, elector.Role = &myself, node.Primary
+		base := serve(t, elector, mock.NewNode(t, &myself))
+
+		t.Logf("\tWhen the endpoints are called")
+		{
+			assertCodes(t, base, map[string]int{"/health": 200, "/primary": 200, "/replica": 503, "/readiness": 200})
+			t.Logf("\t\t%s Then only the primary and the readiness endpoints must succeed.", testutils.Succeed)
+
+			status := health.Status{}
+			_ = get(t, base+"/primary", &status)
+			expected := health.Status{Name: myself.Name, Role: node.Primary, Leader: &myself}
+			if !reflect.DeepEqual(status, expected) {
+				t.Fatalf("\t\t%s FAIL: status, expected <%v> but actual is <%v>", testutils.Failed, expected, status)
+			}
+			t.Logf("\t\t%s Then the status must be returned.", testutils.Succeed)
+
+			leader := node.Spec{}
+			if code := get(t, base+"/leader", &leader); code != 200 || leader != myself {
+				t.Fatalf("\t\t%s FAIL: GET /leader, expected <200 %v> but actual is <%d %v>", testutils.Failed, myself, code, leader)
+			}
+			t.Logf("\t\t%s Then the leader must be returned.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHealth_when_following_then_answer_as_replica(t *testing.T) {
+	t.Logf("Given a node following another one")
+	{
+		elector := mock.NewElector(t)
+		elector.Leader, elector.Role = &other, node.Replica
+		managed := &reportingNode{Node: mock.NewNode(t, &myself), ActualRoleFn: func(context.Context) (node.Role, error) {
+			return node.Replica, nil
+		}}
+		base := serve(t, elector, managed)
+
+		t.Logf("\tWhen the endpoints are called")
+		{
+			assertCodes(t, base, map[string]int{"/health": 200, "/primary": 503, "/replica": 200, "/readiness": 200})
+			t.Logf("\t\t%s Then only the replica and the readiness endpoints must succeed.", testutils.Succeed)
+
+			leader := node.Spec{}
+			if code := get(t, base+"/leader", &leader); code != 200 || leader != other {
+				t.Fatalf("\t\t%s FAIL: GET /leader, expected <200 %v> but actual is <%d %v>", testutils.Failed, other, code, leader)
+			}
+			t.Logf("\t\t%s Then the other node must be returned as the leader.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHealth_when_the_actual_role_differs_then_not_ready(t *testing.T) {
+	t.Logf("Given a node elected as the primary")
+	{
+		for description, actualRoleFn := range map[string]func(context.Context) (node.Role, error){
+			"still in recovery":  func(context.Context) (node.Role, error) { return node.Replica, nil },
+			"failing to tell it": func(context.Context) (node.Role, error) { return node.Unknown, errors.New("") },
+		} {
+			t.Run(description, func(t *testing.T) {
+				elector := mock.NewElector(t)
+				elector.Leader, elector.Role = &myself, node.Primary
+				base := serve(t, elector, &reportingNode{Node: mock.NewNode(t, &myself), ActualRoleFn: actualRoleFn})
+
+				t.Logf("\tWhen the node is %s", description)
+				{
+					assertCodes(t, base, map[string]int{"/health": 200, "/primary": 503, "/replica": 503, "/readiness": 503})
+					t.Logf("\t\t%s Then it must be neither primary nor replica nor ready.", testutils.Succeed)
+				}
+			})
+		}
+	}
+}
+
+func TestHealth_when_no_leader_then_unavailable(t *testing.T) {
+	t.Logf("Given a node before the first election")
+	{
+		base := serve(t, mock.NewElector(t), mock.NewNode(t, &myself))
+
+		t.Logf("\tWhen the endpoints are called")
+		{
+			assertCodes(t, base, map[string]int{"/health": 200, "/primary": 503, "/replica": 503, "/readiness": 503, "/leader": 503})
+			t.Logf("\t\t%s Then only the health endpoint must succeed.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHealth_when_stonithed_then_unhealthy(t *testing.T) {
+	t.Logf("Given a node elected as the primary")
+	{
+		elector := mock.NewElector(t)
+		elector.Leader, elector.Role = &myself, node.Primary
+		base := serve(t, elector, mock.NewNode(t, &myself))
+
+		t.Logf("\tWhen the elector is stonithed")
+		{
+			elector.Stonith(context.Background())
+			assertCodes(t, base, map[string]int{"/health": 503, "/primary": 503, "/readiness": 503})
+			t.Logf("\t\t%s Then it must be neither healthy nor primary nor ready.", testutils.Succeed)
+		}
+	}
+}
+
+func TestHealth_when_paused_then_tell_it(t *testing.T) {
+	t.Logf("Given a paused node elected as the primary")
+	{
+		elector := mock.NewElector(t)
+		elector.Leader, elector.Role, elector.Paused = &myself, node.Primary, true
+		base := serve(t, elector, mock.NewNode(t, &myself))
+
+		t.Logf("\tWhen the health endpoint is called")
+		{
+			status := health.Status{}
+			if code := get(t, base+"/health", &status); code != 200 || !status.Paused {
+				t.Fatalf("\t\t%s FAIL: GET /health, expected <200> and paused but actual is <%d %v>", testutils.Failed, code, status)
+			}
+			t.Logf("\t\t%s Then it must be healthy and paused.", testutils.Succeed)
+		}
+	}
+}
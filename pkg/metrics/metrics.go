@@ -0,0 +1,201 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github/mlyahmed.io/nominee/pkg/config"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Role ...
+type Role string
+
+const (
+	// Leader ...
+	Leader Role = "leader"
+	// Follower ...
+	Follower Role = "follower"
+	// None is the role of a node that neither leads nor follows, before the first election or after a stonith.
+	None Role = "none"
+)
+
+// The reasons of a stonith.
+const (
+	LeadFailed     = "lead_failed"
+	FollowFailed   = "follow_failed"
+	DemoteFailed   = "demote_failed"
+	LeadershipLost = "leadership_lost"
+	NodeStopped    = "node_stopped"
+	ProxyStopped   = "proxy_stopped"
+	Signal         = "signal"
+)
+
+// The steps to bring a former primary back as a standby.
+const (
+	Rewind    = "rewind"
+	MoveAside = "move_aside"
+	Reclone   = "reclone"
+)
+
+const namespace = "nominee"
+
+var (
+	roles = []Role{Leader, Follower, None}
+
+	leaderTransitions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "leader_transitions_total",
+		Help:      "Number of times the elected leader has changed.",
+	})
+
+	role = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_role",
+		Help:      "Current role of the managed node, 1 for the current role and 0 for the others.",
+	}, []string{"role"})
+
+	transitionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "node_transition_duration_seconds",
+		Help:      "Time spent by the managed node to lead or to follow.",
+		Buckets:   []float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"action", "result"})
+
+	stoniths = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stonith_total",
+		Help:      "Number of stoniths by reason.",
+	}, []string{"reason"})
+
+	recoverySteps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recovery_steps_total",
+		Help:      "Number of steps run to bring a former primary back as a standby, by step and result.",
+	}, []string{"step", "result"})
+
+	paused = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "paused",
+		Help:      "Whether the elector is paused, for the whole cluster or for this node, 1 if paused and 0 otherwise.",
+	})
+
+	sessionReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "session_reconnects_total",
+		Help:      "Number of times the session with the election backend was lost and reconnected.",
+	}, []string{"backend"})
+
+	publishDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "publish_duration_seconds",
+		Help:      "Time spent to publish the leader and the followers to the proxy.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	publishFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_failures_total",
+		Help:      "Number of failed publications to the proxy.",
+	})
+)
+
+func init() {
+	SetRole(None)
+}
+
+// LeaderChanged counts a leader transition.
+func LeaderChanged() {
+	leaderTransitions.Inc()
+}
+
+// SetRole ...
+func SetRole(current Role) {
+	for _, r := range roles {
+		value := 0.0
+		if r == current {
+			value = 1
+		}
+		role.WithLabelValues(string(r)).Set(value)
+	}
+}
+
+// SetPaused ...
+func SetPaused(isPaused bool) {
+	value := 0.0
+	if isPaused {
+		value = 1
+	}
+	paused.Set(value)
+}
+
+// ObserveTransition records the time spent by the node to lead or to follow since start.
+func ObserveTransition(action string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	transitionDuration.WithLabelValues(action, result).Observe(time.Since(start).Seconds())
+}
+
+// Stonith counts a stonith for the reason.
+func Stonith(reason string) {
+	stoniths.WithLabelValues(reason).Inc()
+}
+
+// RecoveryStep counts a step run to bring a former primary back as a standby.
+func RecoveryStep(step string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	recoverySteps.WithLabelValues(step, result).Inc()
+}
+
+// SessionReconnected counts a lost session with the backend.
+func SessionReconnected(backend string) {
+	sessionReconnects.WithLabelValues(backend).Inc()
+}
+
+// ObservePublish records a publication to the proxy started at start.
+func ObservePublish(start time.Time, err error) {
+	publishDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		publishFailures.Inc()
+	}
+}
+
+// Serve exposes the metrics on NOMINEE_OBS_METRICS_ADDR until the context is done. It does nothing when the address is not set.
+func Serve(ctx context.Context) error {
+	config.SetDefault("NOMINEE_OBS_METRICS_PATH", "/metrics")
+	address := config.GetString("NOMINEE_OBS_METRICS_ADDR")
+	if address == "" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(config.GetString("NOMINEE_OBS_METRICS_PATH"), promhttp.Handler())
+	server := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	go func() {
+		logger.G(ctx).Infof("metrics served on %s", listener.Addr())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.G(ctx).Errorf("metrics server stopped: %v", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,128 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/metrics"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func freeAddress(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: Listen, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func scrape(t *testing.T, url string) string {
+	var response *http.Response
+	var err error
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if response, err = http.Get(url); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		t.Fatalf("\t\t%s FAIL: scrape, expected no error but actual is <%v>", testutils.Failed, err)
+	}
+	defer response.Body.Close()
+	body, _ := ioutil.ReadAll(response.Body)
+	return string(body)
+}
+
+func TestMetrics_when_no_address_then_nothing_is_served(t *testing.T) {
+	t.Logf("Given no metrics address")
+	{
+		_ = os.Unsetenv("NOMINEE_OBS_METRICS_ADDR")
+		t.Logf("\tWhen serve the metrics")
+		{
+			if err := metrics.Serve(context.Background()); err != nil {
+				t.Fatalf("\t\t%s FAIL: Serve, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then it must do nothing.", testutils.Succeed)
+		}
+	}
+}
+
+func TestMetrics_when_served_then_expose_the_nominee_metrics(t *testing.T) {
+	t.Logf("Given a metrics address and path")
+	{
+		address := freeAddress(t)
+		_ = os.Setenv("NOMINEE_OBS_METRICS_ADDR", address)
+		_ = os.Setenv("NOMINEE_OBS_METRICS_PATH", "/custom")
+		defer func() {
+			_ = os.Unsetenv("NOMINEE_OBS_METRICS_ADDR")
+			_ = os.Unsetenv("NOMINEE_OBS_METRICS_PATH")
+		}()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := metrics.Serve(ctx); err != nil {
+			t.Fatalf("\t\t%s FAIL: Serve, expected no error but actual is <%v>", testutils.Failed, err)
+		}
+
+		t.Logf("\tWhen the node leads after a transition and a publication fails")
+		{
+			metrics.LeaderChanged()
+			metrics.ObserveTransition("lead", time.Now(), nil)
+			metrics.SetRole(metrics.Leader)
+			metrics.SetPaused(true)
+			metrics.Stonith(metrics.LeadershipLost)
+			metrics.SessionReconnected("etcd")
+			metrics.RecoveryStep(metrics.Rewind, errors.New("diverged"))
+			metrics.RecoveryStep(metrics.Reclone, nil)
+			metrics.ObservePublish(time.Now(), errors.New("publish failed"))
+
+			body := scrape(t, "http://"+address+"/custom")
+			for _, expected := range []string{
+				"nominee_leader_transitions_total 1",
+				`nominee_node_role{role="leader"} 1`,
+				`nominee_node_role{role="follower"} 0`,
+				`nominee_node_role{role="none"} 0`,
+				"nominee_paused 1",
+				`nominee_node_transition_duration_seconds_count{action="lead",result="success"} 1`,
+				`nominee_stonith_total{reason="leadership_lost"} 1`,
+				`nominee_session_reconnects_total{backend="etcd"} 1`,
+				`nominee_recovery_steps_total{result="failure",step="rewind"} 1`,
+				`nominee_recovery_steps_total{result="success",step="reclone"} 1`,
+				"nominee_publish_duration_seconds_count 1",
+				"nominee_publish_failures_total 1",
+			} {
+				if !strings.Contains(body, expected) {
+					t.Fatalf("\t\t%s FAIL: metrics, expected to contain <%s> but actual is <%s>", testutils.Failed, expected, body)
+				}
+			}
+			t.Logf("\t\t%s Then the metrics must be exposed on the path.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the context is done")
+		{
+			cancel()
+			deadline := time.Now().Add(2 * time.Second)
+			for {
+				if _, err := http.Get("http://" + address + "/custom"); err != nil {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("\t\t%s FAIL: Serve, expected to be stopped but actual is still serving", testutils.Failed)
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			t.Logf("\t\t%s Then the metrics must not be served anymore.", testutils.Succeed)
+		}
+	}
+}
@@ -19,7 +19,10 @@ type Elector struct {
 	*Mock
 	*stonither.Basic
 	*Cleaner
-	RunFn func(node.Node) error
+	RunFn  func(node.Node) error
+	Leader *node.Spec
+	Role   node.Role
+	Paused bool
 }
 
 type Observer struct {
@@ -39,6 +42,7 @@ func NewElector(t *testing.T) *Elector {
 		RunFn: func(node.Node) error {
 			return nil
 		},
+		Role: node.Unknown,
 	}
 }
 
@@ -67,6 +71,26 @@ func (e *Elector) UpdateLeader(_ *node.Spec) error {
 	return nil
 }
 
+func (e *Elector) GetLeader() *node.Spec {
+	return e.Leader
+}
+
+func (e *Elector) GetRole() node.Role {
+	return e.Role
+}
+
+func (e *Elector) IsPaused() bool {
+	return e.Paused
+}
+
+func (e *Elector) PauseCluster(paused bool) {
+	e.Paused = paused
+}
+
+func (e *Elector) PauseNode(paused bool) {
+	e.Paused = paused
+}
+
 func (o *Observer) Observe(p proxy.Proxy) error {
 	return o.ObserveFn(p)
 }
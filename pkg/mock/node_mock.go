@@ -13,6 +13,7 @@ type NodeRecord struct {
 	LeadHits    int
 	FollowHits  int
 	StonithHits int
+	DemoteHits  int
 	Leader      node.Spec
 }
 
@@ -27,6 +28,8 @@ type Node struct {
 	FollowFn     func(context.Context, node.Spec) error
 	StonithFn    func(context.Context)
 	StopChanFn   func() base.DoneChan
+	CandidateFn  func(context.Context, node.Spec) error
+	DemoteFn     func(context.Context) error
 }
 
 // NewMockServiceWithNominee ...
@@ -53,6 +56,14 @@ func NewNode(t *testing.T, spec *node.Spec) *Node {
 		StopChanFn: func() base.DoneChan {
 			return stopChan
 		},
+		CandidateFn: func(_ context.Context, _ node.Spec) error {
+			t.Fatalf("\t\t\t%s FATAL [Fail Fast]: CandidateFn function not specified.", testutils.Failed)
+			return nil
+		},
+		DemoteFn: func(_ context.Context) error {
+			t.Fatalf("\t\t\t%s FATAL [Fail Fast]: DemoteFn function not specified.", testutils.Failed)
+			return nil
+		},
 	}
 }
 
@@ -81,6 +92,17 @@ func (mock *Node) Stonith(ctx context.Context) {
 	mock.StonithFn(ctx)
 }
 
+// CheckCandidate ...
+func (mock *Node) CheckCandidate(ctx context.Context, candidate node.Spec) error {
+	return mock.CandidateFn(ctx, candidate)
+}
+
+// Demote ...
+func (mock *Node) Demote(ctx context.Context) error {
+	mock.DemoteHits++
+	return mock.DemoteFn(ctx)
+}
+
 // StopChan ...
 func (mock *Node) Done() base.DoneChan {
 	return mock.StopChanFn()
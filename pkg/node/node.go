@@ -3,6 +3,8 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"github/mlyahmed.io/nominee/pkg/config"
 	"github/mlyahmed.io/nominee/pkg/stonither"
 )
 
@@ -23,12 +25,143 @@ type Node interface {
 	stonither.Stonither
 }
 
+// Role ...
+type Role string
+
+const (
+	// Primary ...
+	Primary Role = "primary"
+	// Replica ...
+	Replica Role = "replica"
+	// Unknown is the role of a node that neither leads nor follows yet, or that failed to.
+	Unknown Role = "unknown"
+)
+
+// RoleReporter is implemented by the nodes able to tell the role they actually run with, whatever the election says.
+type RoleReporter interface {
+	ActualRole(context.Context) (Role, error)
+}
+
+// Demoter is implemented by the nodes able to step down cleanly, so that they follow the next leader instead of being stonithed.
+type Demoter interface {
+	// CheckCandidate fails unless the candidate is a healthy standby caught up with this node.
+	CheckCandidate(ctx context.Context, candidate Spec) error
+	// Demote stops leading without losing any write, the node is then ready to Follow.
+	Demote(ctx context.Context) error
+}
+
+// Candidate is implemented by the nodes able to tell how far behind they are, so that only the most up to date lead after a failover.
+type Candidate interface {
+	// Metadata returns what the node publishes about itself to the others.
+	Metadata(ctx context.Context) (Metadata, error)
+	// CheckEligibility fails when the node lags too far behind the most up to date of the standbys.
+	CheckEligibility(ctx context.Context, standbys []Spec) error
+}
+
+// Synchronizer is implemented by the nodes able to replicate synchronously to one of their followers.
+type Synchronizer interface {
+	// Synchronous tells whether the synchronous replication is on.
+	Synchronous() bool
+	// Synchronize picks the synchronous standby among the followers and returns its name once it is synchronous, empty
+	// when the node replicates asynchronously for lack of followers.
+	Synchronize(ctx context.Context, followers []Spec) (string, error)
+}
+
+// SlotKeeper is implemented by the nodes keeping the changes their followers have not received yet.
+type SlotKeeper interface {
+	// KeepSlots makes sure each follower has its slot, and drops the slots of the nodes gone for a while.
+	KeepSlots(ctx context.Context, followers []Spec) error
+}
+
+// Initializer is implemented by the nodes whose data belong to a cluster, so that a node never initializes a cluster
+// twice nor leads it with the data of another one.
+type Initializer interface {
+	// CheckInitialized fails unless the node can lead the cluster initialized with the system identifier, which is empty
+	// when the cluster is not initialized yet.
+	CheckInitialized(ctx context.Context, systemID string) error
+	// SystemID returns the system identifier of the data of the node, empty when it has none.
+	SystemID(ctx context.Context) (string, error)
+}
+
+// Cloner is implemented by the nodes able to clone their data from another member than the leader.
+type Cloner interface {
+	// CloneFrom gives the members tagged clonefrom, a new node clones its data from one of them rather than from the leader.
+	CloneFrom(members []Spec)
+}
+
+// Parameters are the cluster-wide settings of the nodes, by name.
+type Parameters map[string]string
+
+// Configurer is implemented by the nodes configured from the cluster-wide configuration.
+type Configurer interface {
+	// Configure applies the parameters, those set before and no longer there are reset.
+	Configure(ctx context.Context, parameters Parameters) error
+	// PendingRestart tells whether some of the parameters wait for a restart to take effect.
+	PendingRestart(ctx context.Context) (bool, error)
+}
+
 // Spec ...
 type Spec struct {
 	ElectionKey string
 	Name        string
 	Address     string
 	Port        int64
+	Tags        Tags
+	Metadata    Metadata
+}
+
+// Tags tell the others how to deal with the node.
+type Tags struct {
+	// Priority delays the campaigns of the node, the lower the sooner.
+	Priority int `json:",omitempty"`
+	// NoFailover keeps the node from ever running for the leadership.
+	NoFailover bool `json:",omitempty"`
+	// NoLoadBalance keeps the node out of the standbys the proxies balance the reads between.
+	NoLoadBalance bool `json:",omitempty"`
+	// CloneFrom marks the node as a source to clone the new members from.
+	CloneFrom bool `json:",omitempty"`
+}
+
+// LoadTags reads the tags from the keys <prefix>_PRIORITY, <prefix>_NOFAILOVER, <prefix>_NOLOADBALANCE and <prefix>_CLONEFROM.
+func LoadTags(prefix string) Tags {
+	config.SetDefault(prefix+"_PRIORITY", 0)
+	tags := Tags{
+		Priority:      config.GetIntOrPanic(prefix + "_PRIORITY"),
+		NoFailover:    config.GetBool(prefix + "_NOFAILOVER"),
+		NoLoadBalance: config.GetBool(prefix + "_NOLOADBALANCE"),
+		CloneFrom:     config.GetBool(prefix + "_CLONEFROM"),
+	}
+	if tags.Priority < 0 {
+		panic(fmt.Sprintf("You must specify the env var %s_PRIORITY to a positive or zero int value.", prefix))
+	}
+	return tags
+}
+
+// Metadata is what a node publishes about itself while it runs.
+type Metadata struct {
+	// ReceivedLSN is the position of the last WAL received by a standby, in bytes.
+	ReceivedLSN int64 `json:",omitempty"`
+	// ReplayedLSN is the position of the last WAL replayed by a standby, in bytes.
+	ReplayedLSN int64 `json:",omitempty"`
+}
+
+// Position is the furthest position the node has reached, received or replayed.
+func (m Metadata) Position() int64 {
+	if m.ReplayedLSN > m.ReceivedLSN {
+		return m.ReplayedLSN
+	}
+	return m.ReceivedLSN
+}
+
+// Lag returns how many bytes the position lags behind the most up to date of the standbys, 0 when none is ahead.
+func (m Metadata) Lag(standbys ...Spec) int64 {
+	var lag int64
+	for _, standby := range standbys {
+		if behind := standby.Metadata.Position() - m.Position(); behind > lag {
+			lag = behind
+		}
+	}
+	return lag
 }
 
 // Marshal ...
@@ -65,6 +65,11 @@ func TestUnmarshal(t *testing.T) {
 			json:        "{\"ElectionKey\":\"key-871\",\"Name\":\"node-785\",\"Address\":\"10.10.12.65\",\"Port\":8989}",
 			expected:    node.Spec{ElectionKey: "key-871", Name: "node-785", Address: "10.10.12.65", Port: 8989},
 		},
+		{
+			description: "with metadata",
+			json:        "{\"ElectionKey\":\"key-872\",\"Name\":\"node-786\",\"Address\":\"10.10.12.66\",\"Port\":5432,\"Metadata\":{\"ReceivedLSN\":1024,\"ReplayedLSN\":512}}",
+			expected:    node.Spec{ElectionKey: "key-872", Name: "node-786", Address: "10.10.12.66", Port: 5432, Metadata: node.Metadata{ReceivedLSN: 1024, ReplayedLSN: 512}},
+		},
 	}
 
 	for _, example := range examples {
@@ -100,3 +105,33 @@ func TestUnmarshal(t *testing.T) {
 
 	}
 }
+
+func TestMetadata_Lag(t *testing.T) {
+	standbys := []node.Spec{
+		{Name: "node-001", Metadata: node.Metadata{ReceivedLSN: 4096, ReplayedLSN: 2048}},
+		{Name: "node-002", Metadata: node.Metadata{ReplayedLSN: 8192}},
+		{Name: "node-003"},
+	}
+
+	examples := []struct {
+		description string
+		metadata    node.Metadata
+		standbys    []node.Spec
+		expected    int64
+	}{
+		{description: "no standby", metadata: node.Metadata{}, standbys: nil, expected: 0},
+		{description: "unknown positions", metadata: node.Metadata{}, standbys: standbys[2:], expected: 0},
+		{description: "the most up to date", metadata: node.Metadata{ReceivedLSN: 9000}, standbys: standbys, expected: 0},
+		{description: "behind the replayed position", metadata: node.Metadata{ReceivedLSN: 4096}, standbys: standbys, expected: 4096},
+		{description: "nothing received", metadata: node.Metadata{}, standbys: standbys, expected: 8192},
+	}
+
+	for _, example := range examples {
+		t.Run(example.description, func(t *testing.T) {
+			if actual := example.metadata.Lag(example.standbys...); actual != example.expected {
+				t.Fatalf("\t\t%s FAIL: Lag, expected <%d>, actual <%d>", testutils.Failed, example.expected, actual)
+			}
+			t.Logf("\t\t%s It must lag behind the most up to date standby.", testutils.Succeed)
+		})
+	}
+}
@@ -3,7 +3,9 @@ package runner
 import (
 	"context"
 	"github/mlyahmed.io/nominee/pkg/election"
+	"github/mlyahmed.io/nominee/pkg/health"
 	"github/mlyahmed.io/nominee/pkg/logger"
+	"github/mlyahmed.io/nominee/pkg/metrics"
 	"github/mlyahmed.io/nominee/pkg/node"
 	"github/mlyahmed.io/nominee/pkg/proxy"
 )
@@ -32,10 +34,20 @@ func (defaultElectorRunner) Run(ctx context.Context, elector election.Elector, n
 	defer elector.Cleanup()
 
 	log.Infof("RunElector: starting...")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := metrics.Serve(ctx); err != nil {
+		log.Errorf("RunElector: %v", err)
+		return err
+	}
 	if err := elector.Run(node); err != nil {
 		log.Errorf("RunElector: %v", err)
 		return err
 	}
+	if err := health.Serve(ctx, elector, node); err != nil {
+		log.Errorf("RunElector: %v", err)
+		return err
+	}
 
 	select {
 	case <-elector.Done():
@@ -51,6 +63,12 @@ func (defaultObserverRunner) Run(ctx context.Context, observer election.Observer
 	log := logger.G(ctx)
 	defer observer.Cleanup()
 	log.Infof("RunObserver: starting...")
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := metrics.Serve(ctx); err != nil {
+		log.Errorf("RunObserver: %v", err)
+		return err
+	}
 	if err := observer.Observe(proxy); err != nil {
 		log.Errorf("RunObserver: %v", err)
 		return err
@@ -3,6 +3,7 @@ package stonither
 import (
 	"context"
 	basepkg "github/mlyahmed.io/nominee/pkg/base"
+	"github/mlyahmed.io/nominee/pkg/metrics"
 	"os"
 	"os/signal"
 )
@@ -37,6 +38,7 @@ func (base *Basic) setUpSignals() {
 	signal.Notify(listener, ShutdownSignals...)
 	go func() {
 		<-listener
+		metrics.Stonith(metrics.Signal)
 		base.Stonith(context.TODO())
 		<-listener
 		os.Exit(1)
@@ -0,0 +1,131 @@
+package watchdog
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github/mlyahmed.io/nominee/pkg/logger"
+	"os"
+	"sync"
+	"time"
+)
+
+// Watchdog resets the host unless it is fed in time, so that a primary which has lost its session cannot survive a stuck process.
+type Watchdog interface {
+	// Arm starts the watchdog and feeds it as long as alive tells it.
+	Arm(alive func() bool) error
+	// Disarm stops the watchdog cleanly.
+	Disarm() error
+}
+
+// Clock ticks the feeding of the watchdog.
+type Clock interface {
+	// Every ticks every interval until stop is called.
+	Every(interval time.Duration) (ticks <-chan time.Time, stop func())
+}
+
+type realClock struct{}
+
+func (realClock) Every(interval time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// magicClose tells the driver the watchdog is disarmed on purpose before the device is closed.
+const magicClose = "V"
+
+// Device is a watchdog device such as /dev/watchdog, backed by the softdog module when there is no hardware one.
+type Device struct {
+	Path       string
+	Timeout    time.Duration
+	Interval   time.Duration
+	Clock      Clock
+	SetTimeout func(file *os.File, seconds int) error
+	mutex      *sync.Mutex
+	file       *os.File
+	stop       func()
+	done       chan struct{}
+}
+
+var log *logrus.Entry
+
+// NewDevice makes a device which resets the host after the timeout, it is fed twice per timeout.
+func NewDevice(path string, timeout time.Duration) *Device {
+	log = logger.G(context.Background()).WithFields(logrus.Fields{"watchdog": path})
+	return &Device{
+		Path:       path,
+		Timeout:    timeout,
+		Interval:   timeout / 2,
+		Clock:      realClock{},
+		SetTimeout: setTimeout,
+		mutex:      &sync.Mutex{},
+	}
+}
+
+// Arm opens the device, which starts the countdown, sets its timeout then feeds it every interval while alive tells it.
+func (d *Device) Arm(alive func() bool) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.file != nil {
+		return nil
+	}
+
+	file, err := os.OpenFile(d.Path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err := d.SetTimeout(file, int(d.Timeout/time.Second)); err != nil {
+		_, _ = file.Write([]byte(magicClose))
+		_ = file.Close()
+		return err
+	}
+	if _, err := file.Write([]byte{0}); err != nil {
+		_ = file.Close()
+		return err
+	}
+	log.Infof("armed with a timeout of %v.", d.Timeout)
+
+	ticks, stop := d.Clock.Every(d.Interval)
+	done := make(chan struct{})
+	d.file, d.stop, d.done = file, stop, done
+	go func() {
+		for {
+			select {
+			case <-ticks:
+			case <-done:
+				return
+			}
+			if !alive() {
+				log.Warnf("no longer fed, the host resets unless disarmed.")
+				continue
+			}
+			d.mutex.Lock()
+			if d.file != nil {
+				if _, err := d.file.Write([]byte{0}); err != nil {
+					log.Errorf("failed to feed: %v", err)
+				}
+			}
+			d.mutex.Unlock()
+		}
+	}()
+	return nil
+}
+
+// Disarm stops the feeding then closes the device with the magic character, so that the host does not reset.
+func (d *Device) Disarm() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.file == nil {
+		return nil
+	}
+
+	d.stop()
+	close(d.done)
+	file := d.file
+	d.file = nil
+	if _, err := file.Write([]byte(magicClose)); err != nil {
+		_ = file.Close()
+		return err
+	}
+	log.Infof("disarmed.")
+	return file.Close()
+}
@@ -0,0 +1,19 @@
+package watchdog
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// wdiocSetTimeout is WDIOC_SETTIMEOUT from linux/watchdog.h.
+const wdiocSetTimeout = 0xc0045706
+
+// setTimeout asks the driver to reset the host after the given seconds without being fed.
+func setTimeout(file *os.File, seconds int) error {
+	timeout := int32(seconds)
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), wdiocSetTimeout, uintptr(unsafe.Pointer(&timeout))); errno != 0 {
+		return errno
+	}
+	return nil
+}
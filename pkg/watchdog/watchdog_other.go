@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package watchdog
+
+import (
+	"errors"
+	"os"
+)
+
+func setTimeout(*os.File, int) error {
+	return errors.New("watchdog: not supported on this platform")
+}
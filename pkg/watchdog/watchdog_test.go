@@ -0,0 +1,192 @@
+package watchdog_test
+
+import (
+	"errors"
+	"github/mlyahmed.io/nominee/pkg/testutils"
+	"github/mlyahmed.io/nominee/pkg/watchdog"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock ticks when the test tells it.
+type fakeClock struct {
+	ticks   chan time.Time
+	stopped int32
+}
+
+func (c *fakeClock) Every(time.Duration) (<-chan time.Time, func()) {
+	return c.ticks, func() { atomic.StoreInt32(&c.stopped, 1) }
+}
+
+func (c *fakeClock) tick() {
+	c.ticks <- time.Now()
+	time.Sleep(10 * time.Millisecond)
+}
+
+// fakeTimeout records the timeout set on the device.
+type fakeTimeout struct {
+	seconds int
+	err     error
+}
+
+func (f *fakeTimeout) set(_ *os.File, seconds int) error {
+	f.seconds = seconds
+	return f.err
+}
+
+func newFakeDevice(t *testing.T) (*watchdog.Device, *fakeClock) {
+	dir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatalf("\t\t%s FATAL: TempDir, error %v", testutils.Failed, err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	file := path.Join(dir, "watchdog")
+	if err := ioutil.WriteFile(file, nil, 0600); err != nil {
+		t.Fatalf("\t\t%s FATAL: WriteFile, error %v", testutils.Failed, err)
+	}
+
+	clock := &fakeClock{ticks: make(chan time.Time)}
+	device := watchdog.NewDevice(file, 4*time.Second)
+	device.Clock = clock
+	device.SetTimeout = (&fakeTimeout{}).set
+	return device, clock
+}
+
+func written(t *testing.T, device *watchdog.Device) string {
+	content, err := ioutil.ReadFile(device.Path)
+	if err != nil {
+		t.Fatalf("\t\t%s FATAL: ReadFile, error %v", testutils.Failed, err)
+	}
+	return string(content)
+}
+
+func TestDevice_when_armed_then_feed_it_while_alive(t *testing.T) {
+	t.Logf("Given a watchdog device")
+	{
+		device, clock := newFakeDevice(t)
+		alive := int32(1)
+
+		t.Logf("\tWhen it is armed")
+		{
+			if err := device.Arm(func() bool { return atomic.LoadInt32(&alive) == 1 }); err != nil {
+				t.Fatalf("\t\t%s FAIL: Arm, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if actual := written(t, device); actual != "\x00" {
+				t.Fatalf("\t\t%s FAIL: device, expected <%q> but actual is <%q>", testutils.Failed, "\x00", actual)
+			}
+			t.Logf("\t\t%s Then it must be fed right away.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the clock ticks while alive")
+		{
+			clock.tick()
+			clock.tick()
+			if actual := written(t, device); actual != "\x00\x00\x00" {
+				t.Fatalf("\t\t%s FAIL: device, expected <%q> but actual is <%q>", testutils.Failed, "\x00\x00\x00", actual)
+			}
+			t.Logf("\t\t%s Then it must be fed at each tick.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen the clock ticks after the session is lost")
+		{
+			atomic.StoreInt32(&alive, 0)
+			clock.tick()
+			if actual := written(t, device); actual != "\x00\x00\x00" {
+				t.Fatalf("\t\t%s FAIL: device, expected <%q> but actual is <%q>", testutils.Failed, "\x00\x00\x00", actual)
+			}
+			t.Logf("\t\t%s Then it must not be fed anymore.", testutils.Succeed)
+		}
+	}
+}
+
+func TestDevice_when_disarmed_then_close_it_with_the_magic_character(t *testing.T) {
+	t.Logf("Given an armed watchdog device")
+	{
+		device, clock := newFakeDevice(t)
+		_ = device.Arm(func() bool { return true })
+
+		t.Logf("\tWhen it is disarmed")
+		{
+			if err := device.Disarm(); err != nil {
+				t.Fatalf("\t\t%s FAIL: Disarm, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if actual := written(t, device); actual != "\x00V" {
+				t.Fatalf("\t\t%s FAIL: device, expected <%q> but actual is <%q>", testutils.Failed, "\x00V", actual)
+			}
+			if atomic.LoadInt32(&clock.stopped) != 1 {
+				t.Fatalf("\t\t%s FAIL: clock, expected to be stopped but actual is not", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must be closed with the magic character and no longer fed.", testutils.Succeed)
+		}
+
+		t.Logf("\tWhen it is disarmed again")
+		{
+			if err := device.Disarm(); err != nil {
+				t.Fatalf("\t\t%s FAIL: Disarm, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			t.Logf("\t\t%s Then nothing must happen.", testutils.Succeed)
+		}
+	}
+}
+
+func TestDevice_when_the_device_does_not_exist_then_fail_to_arm(t *testing.T) {
+	t.Logf("Given a missing watchdog device")
+	{
+		device := watchdog.NewDevice("/nonexistent/watchdog", 4*time.Second)
+
+		t.Logf("\tWhen it is armed")
+		{
+			if err := device.Arm(func() bool { return true }); err == nil {
+				t.Fatalf("\t\t%s FAIL: Arm, expected an error but actual is none", testutils.Failed)
+			}
+			t.Logf("\t\t%s Then it must fail.", testutils.Succeed)
+		}
+	}
+}
+
+func TestDevice_when_armed_then_set_its_timeout(t *testing.T) {
+	t.Logf("Given a watchdog device with a timeout of 4s")
+	{
+		device, _ := newFakeDevice(t)
+		timeout := &fakeTimeout{}
+		device.SetTimeout = timeout.set
+
+		if device.Interval != 2*time.Second {
+			t.Fatalf("\t\t%s FAIL: Interval, expected <%v> but actual is <%v>", testutils.Failed, 2*time.Second, device.Interval)
+		}
+
+		t.Logf("\tWhen it is armed")
+		{
+			if err := device.Arm(func() bool { return true }); err != nil {
+				t.Fatalf("\t\t%s FAIL: Arm, expected no error but actual is <%v>", testutils.Failed, err)
+			}
+			if timeout.seconds != 4 {
+				t.Fatalf("\t\t%s FAIL: timeout, expected <%d> but actual is <%d>", testutils.Failed, 4, timeout.seconds)
+			}
+			t.Logf("\t\t%s Then its timeout must be set.", testutils.Succeed)
+		}
+	}
+}
+
+func TestDevice_when_the_timeout_cannot_be_set_then_fail_to_arm(t *testing.T) {
+	t.Logf("Given a watchdog device refusing the timeout")
+	{
+		device, _ := newFakeDevice(t)
+		device.SetTimeout = (&fakeTimeout{err: errors.New("inappropriate ioctl")}).set
+
+		t.Logf("\tWhen it is armed")
+		{
+			if err := device.Arm(func() bool { return true }); err == nil {
+				t.Fatalf("\t\t%s FAIL: Arm, expected an error but actual is none", testutils.Failed)
+			}
+			if actual := written(t, device); actual != "V" {
+				t.Fatalf("\t\t%s FAIL: device, expected <%q> but actual is <%q>", testutils.Failed, "V", actual)
+			}
+			t.Logf("\t\t%s Then it must fail and be closed with the magic character.", testutils.Succeed)
+		}
+	}
+}